@@ -1,9 +1,11 @@
 package main
 
 import (
-	"context"
 	"fmt"
+	"net/url"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/go-retryablehttp"
 	"github.com/rs/zerolog/log"
@@ -13,8 +15,11 @@ import (
 	"github.com/soerenschneider/sc-agent/internal/config"
 	"github.com/soerenschneider/sc-agent/internal/core/ports"
 	"github.com/soerenschneider/sc-agent/internal/domain"
+	"github.com/soerenschneider/sc-agent/internal/domain/git_replication"
 	"github.com/soerenschneider/sc-agent/internal/domain/http_replication"
 	"github.com/soerenschneider/sc-agent/internal/events"
+	"github.com/soerenschneider/sc-agent/internal/schedule"
+	git_replication_svc "github.com/soerenschneider/sc-agent/internal/services/components/git_replication"
 	http_replication_svc "github.com/soerenschneider/sc-agent/internal/services/components/http_replication"
 	"github.com/soerenschneider/sc-agent/internal/services/components/libvirt"
 	"github.com/soerenschneider/sc-agent/internal/services/components/packages"
@@ -83,6 +88,15 @@ func BuildDeps(conf config.Config) (*ports.Components, error) {
 		errs = multierr.Append(errs, err)
 	}
 
+	if len(conf.Vault) > 0 {
+		vaultClients, err := vault.BuildVaultClientsService()
+		if err != nil {
+			errs = multierr.Append(errs, err)
+		} else {
+			ret.Vault = vaultClients
+		}
+	}
+
 	if conf.SecretsReplication != nil && conf.SecretsReplication.Enabled {
 		ret.SecretsReplication, err = vault.BuildSecretReplication(conf.SecretsReplication)
 		if err != nil {
@@ -112,11 +126,20 @@ func BuildDeps(conf config.Config) (*ports.Components, error) {
 	}
 
 	if conf.HttpReplication != nil && conf.HttpReplication.Enabled {
-		ret.HttpReplication, err = buildHttpReplication(*conf.HttpReplication)
+		httpReplication, err := buildHttpReplication(*conf.HttpReplication)
+		if err != nil {
+			errs = multierr.Append(errs, err)
+		} else {
+			ret.HttpReplication = httpReplication
+		}
+	}
+
+	if conf.GitReplication != nil && conf.GitReplication.Enabled {
+		gitReplication, err := buildGitReplication(*conf.GitReplication)
 		if err != nil {
 			errs = multierr.Append(errs, err)
 		} else {
-			go ret.HttpReplication.StartReplication(context.Background())
+			ret.GitReplication = gitReplication
 		}
 	}
 
@@ -127,7 +150,15 @@ func buildHttpReplication(conf config.HttpReplication) (*http_replication_svc.Se
 	items := make([]http_replication.ReplicationItem, 0, len(conf.ReplicationItems))
 
 	for key, val := range conf.ReplicationItems {
-		destStorage, err := buildCertStorage(val.Destinations)
+		var destStorage http_replication_svc.StorageImplementation
+		var err error
+		destinations := val.Destinations
+		if val.Archive != nil {
+			destStorage, err = buildArchiveStorage(val.Source, *val.Archive)
+			destinations = []string{val.Archive.Dest}
+		} else {
+			destStorage, err = buildCertStorage(val.Destinations)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -145,22 +176,205 @@ func buildHttpReplication(conf config.HttpReplication) (*http_replication_svc.Se
 				Test:         val.Validation.Test,
 				Arg:          val.Validation.Arg,
 				InvertResult: val.Validation.InvertResult,
+				SignatureUrl: val.Validation.SignatureUrl,
 			}
 		}
 
-		items = append(items, http_replication.ReplicationItem{
+		schedule, err := buildSchedule(val.Cron, val.Interval)
+		if err != nil {
+			return nil, fmt.Errorf("could not build schedule for http replication item %q: %w", key, err)
+		}
+
+		item := http_replication.ReplicationItem{
 			PostHooks: postHooks,
 			ReplicationConf: http_replication.ReplicationConf{
 				Id:             key,
 				Source:         val.Source,
-				Destinations:   val.Destinations,
+				Destinations:   destinations,
 				FileValidation: fileValidationConf,
+				Schedule:       schedule,
 			},
 			Destination: destStorage,
-		})
+		}
+
+		if val.Auth != nil {
+			if err := buildHttpReplicationAuth(*val.Auth, &item); err != nil {
+				return nil, fmt.Errorf("could not build auth for http replication item %q: %w", key, err)
+			}
+		}
+
+		items = append(items, item)
+	}
+
+	client := *httpClient
+	client.Timeout = http_replication_svc.ClientTimeout
+	return http_replication_svc.New(&client, items)
+}
+
+// buildSchedule returns the schedule of a replication item, which is either defined by a cron expression or an
+// interval. If neither is set, nil is returned and the service's default schedule is used.
+func buildSchedule(cronSpec, interval string) (schedule.Schedule, error) {
+	switch {
+	case cronSpec != "":
+		return schedule.NewCron(cronSpec)
+	case interval != "":
+		parsed, err := time.ParseDuration(interval)
+		if err != nil {
+			return nil, err
+		}
+		return schedule.NewInterval(parsed)
+	default:
+		return nil, nil
+	}
+}
+
+func buildArchiveStorage(source string, conf config.HttpArchive) (*storage.ArchiveStorage, error) {
+	format := conf.Format
+	if format == "" {
+		sourceUrl, err := url.Parse(source)
+		if err != nil {
+			return nil, err
+		}
+		format = storage.ArchiveFormatFromName(sourceUrl.Path)
+		if format == "" {
+			return nil, fmt.Errorf("can not infer archive format from %q, please specify it explicitly", source)
+		}
+	}
+
+	opts := []storage.ArchiveStorageOpt{
+		storage.WithArchiveInclude(conf.Include),
+		storage.WithArchiveExclude(conf.Exclude),
+		storage.WithArchiveStripComponents(conf.StripComponents),
+	}
+	if conf.MaxBytes > 0 {
+		opts = append(opts, storage.WithArchiveMaxBytes(conf.MaxBytes))
+	}
+	if conf.MaxFiles > 0 {
+		opts = append(opts, storage.WithArchiveMaxFiles(conf.MaxFiles))
+	}
+
+	return storage.NewArchiveStorage(conf.Dest, format, opts...)
+}
+
+func buildHttpReplicationAuth(conf config.HttpAuth, item *http_replication.ReplicationItem) error {
+	var tokenSource http_replication_svc.TokenSource
+	var err error
+	switch {
+	case conf.BearerTokenFile != "":
+		tokenSource, err = http_replication_svc.NewFileTokenSource(conf.BearerTokenFile)
+	case conf.BearerTokenVault != nil:
+		tokenSource, err = buildVaultTokenSource(*conf.BearerTokenVault)
+	case conf.BasicAuth != nil:
+		item.Authenticator, err = http_replication_svc.NewBasicAuth(conf.BasicAuth.Username, conf.BasicAuth.PasswordFile)
+	}
+	if err != nil {
+		return err
 	}
 
-	return http_replication_svc.New(httpClient, items)
+	if tokenSource != nil {
+		item.Authenticator, err = http_replication_svc.NewBearerTokenAuth(tokenSource)
+		if err != nil {
+			return err
+		}
+	}
+
+	if conf.Tls != nil {
+		item.Client, err = http_replication_svc.NewMtlsClient(conf.Tls.CertFile, conf.Tls.KeyFile, conf.Tls.CaFile)
+	}
+	return err
+}
+
+func buildVaultTokenSource(conf config.VaultSecretRef) (http_replication_svc.TokenSource, error) {
+	kv2Client, err := vault.GetKv2Client(conf.VaultId, conf.Kv2Mount)
+	if err != nil {
+		return nil, err
+	}
+
+	return http_replication_svc.NewVaultTokenSource(kv2Client, conf.SecretPath, conf.Key)
+}
+
+const defaultGitWorkDir = "/var/lib/sc-agent/git"
+
+func buildGitReplication(conf config.GitReplication) (*git_replication_svc.Service, error) {
+	items := make([]git_replication.ReplicationItem, 0, len(conf.ReplicationItems))
+
+	for key, val := range conf.ReplicationItems {
+		paths := make([]git_replication.PathMapping, 0, len(val.Paths))
+		for _, path := range val.Paths {
+			mapping := git_replication.PathMapping{
+				Source:       path.Src,
+				Destinations: path.Dest,
+			}
+
+			var err error
+			if path.DestDir != "" {
+				mapping.IsDir = true
+				mapping.Destinations = []string{path.DestDir}
+				// the directory is written as tree, the archive format is irrelevant
+				mapping.Destination, err = storage.NewArchiveStorage(path.DestDir, storage.ArchiveFormatTarGz)
+			} else {
+				mapping.Destination, err = storage.NewMultiFilesystemStorage(path.Dest...)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("could not build destination for git replication item %q: %w", key, err)
+			}
+			paths = append(paths, mapping)
+		}
+
+		postHooks := make([]domain.PostHook, 0, len(val.PostHooks))
+		for key, hook := range val.PostHooks {
+			postHooks = append(postHooks, domain.PostHook{
+				Name: key,
+				Cmd:  hook,
+			})
+		}
+
+		schedule, err := buildSchedule(val.Cron, val.Interval)
+		if err != nil {
+			return nil, fmt.Errorf("could not build schedule for git replication item %q: %w", key, err)
+		}
+
+		workDir := val.WorkDir
+		if workDir == "" {
+			workDir = filepath.Join(defaultGitWorkDir, key)
+		}
+
+		item := git_replication.ReplicationItem{
+			PostHooks: postHooks,
+			ReplicationConf: git_replication.ReplicationConf{
+				Id:          key,
+				Repository:  val.Repository,
+				Branch:      val.Branch,
+				Tag:         val.Tag,
+				WorkDir:     workDir,
+				KeyringFile: val.KeyringFile,
+				Paths:       paths,
+				Schedule:    schedule,
+			},
+		}
+
+		if val.Auth != nil {
+			item.Auth, err = buildGitReplicationAuth(*val.Auth)
+			if err != nil {
+				return nil, fmt.Errorf("could not build auth for git replication item %q: %w", key, err)
+			}
+		}
+
+		items = append(items, item)
+	}
+
+	return git_replication_svc.New(items)
+}
+
+func buildGitReplicationAuth(conf config.GitAuth) (git_replication.AuthProvider, error) {
+	switch {
+	case conf.SshKeyFile != "":
+		return git_replication_svc.NewSshKeyAuth(conf.Username, conf.SshKeyFile, conf.SshCertFile, conf.KnownHostsFile)
+	case conf.PasswordFile != "":
+		return git_replication_svc.NewBasicAuth(conf.Username, conf.PasswordFile)
+	default:
+		return nil, nil
+	}
 }
 
 func buildRebootManager(config config.Config) (ports.RebootManager, error) {
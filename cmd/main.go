@@ -115,7 +115,7 @@ func main() {
 		}()
 	}
 
-	services.StartServices(ctx, *conf, scAgentFatalErrors)
+	services.StartServices(ctx, wg, *conf)
 
 	// Handle graceful exit
 	sigc := make(chan os.Signal, 1)
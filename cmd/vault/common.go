@@ -3,7 +3,11 @@ package vault
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/url"
+	"strings"
 	"sync"
+	"time"
 
 	vault "github.com/hashicorp/vault/api"
 	"github.com/rs/zerolog/log"
@@ -11,6 +15,7 @@ import (
 	vault_config "github.com/soerenschneider/sc-agent/internal/config/vault"
 	"github.com/soerenschneider/sc-agent/internal/services/components/vault_common"
 	"github.com/soerenschneider/sc-agent/internal/services/components/vault_common/auth"
+	"github.com/soerenschneider/sc-agent/internal/storage"
 	pkg_vault "github.com/soerenschneider/sc-agent/pkg/vault"
 	"go.uber.org/multierr"
 )
@@ -32,10 +37,39 @@ func BuildVaultClients(conf config.Config) error {
 		}
 	}
 
+	storage.SetTransitEngineResolver(getTransitEngine)
 	return errs
 }
 
+// getTransitEngine returns the transit engine of the given client. If no client is given, the only configured client
+// is used.
+func getTransitEngine(vaultId, mount string) (storage.TransitEngine, error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if vaultId == "" {
+		if len(clients) != 1 {
+			return nil, fmt.Errorf("%d vault clients configured, parameter %q is required", len(clients), storage.ParamTransitVault)
+		}
+		for key := range clients {
+			vaultId = key
+		}
+	}
+
+	client, found := clients[vaultId]
+	if !found {
+		return nil, fmt.Errorf("vault client %q not found", vaultId)
+	}
+
+	return vault_common.NewTransit(client.Client(), mount)
+}
+
 func getOpts(conf vault_config.Vault) ([]vault_common.ApproleSecretIdRotationOpts, error) {
+	address := conf.Address
+	if endpoints := conf.Endpoints(); len(endpoints) > 0 {
+		address = endpoints[0]
+	}
+
 	var opts []vault_common.ApproleSecretIdRotationOpts
 	if conf.ApproleCidrLoginResolver != nil {
 		if conf.ApproleCidrLoginResolver.Type == "static" {
@@ -46,7 +80,7 @@ func getOpts(conf vault_config.Vault) ([]vault_common.ApproleSecretIdRotationOpt
 			opts = append(opts, vault_common.WithStaticCidrResolver(cidrs))
 		}
 		if conf.ApproleCidrLoginResolver.Type == "dynamic" {
-			opts = append(opts, vault_common.WithDynamicCidrResolver(conf.Address))
+			opts = append(opts, vault_common.WithDynamicCidrResolver(address))
 		}
 	}
 
@@ -59,7 +93,7 @@ func getOpts(conf vault_config.Vault) ([]vault_common.ApproleSecretIdRotationOpt
 			opts = append(opts, vault_common.WithStaticCidrTokenResolver(cidrs))
 		}
 		if conf.ApproleCidrTokenResolver.Type == "dynamic" {
-			opts = append(opts, vault_common.WithDynamicCidrTokenResolver(conf.Address))
+			opts = append(opts, vault_common.WithDynamicCidrTokenResolver(address))
 		}
 	}
 
@@ -75,11 +109,27 @@ func buildVaultClient(clientId string, conf vault_config.Vault) error {
 		return nil
 	}
 
+	endpoints := conf.Endpoints()
 	vaultConf := vault.DefaultConfig()
-	vaultConf.Address = conf.Address
+	if len(endpoints) > 0 {
+		vaultConf.Address = endpoints[0]
+	}
 	vaultConf.MaxRetries = 5
 
-	auth, err := buildVaultAuth(conf)
+	if conf.CaCertFile != "" || conf.ClientCertFile != "" || conf.TlsServerName != "" || conf.TlsInsecure {
+		err := vaultConf.ConfigureTLS(&vault.TLSConfig{
+			CACert:        conf.CaCertFile,
+			ClientCert:    conf.ClientCertFile,
+			ClientKey:     conf.ClientKeyFile,
+			TLSServerName: conf.TlsServerName,
+			Insecure:      conf.TlsInsecure,
+		})
+		if err != nil {
+			return fmt.Errorf("could not configure tls for vault client %q: %w", clientId, err)
+		}
+	}
+
+	authMethod, err := buildVaultAuth(conf)
 	if err != nil {
 		return err
 	}
@@ -89,7 +139,48 @@ func buildVaultClient(clientId string, conf vault_config.Vault) error {
 		return err
 	}
 
-	tokenRenewer, err := vault_common.NewTokenRenewer(vaultClient, auth, clientId)
+	if conf.Namespace != "" {
+		vaultClient.SetNamespace(conf.Namespace)
+	}
+
+	clientOpts := []vault_common.VaultCommonOpt{
+		vault_common.WithName(clientId),
+		vault_common.WithAuthMethodName(conf.AuthMethod),
+	}
+	if len(endpoints) > 1 {
+		var selectorOpts []vault_common.EndpointSelectorOpt
+		if conf.HealthCheckInterval != "" {
+			interval, err := time.ParseDuration(conf.HealthCheckInterval)
+			if err != nil {
+				return err
+			}
+			selectorOpts = append(selectorOpts, vault_common.WithHealthCheckInterval(interval))
+		}
+		selector, err := vault_common.NewEndpointSelector(vaultClient, clientId, endpoints, selectorOpts...)
+		if err != nil {
+			return err
+		}
+		clientOpts = append(clientOpts, vault_common.WithEndpointSelector(selector))
+	}
+
+	var renewerOpts []vault_common.TokenRenewerOpt
+	if len(conf.TokenSinks) > 0 {
+		sinks, err := buildTokenSinks(conf.TokenSinks)
+		if err != nil {
+			return err
+		}
+		renewerOpts = append(renewerOpts, vault_common.WithTokenSinks(sinks...))
+	}
+
+	if conf.TokenCache != "" {
+		cache, err := buildTokenCache(conf.TokenCache)
+		if err != nil {
+			return fmt.Errorf("could not build token cache for vault client %q: %w", clientId, err)
+		}
+		renewerOpts = append(renewerOpts, vault_common.WithTokenCache(cache))
+	}
+
+	tokenRenewer, err := vault_common.NewTokenRenewer(vaultClient, authMethod, clientId, renewerOpts...)
 	if err != nil {
 		return err
 	}
@@ -100,17 +191,27 @@ func buildVaultClient(clientId string, conf vault_config.Vault) error {
 		if err != nil {
 			return err
 		}
+		if conf.SecretIdWrapTtl != "" {
+			vaultClient.SetWrappingLookupFunc(vault_common.SecretIdWrappingLookup(conf.MountApprole, conf.SecretIdWrapTtl))
+		}
 		approleClient, err := vault_common.NewClient(vaultClient.Logical(), conf.MountApprole, opts...)
 		if err != nil {
 			return err
 		}
-		secretIdRotator, err = vault_common.NewApproleUpdater(approleClient, clientId, &conf)
+		var rotatorOpts []vault_common.ApproleSecretIdRotationOption
+		if approleAuth, ok := authMethod.(*auth.AppRoleAuth); ok {
+			// the login unwraps wrapped secret_ids, the rotation must not consume the wrapping token itself
+			rotatorOpts = append(rotatorOpts, vault_common.WithSecretIdUnwrapper(func() (string, error) {
+				return approleAuth.SecretID(context.Background(), vaultClient)
+			}))
+		}
+		secretIdRotator, err = vault_common.NewApproleUpdater(approleClient, clientId, &conf, rotatorOpts...)
 		if err != nil {
 			return err
 		}
 	}
 
-	client, err := vault_common.NewVaultClient(auth, vaultClient, tokenRenewer, secretIdRotator)
+	client, err := vault_common.NewVaultClient(authMethod, vaultClient, tokenRenewer, secretIdRotator, clientOpts...)
 	if err != nil {
 		return err
 	}
@@ -119,13 +220,91 @@ func buildVaultClient(clientId string, conf vault_config.Vault) error {
 	return nil
 }
 
-func StartTokenRenewal(ctx context.Context, wg *sync.WaitGroup, vaultFatalError chan error) {
+func buildTokenSinks(conf []vault_config.VaultTokenSink) ([]*vault_common.TokenSink, error) {
+	var sinks []*vault_common.TokenSink
+	for _, sinkConf := range conf {
+		dest, err := storage.NewFilesystemStorageFromUri(sinkConf.Dest)
+		if err != nil {
+			return nil, fmt.Errorf("could not build token sink %q: %w", sinkConf.Dest, err)
+		}
+
+		var opts []vault_common.TokenSinkOpt
+		if len(sinkConf.Policies) > 0 || sinkConf.Ttl != "" {
+			opts = append(opts, vault_common.WithChildToken(sinkConf.Policies, sinkConf.Ttl))
+		}
+		if sinkConf.WrapTtl != "" {
+			opts = append(opts, vault_common.WithWrapTtl(sinkConf.WrapTtl))
+		}
+
+		sink, err := vault_common.NewTokenSink(dest.FilePath, dest, opts...)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+
+	return sinks, nil
+}
+
+// tokenCacheKeyring is the only keyring that persists the cached token across restarts of the agent.
+const tokenCacheKeyring = "user"
+
+// buildTokenCache builds the storage the token is persisted to. Tokens are never cached in plaintext files.
+func buildTokenCache(uri string) (vault_common.TokenCacheStorage, error) {
+	if strings.HasPrefix(uri, storage.KeyringScheme+"://") {
+		cache, err := storage.NewKeyringStorageFromUri(uri)
+		if err != nil {
+			return nil, err
+		}
+		// the process, thread and session keyrings are gone after a restart of the agent
+		if cache.Keyring != tokenCacheKeyring {
+			return nil, fmt.Errorf("token cache must use the %q keyring, got %q", tokenCacheKeyring, cache.Keyring)
+		}
+		return cache, nil
+	}
+
+	// decrypting the token using transit would require the very token that is cached
+	if parsed, err := url.Parse(uri); err == nil && parsed.Query().Has(storage.ParamTransitKey) {
+		return nil, errors.New("token cache can not be encrypted using transit")
+	}
+
+	cache, err := storage.NewFilesystemStorageFromUri(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	if !cache.IsEncrypted() {
+		return nil, fmt.Errorf("token cache file %q must be encrypted, e.g. using the %q parameter", cache.FilePath, storage.ParamAgeKeyFile)
+	}
+
+	return cache, nil
+}
+
+// BuildVaultClientsService returns a service that reports the status of all configured Vault clients.
+func BuildVaultClientsService() (*vault_common.Clients, error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	return vault_common.NewClients(clients)
+}
+
+// StartEndpointSelection selects the healthiest endpoint for all clients that are configured with multiple addresses.
+func StartEndpointSelection(ctx context.Context) {
+	for key := range clients {
+		clients[key].StartEndpointSelection(ctx)
+	}
+}
+
+// StartTokenRenewal logs in all clients and keeps their tokens renewed. Each client logs in independently, it does
+// not block until the logins have succeeded.
+func StartTokenRenewal(ctx context.Context) {
 	for key := range clients {
 		client := clients[key]
-		go client.StartTokenRenewer(ctx, wg, vaultFatalError)
+		go client.StartTokenRenewer(ctx)
 	}
 }
 
+// StartApproleSecretIdRotation starts the secret_id rotation of each client as soon as it has logged in.
 func StartApproleSecretIdRotation(ctx context.Context) {
 	for key := range clients {
 		client := clients[key]
@@ -133,32 +312,109 @@ func StartApproleSecretIdRotation(ctx context.Context) {
 	}
 }
 
+// WaitForLogin blocks until all given clients have logged in successfully. Unknown clients are ignored. It returns
+// false if the context is canceled before.
+func WaitForLogin(ctx context.Context, vaultIds ...string) bool {
+	for _, vaultId := range vaultIds {
+		client := getVaultClient(vaultId)
+		if client == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-client.Ready():
+		}
+	}
+
+	return true
+}
+
 func buildVaultAuth(conf vault_config.Vault) (vault.AuthMethod, error) {
 	switch conf.AuthMethod {
 	case "token":
 		return auth.NewTokenAuth(conf.Token)
 
 	case "approle":
-		secretId := &auth.SecretID{
-			FromFile: conf.SecretIdFile,
+		return buildApproleAuth(conf)
+
+	case "cert":
+		loginOpts := []auth.CertLoginOption{
+			auth.WithCertMountPath(conf.MountCert),
+		}
+		if conf.CertRole != "" {
+			loginOpts = append(loginOpts, auth.WithCertRole(conf.CertRole))
 		}
 
-		var loginOpts []auth.LoginOption
-		if conf.MountApprole != "" {
-			loginOpts = append(loginOpts, auth.WithMountPath(conf.MountApprole))
+		if conf.RoleId != "" {
+			log.Info().Msg("Using approle to bootstrap cert auth")
+			bootstrap, err := buildApproleAuth(conf)
+			if err != nil {
+				return nil, err
+			}
+			loginOpts = append(loginOpts, auth.WithBootstrapAuth(bootstrap))
 		}
 
-		isWrappedToken, err := pkg_vault.ContainsFileWrappedToken(conf.SecretIdFile)
+		return auth.NewCertAuth(conf.CertFile, conf.KeyFile, loginOpts...)
+
+	case "kubernetes":
+		return auth.NewKubernetesAuth(conf.KubernetesRole, conf.KubernetesTokenFile, auth.WithJwtMountPath(conf.MountKubernetes))
+
+	case "jwt":
+		var source auth.JwtSource
+		var err error
+		switch {
+		case conf.JwtFile != "":
+			source, err = auth.NewJwtFile(conf.JwtFile)
+		case conf.JwtUrl != "":
+			source, err = auth.NewJwtEndpoint(conf.JwtUrl, conf.JwtUrlHeaders)
+		default:
+			return nil, errors.New("either jwt_file or jwt_url must be set for jwt auth")
+		}
 		if err != nil {
 			return nil, err
 		}
-		if isWrappedToken {
-			log.Info().Msg("Trying to authenticate using wrapped secret_id token")
-			loginOpts = append(loginOpts, auth.WithWrappingToken())
-		}
 
-		return auth.NewAppRoleAuth(conf.RoleId, secretId, loginOpts...)
+		return auth.NewJwtAuth(conf.JwtRole, source, auth.WithJwtMountPath(conf.MountJwt))
 	default:
 		return nil, errors.New("unknown auth module requested")
 	}
 }
+
+func buildApproleAuth(conf vault_config.Vault) (*auth.AppRoleAuth, error) {
+	secretId := &auth.SecretID{
+		FromFile: conf.SecretIdFile,
+	}
+
+	var loginOpts []auth.LoginOption
+	if conf.MountApprole != "" {
+		loginOpts = append(loginOpts, auth.WithMountPath(conf.MountApprole))
+	}
+
+	// the secret_id rotation writes wrapped secret_ids if a wrap ttl is configured
+	unwrap := conf.SecretIdWrapped || conf.SecretIdWrapTtl != ""
+	if !conf.SecretIdWrapped {
+		isWrappedToken, err := pkg_vault.ContainsFileWrappedToken(conf.SecretIdFile)
+		if err != nil {
+			return nil, err
+		}
+		unwrap = unwrap || isWrappedToken
+	}
+	if unwrap {
+		log.Info().Msg("Unwrapping of wrapped secret_id tokens enabled")
+		loginOpts = append(loginOpts, auth.WithWrappingToken())
+	}
+
+	return auth.NewAppRoleAuth(conf.RoleId, secretId, loginOpts...)
+}
+
+// GetKv2Client returns a client for the kv2 secrets engine mounted at mount using the vault client with the given id.
+func GetKv2Client(vaultId, mount string) (*vault.KVv2, error) {
+	client := getVaultClient(vaultId)
+	if client == nil {
+		return nil, fmt.Errorf("vault client %q not found", vaultId)
+	}
+
+	return client.Client().KVv2(mount), nil
+}
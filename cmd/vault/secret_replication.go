@@ -3,11 +3,16 @@ package vault
 import (
 	"errors"
 	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/soerenschneider/sc-agent/internal/config/vault"
 	domain "github.com/soerenschneider/sc-agent/internal/domain/secret_replication"
 	"github.com/soerenschneider/sc-agent/internal/services/components/secret_replication"
 	"github.com/soerenschneider/sc-agent/internal/services/components/secret_replication/formatter"
+	"github.com/soerenschneider/sc-agent/internal/services/components/vault_common"
 	"github.com/soerenschneider/sc-agent/internal/storage"
 	"github.com/soerenschneider/sc-agent/pkg"
 )
@@ -19,8 +24,23 @@ const (
 	vaultSecretSyncerFormatterTemplateOptionTemplateFile = "file"
 	vaultSecretSyncerFormatterEnvKey                     = "env"
 	vaultSecretSyncerFormatterEnvOptionUppercaseKeys     = "uppercase_keys"
+	vaultSecretSyncerFormatterDotenvKey                  = "dotenv"
+	vaultSecretSyncerFormatterPropertiesKey              = "properties"
+	vaultSecretSyncerFormatterIniKey                     = "ini"
+	vaultSecretSyncerFormatterTomlKey                    = "toml"
+	vaultSecretSyncerFormatterNetrcKey                   = "netrc"
+	vaultSecretSyncerFormatterNetrcOptionMachine         = "machine"
+	vaultSecretSyncerFormatterNetrcOptionLoginKey        = "login_key"
+	vaultSecretSyncerFormatterNetrcOptionPasswordKey     = "password_key"
+	vaultSecretSyncerFormatterPgpassKey                  = "pgpass"
+	vaultSecretSyncerFormatterPgpassOptionHost           = "host"
+	vaultSecretSyncerFormatterPgpassOptionPort           = "port"
+	vaultSecretSyncerFormatterPgpassOptionDatabase       = "database"
+	vaultSecretSyncerFormatterPgpassOptionUsername       = "username"
 )
 
+var secretBackendHttpClient = &http.Client{Timeout: 30 * time.Second}
+
 type Formatter interface {
 	Format(data map[string]any) ([]byte, error)
 }
@@ -30,14 +50,15 @@ func BuildSecretReplication(conf *vault.SecretsReplication) (*secret_replication
 		return nil, errors.New("no vaultsyncer config given")
 	}
 
+	// the default Vault client is optional if all items are read from named backends
+	var kv2Client secret_replication.ReplicationClient
 	client := getVaultClient(conf.VaultId)
-	if client == nil {
-		return nil, fmt.Errorf("vault client %q not found", conf.VaultId)
-	}
-
-	kv2Client, err := secret_replication.NewClient(client.Client().KVv2(conf.Kv2Mount))
-	if err != nil {
-		return nil, err
+	if client != nil {
+		var err error
+		kv2Client, err = buildVaultReplicationClient(client, conf.Kv2Mount)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	syncRequests, err := buildSyncSecretRequests(*conf)
@@ -49,7 +70,59 @@ func BuildSecretReplication(conf *vault.SecretsReplication) (*secret_replication
 		return nil, errors.New("secret syncer config provided but no actual secrets to sync defined")
 	}
 
-	return secret_replication.NewService(kv2Client, syncRequests)
+	var opts []secret_replication.SecretsReplicationOpts
+	if conf.VaultEvents {
+		if client == nil {
+			return nil, fmt.Errorf("vault client %q not found", conf.VaultId)
+		}
+		subscriber, err := secret_replication.NewVaultEventSubscriber(client.Client(), conf.Kv2Mount)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, secret_replication.WithEventSubscriber(subscriber))
+	}
+
+	for name, backendConf := range conf.Backends {
+		backend, err := buildSecretBackend(backendConf)
+		if err != nil {
+			return nil, fmt.Errorf("could not build secret backend %q: %w", name, err)
+		}
+		opts = append(opts, secret_replication.WithBackend(name, backend))
+	}
+
+	return secret_replication.NewService(kv2Client, syncRequests, opts...)
+}
+
+func buildVaultReplicationClient(client *vault_common.VaultCommon, mount string) (*secret_replication.VaultKv2Client, error) {
+	return secret_replication.NewClient(client.Client().KVv2(mount), client.Client().Logical(), client.Client().Sys())
+}
+
+func buildSecretBackend(conf vault.SecretBackend) (secret_replication.ReplicationClient, error) {
+	var backend secret_replication.SecretBackend
+	var err error
+	switch conf.Type {
+	case vault.SecretBackendVault:
+		client := getVaultClient(conf.VaultId)
+		if client == nil {
+			return nil, fmt.Errorf("vault client %q not found", conf.VaultId)
+		}
+		return buildVaultReplicationClient(client, conf.Kv2Mount)
+	case vault.SecretBackendSops:
+		backend, err = secret_replication.NewSopsBackend(conf.File)
+	case vault.SecretBackendAge:
+		backend, err = secret_replication.NewAgeBackend(conf.File, conf.IdentityFile)
+	case vault.SecretBackendOnePasswordConnect:
+		backend, err = secret_replication.NewOnePasswordConnectBackend(secretBackendHttpClient, conf.Address, conf.TokenFile)
+	case vault.SecretBackendBitwarden:
+		backend, err = secret_replication.NewBitwardenBackend(secretBackendHttpClient, conf.Address, conf.TokenFile)
+	default:
+		return nil, fmt.Errorf("unknown backend type %q", conf.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return secret_replication.NewBackendClient(backend)
 }
 
 func buildSyncSecretRequests(conf vault.SecretsReplication) ([]domain.ReplicationItem, error) {
@@ -61,19 +134,32 @@ func buildSyncSecretRequests(conf vault.SecretsReplication) ([]domain.Replicatio
 			return nil, fmt.Errorf("could not build formatter for %q: %w", req.Formatter, err)
 		}
 
-		storageImpl, err := storage.NewFilesystemStorageFromUri(req.DestUri)
+		storageImpl, err := buildSecretDestination(req.DestUri)
 		if err != nil {
 			return nil, fmt.Errorf("could not build storage for %q: %w", req.DestUri, err)
 		}
 
+		var leaseIncrement time.Duration
+		if req.LeaseIncrement != "" {
+			leaseIncrement, err = time.ParseDuration(req.LeaseIncrement)
+			if err != nil {
+				return nil, fmt.Errorf("could not parse lease increment for %q: %w", id, err)
+			}
+		}
+
 		request := domain.ReplicationItem{
 			ReplicationConf: domain.ReplicationConf{
-				Id:         id,
-				SecretPath: req.SecretPath,
-				DestUri:    req.DestUri,
+				Id:             id,
+				SecretPath:     req.SecretPath,
+				DestUri:        req.DestUri,
+				Backend:        req.Backend,
+				Dynamic:        req.Dynamic,
+				LeaseIncrement: leaseIncrement,
+				OnDelete:       domain.OnDeletePolicy(req.OnDelete),
 			},
 			Formatter:   formatter,
 			Destination: storageImpl,
+			PostHooks:   req.GetPostHooks(),
 		}
 
 		ret = append(ret, request)
@@ -82,9 +168,20 @@ func buildSyncSecretRequests(conf vault.SecretsReplication) ([]domain.Replicatio
 	return ret, nil
 }
 
+func buildSecretDestination(uri string) (domain.StorageImplementation, error) {
+	switch {
+	case strings.HasPrefix(uri, storage.SystemdCredsScheme+"://"):
+		return storage.NewSystemdCredsStorageFromUri(uri)
+	case strings.HasPrefix(uri, storage.KeyringScheme+"://"):
+		return storage.NewKeyringStorageFromUri(uri)
+	default:
+		return storage.NewFilesystemStorageFromUri(uri)
+	}
+}
+
 func buildSecretFormatter(name string, arguments map[string]any) (Formatter, error) {
 	switch name {
-	case vaultSecretSyncerFormatterEnvKey:
+	case vaultSecretSyncerFormatterEnvKey, vaultSecretSyncerFormatterDotenvKey:
 		uppercaseKeys := false
 		if arguments != nil {
 			val, found := arguments[vaultSecretSyncerFormatterEnvOptionUppercaseKeys]
@@ -96,11 +193,48 @@ func buildSecretFormatter(name string, arguments map[string]any) (Formatter, err
 			}
 		}
 
-		return formatter.NewEnvVarFormatter(uppercaseKeys), nil
+		var opts []formatter.EnvVarOpt
+		if name == vaultSecretSyncerFormatterDotenvKey {
+			opts = append(opts, formatter.WithStrictEnvKeys())
+		}
+		return formatter.NewEnvVarFormatter(uppercaseKeys, opts...), nil
 	case vaultSecretSyncerFormatterYamlKey:
 		return &formatter.YamlFormatter{}, nil
 	case vaultSecretSyncerFormatterJsonKey:
 		return &formatter.JsonFormatter{}, nil
+	case vaultSecretSyncerFormatterPropertiesKey:
+		return &formatter.PropertiesFormatter{}, nil
+	case vaultSecretSyncerFormatterIniKey:
+		return &formatter.IniFormatter{}, nil
+	case vaultSecretSyncerFormatterTomlKey:
+		return &formatter.TomlFormatter{}, nil
+	case vaultSecretSyncerFormatterNetrcKey:
+		var opts []formatter.NetrcOpt
+		if val := getStringArgument(arguments, vaultSecretSyncerFormatterNetrcOptionMachine); val != "" {
+			opts = append(opts, formatter.WithNetrcMachine(val))
+		}
+		if val := getStringArgument(arguments, vaultSecretSyncerFormatterNetrcOptionLoginKey); val != "" {
+			opts = append(opts, formatter.WithNetrcLoginKey(val))
+		}
+		if val := getStringArgument(arguments, vaultSecretSyncerFormatterNetrcOptionPasswordKey); val != "" {
+			opts = append(opts, formatter.WithNetrcPasswordKey(val))
+		}
+		return formatter.NewNetrcFormatter(opts...), nil
+	case vaultSecretSyncerFormatterPgpassKey:
+		var opts []formatter.PgpassOpt
+		if val := getStringArgument(arguments, vaultSecretSyncerFormatterPgpassOptionHost); val != "" {
+			opts = append(opts, formatter.WithPgpassHost(val))
+		}
+		if val := getStringArgument(arguments, vaultSecretSyncerFormatterPgpassOptionPort); val != "" {
+			opts = append(opts, formatter.WithPgpassPort(val))
+		}
+		if val := getStringArgument(arguments, vaultSecretSyncerFormatterPgpassOptionDatabase); val != "" {
+			opts = append(opts, formatter.WithPgpassDatabase(val))
+		}
+		if val := getStringArgument(arguments, vaultSecretSyncerFormatterPgpassOptionUsername); val != "" {
+			opts = append(opts, formatter.WithPgpassUsername(val))
+		}
+		return formatter.NewPgpassFormatter(opts...), nil
 	case vaultSecretSyncerFormatterTemplateKey:
 		if arguments == nil {
 			return nil, errors.New("no formatter arguments found")
@@ -119,3 +253,20 @@ func buildSecretFormatter(name string, arguments map[string]any) (Formatter, err
 		return nil, errors.New("no implementation found")
 	}
 }
+
+// getStringArgument returns the formatter argument as string or an empty string if it is not set.
+func getStringArgument(arguments map[string]any, key string) string {
+	val, found := arguments[key]
+	if !found {
+		return ""
+	}
+
+	switch converted := val.(type) {
+	case string:
+		return converted
+	case int:
+		return strconv.Itoa(converted)
+	default:
+		return ""
+	}
+}
@@ -23,6 +23,9 @@ const (
 	FileValidationTestRegex      = "regex"
 	FileValidationTestStartsWith = "starts_with"
 	FileValidationTestEndsWith   = "ends_with"
+	FileValidationTestMinisign   = "minisign"
+	FileValidationTestGpg        = "gpg"
+	FileValidationTestCosign     = "cosign"
 
 	DefaultMqttClientIdPrefix = "sc-agent"
 )
@@ -45,8 +48,10 @@ type Config struct {
 	X509Pki            *vault.X509Pki            `yaml:"x509_pki"`
 	RebootManager      *RebootManagerConfig      `yaml:"reboot_manager"`
 	HttpReplication    *HttpReplication          `yaml:"http_replication"`
+	GitReplication     *GitReplication           `yaml:"git_replication"`
 
-	// VaultLoginTimeout is a duration-formatted string that
+	// VaultLoginTimeout is a duration-formatted string that defines how long Vault-dependent components wait for the
+	// login of their Vault client before they are reported as degraded. They keep waiting for the login afterwards.
 	VaultLoginTimeout string `yaml:"vault_login_timeout" validate:"omitempty,duration"`
 
 	Vault map[string]vault.Vault `yaml:"vault"`
@@ -224,15 +229,92 @@ type HttpReplication struct {
 
 type HttpReplicationItem struct {
 	Source       string            `yaml:"source" validate:"http_url"`
-	Destinations []string          `yaml:"dest" validate:"required"`
+	Destinations []string          `yaml:"dest" validate:"required_without=Archive,excluded_with=Archive"`
 	PostHooks    map[string]string `yaml:"post_hooks"`
 	Validation   *FileValidation   `yaml:"validation"`
+	Auth         *HttpAuth         `yaml:"auth"`
+	// Archive extracts the downloaded tar.gz or zip archive into a directory instead of writing it to files.
+	Archive *HttpArchive `yaml:"archive"`
+	// Interval is the duration-formatted interval the item is replicated at, defaults to 10m.
+	Interval string `yaml:"interval" validate:"omitempty,duration,excluded_with=Cron"`
+	// Cron is a cron expression, e.g. "0 4 * * *", that defines when the item is replicated.
+	Cron string `yaml:"cron" validate:"omitempty,cron"`
+}
+
+// HttpArchive configures the extraction of a replicated archive.
+type HttpArchive struct {
+	// Dest is the path of the symlink that is atomically swapped to point to the directory of the extracted archive.
+	Dest string `yaml:"dest" validate:"required,filepath"`
+	// Format is the format of the archive, it is inferred from the source URL if not set.
+	Format string `yaml:"format" validate:"omitempty,oneof=tar.gz zip"`
+	// Include restricts the extracted entries to the ones matching one of the globs, e.g. "dist/*".
+	Include []string `yaml:"include"`
+	// Exclude skips entries matching one of the globs.
+	Exclude         []string `yaml:"exclude"`
+	StripComponents int      `yaml:"strip_components" validate:"gte=0"`
+	// MaxBytes is the maximum total size of the extracted files, defaults to 1 GiB.
+	MaxBytes int64 `yaml:"max_bytes" validate:"gte=0"`
+	// MaxFiles is the maximum number of entries, defaults to 10000.
+	MaxFiles int `yaml:"max_files" validate:"gte=0"`
 }
 
 type FileValidation struct {
-	Test         string `yaml:"test" validate:"omitempty,oneof=sha256 regex starts_with ends_with"`
+	Test string `yaml:"test" validate:"omitempty,oneof=sha256 regex starts_with ends_with minisign gpg cosign"`
+	// Arg is the value to test against. For the signature tests "minisign", "gpg" and "cosign" it is the public key,
+	// either inline or as absolute path to a file.
 	Arg          string `yaml:"arg" validate:"required_with=Test"`
 	InvertResult bool   `yaml:"invert_result"`
+	// SignatureUrl is the URL of the detached signature. Defaults to the source URL with the suffix ".minisig"
+	// (minisign), ".asc" (gpg) or ".sig" (cosign).
+	SignatureUrl string `yaml:"signature_url" validate:"omitempty,http_url"`
+}
+
+// HttpAuth configures how requests of a HTTP replication item are authenticated.
+type HttpAuth struct {
+	// BearerTokenFile is the file that contains the bearer token.
+	BearerTokenFile string `yaml:"bearer_token_file" validate:"excluded_with=BearerTokenVault BasicAuth"`
+	// BearerTokenVault reads the bearer token from a kv2 secret in Vault.
+	BearerTokenVault *VaultSecretRef `yaml:"bearer_token_vault" validate:"omitempty,excluded_with=BasicAuth"`
+	BasicAuth        *BasicAuth      `yaml:"basic_auth"`
+	// Tls configures a client certificate used for mTLS, e.g. a certificate that is managed by the pki component.
+	Tls *ClientTls `yaml:"tls"`
+}
+
+type VaultSecretRef struct {
+	VaultId    string `yaml:"vault"`
+	Kv2Mount   string `yaml:"kv2_mount"`
+	SecretPath string `yaml:"secret_path" validate:"required"`
+	Key        string `yaml:"key" validate:"required"`
+}
+
+func (conf *VaultSecretRef) UnmarshalYAML(node *yaml.Node) error {
+	type Alias VaultSecretRef // Create an alias to avoid recursion during unmarshalling
+
+	// Define conf temporary struct with default values
+	tmp := &Alias{
+		Kv2Mount: "secret",
+	}
+
+	// Unmarshal the yaml data into the temporary struct
+	if err := node.Decode(&tmp); err != nil {
+		return err
+	}
+
+	// Assign the values from the temporary struct to the original struct
+	*conf = VaultSecretRef(*tmp)
+	return nil
+}
+
+type BasicAuth struct {
+	Username     string `yaml:"username" validate:"required"`
+	PasswordFile string `yaml:"password_file" validate:"required"`
+}
+
+type ClientTls struct {
+	CertFile string `yaml:"cert_file" validate:"required"`
+	KeyFile  string `yaml:"key_file" validate:"required"`
+	// CaFile is an optional file containing the CA certificates used to verify the server, defaults to the system pool.
+	CaFile string `yaml:"ca_file"`
 }
 
 func (conf *HttpReplication) UnmarshalYAML(node *yaml.Node) error {
@@ -253,6 +335,72 @@ func (conf *HttpReplication) UnmarshalYAML(node *yaml.Node) error {
 	return nil
 }
 
+type GitReplication struct {
+	Enabled          bool                          `yaml:"enabled"`
+	ReplicationItems map[string]GitReplicationItem `yaml:"items" validate:"dive,required_if=Enabled true"`
+}
+
+type GitReplicationItem struct {
+	// Repository is the HTTPS or SSH URL of the repository, e.g. "git@github.com:example/dotfiles.git".
+	Repository string `yaml:"repository" validate:"required"`
+	Branch     string `yaml:"branch" validate:"required_without=Tag,excluded_with=Tag"`
+	Tag        string `yaml:"tag"`
+	// WorkDir is the directory the repository is cloned to, defaults to "/var/lib/sc-agent/git/<id>".
+	WorkDir   string            `yaml:"work_dir" validate:"omitempty,filepath"`
+	Auth      *GitAuth          `yaml:"auth"`
+	PostHooks map[string]string `yaml:"post_hooks"`
+	// KeyringFile is a file containing armored OpenPGP public keys. If set, the checked out commit or its signed tag
+	// must be signed by one of the keys.
+	KeyringFile string               `yaml:"verify_signatures_keyring" validate:"omitempty,file"`
+	Paths       []GitReplicationPath `yaml:"paths" validate:"required,min=1,dive"`
+	// Interval is the duration-formatted interval the item is replicated at, defaults to 10m.
+	Interval string `yaml:"interval" validate:"omitempty,duration,excluded_with=Cron"`
+	// Cron is a cron expression, e.g. "0 4 * * *", that defines when the item is replicated.
+	Cron string `yaml:"cron" validate:"omitempty,cron"`
+}
+
+// GitReplicationPath maps a file or directory of the repository to the local system.
+type GitReplicationPath struct {
+	// Src is the path relative to the root of the repository.
+	Src string `yaml:"src" validate:"required"`
+	// Dest are the file URIs a file is written to.
+	Dest []string `yaml:"dest" validate:"required_without=DestDir,excluded_with=DestDir,dive,file_uri"`
+	// DestDir is the path of the symlink that is atomically swapped to point to a copy of the directory Src.
+	DestDir string `yaml:"dest_dir" validate:"omitempty,filepath"`
+}
+
+// GitAuth configures how the remote repository is accessed.
+type GitAuth struct {
+	// SshKeyFile is the private key used for SSH remotes.
+	SshKeyFile string `yaml:"ssh_key_file" validate:"excluded_with=PasswordFile"`
+	// SshCertFile is an optional certificate for the SSH key, e.g. one that is signed by the ssh component.
+	SshCertFile string `yaml:"ssh_cert_file" validate:"omitempty,excluded_without=SshKeyFile"`
+	// KnownHostsFile is used to verify the host key of SSH remotes, defaults to the known_hosts files of the user.
+	KnownHostsFile string `yaml:"known_hosts_file" validate:"omitempty,excluded_without=SshKeyFile"`
+	// Username is the user for SSH remotes (defaults to "git") or HTTPS remotes.
+	Username string `yaml:"username" validate:"required_with=PasswordFile"`
+	// PasswordFile contains the password or access token for HTTPS remotes.
+	PasswordFile string `yaml:"password_file"`
+}
+
+func (conf *GitReplication) UnmarshalYAML(node *yaml.Node) error {
+	type Alias GitReplication // Create an alias to avoid recursion during unmarshalling
+
+	// Define conf temporary struct with default values
+	tmp := &Alias{
+		Enabled: true,
+	}
+
+	// Unmarshal the yaml data into the temporary struct
+	if err := node.Decode(&tmp); err != nil {
+		return err
+	}
+
+	// Assign the values from the temporary struct to the original struct
+	*conf = GitReplication(*tmp)
+	return nil
+}
+
 type Services struct {
 	Enabled bool `yaml:"enabled"`
 	UseSudo bool `yaml:"use_sudo"`
@@ -1,12 +1,17 @@
 package config
 
 import (
+	"fmt"
 	"net/url"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/robfig/cron/v3"
 	"github.com/rs/zerolog/log"
+	"github.com/soerenschneider/sc-agent/internal/config/vault"
 )
 
 var (
@@ -24,9 +29,45 @@ func Validate(s any) error {
 		if err := validate.RegisterValidation("broker", validateBroker); err != nil {
 			log.Fatal().Err(err).Msg("could not build custom validation 'validateBroker'")
 		}
+
+		if err := validate.RegisterValidation("file_uri", validateFileUri); err != nil {
+			log.Fatal().Err(err).Msg("could not build custom validation 'validateFileUri'")
+		}
+
+		if err := validate.RegisterValidation("secret_dest_uri", validateSecretDestUri); err != nil {
+			log.Fatal().Err(err).Msg("could not build custom validation 'validateSecretDestUri'")
+		}
+
+		if err := validate.RegisterValidation("cron", validateCron); err != nil {
+			log.Fatal().Err(err).Msg("could not build custom validation 'validateCron'")
+		}
+
+		if err := validate.RegisterValidation("token_cache_uri", validateTokenCacheUri); err != nil {
+			log.Fatal().Err(err).Msg("could not build custom validation 'validateTokenCacheUri'")
+		}
 	})
 
-	return validate.Struct(s)
+	if err := validate.Struct(s); err != nil {
+		return err
+	}
+
+	if conf, ok := s.(*Config); ok {
+		return validateVaultTokenCaches(conf.Vault)
+	}
+
+	return nil
+}
+
+// validateVaultTokenCaches validates the token caches of the Vault clients. The Vault configs are not validated as a
+// whole, a cache that does not survive a restart would otherwise only be noticed when a new login is required.
+func validateVaultTokenCaches(clients map[string]vault.Vault) error {
+	for id, conf := range clients {
+		if err := validate.Var(conf.TokenCache, "omitempty,token_cache_uri"); err != nil {
+			return fmt.Errorf("invalid token_cache for vault client %q: %w", id, err)
+		}
+	}
+
+	return nil
 }
 
 func validateDuration(fl validator.FieldLevel) bool {
@@ -34,11 +75,108 @@ func validateDuration(fl validator.FieldLevel) bool {
 	return err == nil
 }
 
+func validateCron(fl validator.FieldLevel) bool {
+	_, err := cron.ParseStandard(fl.Field().String())
+	return err == nil
+}
+
 func validateBroker(fl validator.FieldLevel) bool {
 	broker := fl.Field().String()
 	return IsValidMqttUrl(broker)
 }
 
+func validateFileUri(fl validator.FieldLevel) bool {
+	return IsValidFileUri(fl.Field().String())
+}
+
+func validateSecretDestUri(fl validator.FieldLevel) bool {
+	return IsValidSecretDestUri(fl.Field().String())
+}
+
+func validateTokenCacheUri(fl validator.FieldLevel) bool {
+	return IsValidTokenCacheUri(fl.Field().String())
+}
+
+// IsValidTokenCacheUri checks whether the input is a valid storage for the Vault token cache. Besides file URIs, only
+// the user keyring is supported, as the process, thread and session keyrings do not outlive the agent's process.
+func IsValidTokenCacheUri(input string) bool {
+	parsed, err := url.Parse(input)
+	if err != nil {
+		return false
+	}
+
+	if parsed.Scheme == "keyring" {
+		return parsed.Host == "user" && len(strings.TrimPrefix(parsed.Path, "/")) > 0
+	}
+
+	return IsValidFileUri(input)
+}
+
+// IsValidSecretDestUri checks whether the input is a valid destination for a replicated secret. Besides file URIs,
+// "systemd-creds://<name>" and "keyring://<keyring>/<description>" are supported.
+func IsValidSecretDestUri(input string) bool {
+	parsed, err := url.Parse(input)
+	if err != nil {
+		return false
+	}
+
+	switch parsed.Scheme {
+	case "systemd-creds":
+		if len(parsed.Host) == 0 || len(strings.Trim(parsed.Path, "/")) > 0 {
+			return false
+		}
+		dir := parsed.Query().Get("dir")
+		return dir == "" || strings.HasPrefix(dir, "/")
+	case "keyring":
+		switch parsed.Host {
+		case "user", "session", "process", "thread":
+			return len(strings.TrimPrefix(parsed.Path, "/")) > 0
+		default:
+			return false
+		}
+	default:
+		return IsValidFileUri(input)
+	}
+}
+
+// IsValidFileUri checks whether the input is a path or a file URI with optional ownership, permissions and number of
+// retained backups, such as "file://user:group@/path/to/file?chmod=0640&backups=3".
+func IsValidFileUri(input string) bool {
+	parsed, err := url.Parse(input)
+	if err != nil || len(parsed.Path) == 0 {
+		return false
+	}
+
+	if parsed.Scheme != "" && parsed.Scheme != "file" {
+		return false
+	}
+
+	if parsed.User != nil && len(parsed.User.Username()) == 0 {
+		return false
+	}
+
+	params, err := url.ParseQuery(parsed.RawQuery)
+	if err != nil {
+		return false
+	}
+
+	if params.Has("chmod") {
+		mode, err := strconv.ParseUint(params.Get("chmod"), 8, 32)
+		if err != nil || mode > 0777 {
+			return false
+		}
+	}
+
+	if params.Has("backups") {
+		backups, err := strconv.Atoi(params.Get("backups"))
+		if err != nil || backups < 0 || backups > 100 {
+			return false
+		}
+	}
+
+	return true
+}
+
 func IsValidMqttUrl(input string) bool {
 	_, err := url.ParseRequestURI(input)
 	if err != nil {
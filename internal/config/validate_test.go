@@ -0,0 +1,215 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/soerenschneider/sc-agent/internal/config/vault"
+)
+
+func TestIsValidFileUri(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  bool
+	}{
+		{
+			name:  "plain path",
+			input: "/etc/postgres/password",
+			want:  true,
+		},
+		{
+			name:  "file uri",
+			input: "file:///etc/postgres/password",
+			want:  true,
+		},
+		{
+			name:  "owner, group and mode",
+			input: "file://root:postgres@/etc/postgres/password?chmod=0640",
+			want:  true,
+		},
+		{
+			name:  "home dir",
+			input: "file://~/.config/password",
+			want:  true,
+		},
+		{
+			name:  "invalid mode",
+			input: "file:///etc/postgres/password?chmod=rw-r-----",
+			want:  false,
+		},
+		{
+			name:  "backups",
+			input: "file:///etc/postgres/password?chmod=0640&backups=3",
+			want:  true,
+		},
+		{
+			name:  "invalid backups",
+			input: "file:///etc/postgres/password?backups=-1",
+			want:  false,
+		},
+		{
+			name:  "mode out of range",
+			input: "file:///etc/postgres/password?chmod=17777",
+			want:  false,
+		},
+		{
+			name:  "empty user",
+			input: "file://:postgres@/etc/postgres/password",
+			want:  false,
+		},
+		{
+			name:  "unsupported scheme",
+			input: "https://example.com/password",
+			want:  false,
+		},
+		{
+			name:  "empty path",
+			input: "file://",
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsValidFileUri(tt.input); got != tt.want {
+				t.Errorf("IsValidFileUri() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsValidSecretDestUri(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  bool
+	}{
+		{
+			name:  "file uri",
+			input: "file://root:postgres@/etc/postgres/password?chmod=0640",
+			want:  true,
+		},
+		{
+			name:  "invalid file uri",
+			input: "file:///etc/postgres/password?chmod=999",
+			want:  false,
+		},
+		{
+			name:  "systemd-creds",
+			input: "systemd-creds://postgres-password",
+			want:  true,
+		},
+		{
+			name:  "systemd-creds with dir",
+			input: "systemd-creds://postgres-password?dir=/etc/credstore.encrypted&with_key=host",
+			want:  true,
+		},
+		{
+			name:  "systemd-creds with relative dir",
+			input: "systemd-creds://postgres-password?dir=credstore",
+			want:  false,
+		},
+		{
+			name:  "systemd-creds without name",
+			input: "systemd-creds:///postgres-password",
+			want:  false,
+		},
+		{
+			name:  "keyring",
+			input: "keyring://user/postgres-password",
+			want:  true,
+		},
+		{
+			name:  "unknown keyring",
+			input: "keyring://foo/postgres-password",
+			want:  false,
+		},
+		{
+			name:  "keyring without description",
+			input: "keyring://user",
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsValidSecretDestUri(tt.input); got != tt.want {
+				t.Errorf("IsValidSecretDestUri() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsValidTokenCacheUri(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  bool
+	}{
+		{
+			name:  "file uri",
+			input: "file:///var/lib/sc-agent/vault-token?chmod=0600&age_key_file=/var/lib/sc-agent/token-cache.key",
+			want:  true,
+		},
+		{
+			name:  "user keyring",
+			input: "keyring://user/sc-agent-vault-token",
+			want:  true,
+		},
+		{
+			name:  "session keyring",
+			input: "keyring://session/sc-agent-vault-token",
+			want:  false,
+		},
+		{
+			name:  "process keyring",
+			input: "keyring://process/sc-agent-vault-token",
+			want:  false,
+		},
+		{
+			name:  "thread keyring",
+			input: "keyring://thread/sc-agent-vault-token",
+			want:  false,
+		},
+		{
+			name:  "user keyring without description",
+			input: "keyring://user",
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsValidTokenCacheUri(tt.input); got != tt.want {
+				t.Errorf("IsValidTokenCacheUri() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate_VaultTokenCache(t *testing.T) {
+	tests := []struct {
+		name       string
+		tokenCache string
+		wantErr    bool
+	}{
+		{
+			name: "no token cache",
+		},
+		{
+			name:       "user keyring",
+			tokenCache: "keyring://user/sc-agent-vault-token",
+		},
+		{
+			name:       "process keyring",
+			tokenCache: "keyring://process/sc-agent-vault-token",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := getDefaultConfig()
+			conf.Vault = map[string]vault.Vault{"default": {TokenCache: tt.tokenCache}}
+			if err := Validate(&conf); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
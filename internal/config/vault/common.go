@@ -1,24 +1,103 @@
 package vault
 
 import (
+	"strings"
+
 	"gopkg.in/yaml.v3"
 )
 
 const (
 	defaultAuthMethod   = "approle"
 	defaultApproleMount = "approle"
+	defaultCertMount    = "cert"
+	defaultJwtMount     = "jwt"
+	defaultK8sMount     = "kubernetes"
 )
 
 type Vault struct {
-	Address                  string                    `yaml:"address" env:"VAULT_ADDR" validate:"omitempty,http_url"`
-	AuthMethod               string                    `yaml:"auth_method" validate:"required,oneof=token approle"`
-	Token                    string                    `yaml:"token" env:"VAULT_TOKEN" validate:"required_if=AuthMethod token"`
-	RoleId                   string                    `yaml:"role_id" validate:"required_if=AuthMethod approle"`
-	SecretIdFile             string                    `yaml:"secret_id_file" validate:"required_if=AuthMethod approle,file"`
-	SecretIdFileUser         string                    `yaml:"secret_id_file_user" validate:"string"`
-	MountApprole             string                    `yaml:"approle_mount" validate:"required_if=AuthMethod approle"`
+	Address          string `yaml:"address" env:"VAULT_ADDR" validate:"omitempty,http_url"`
+	AuthMethod       string `yaml:"auth_method" validate:"required,oneof=token approle cert kubernetes jwt"`
+	Token            string `yaml:"token" env:"VAULT_TOKEN" validate:"required_if=AuthMethod token"`
+	RoleId           string `yaml:"role_id" validate:"required_if=AuthMethod approle"`
+	SecretIdFile     string `yaml:"secret_id_file" validate:"required_if=AuthMethod approle,file"`
+	SecretIdFileUser string `yaml:"secret_id_file_user" validate:"string"`
+	MountApprole     string `yaml:"approle_mount" validate:"required_if=AuthMethod approle"`
+	// SecretIdWrapped indicates that SecretIdFile contains a response-wrapped secret_id, e.g. delivered on first boot.
+	// It is unwrapped on login and the file is replaced with the unwrapped secret_id. Wrapped secret_ids are also
+	// detected automatically if the file exists on startup.
+	SecretIdWrapped bool `yaml:"secret_id_wrapped"`
+	// SecretIdWrapTtl response-wraps secret_ids generated by the secret_id rotation using the given TTL.
+	SecretIdWrapTtl          string                    `yaml:"secret_id_wrap_ttl" validate:"omitempty,duration"`
 	ApproleCidrTokenResolver *VaultApproleCidrResolver `yaml:"cidr_token_resolver"`
 	ApproleCidrLoginResolver *VaultApproleCidrResolver `yaml:"cidr_login_resolver"`
+
+	// CertFile and KeyFile are used to log in using the TLS certificate auth method, e.g. the files of a certificate
+	// that is managed by the x509_pki component. If RoleId is set as well, AppRole is used as long as the certificate
+	// has not been issued yet.
+	CertFile  string `yaml:"cert_file" validate:"required_if=AuthMethod cert"`
+	KeyFile   string `yaml:"key_file" validate:"required_if=AuthMethod cert"`
+	CertRole  string `yaml:"cert_role"`
+	MountCert string `yaml:"cert_mount" validate:"required_if=AuthMethod cert"`
+
+	// KubernetesTokenFile is the service account token used to log in using the Kubernetes auth method, defaults to the
+	// token mounted into pods.
+	KubernetesRole      string `yaml:"kubernetes_role" validate:"required_if=AuthMethod kubernetes"`
+	KubernetesTokenFile string `yaml:"kubernetes_token_file"`
+	MountKubernetes     string `yaml:"kubernetes_mount" validate:"required_if=AuthMethod kubernetes"`
+
+	// JwtFile or JwtUrl provide the JWT used to log in using the JWT/OIDC auth method. JwtUrl is a local identity
+	// endpoint, e.g. a cloud provider's metadata server, that is queried for every login using JwtUrlHeaders.
+	JwtRole       string            `yaml:"jwt_role" validate:"required_if=AuthMethod jwt"`
+	JwtFile       string            `yaml:"jwt_file" validate:"excluded_with=JwtUrl"`
+	JwtUrl        string            `yaml:"jwt_url" validate:"omitempty,http_url"`
+	JwtUrlHeaders map[string]string `yaml:"jwt_url_headers"`
+	MountJwt      string            `yaml:"jwt_mount" validate:"required_if=AuthMethod jwt"`
+
+	// Addresses are further addresses of the nodes of a Vault cluster. The client fails over to the healthiest node,
+	// which is determined every HealthCheckInterval.
+	Addresses           []string `yaml:"addresses" validate:"omitempty,dive,http_url"`
+	HealthCheckInterval string   `yaml:"health_check_interval" validate:"omitempty,duration"`
+	// Namespace is the Vault Enterprise or OpenBao namespace all requests of the client are sent to.
+	Namespace string `yaml:"namespace"`
+
+	CaCertFile     string `yaml:"tls_ca_cert" validate:"omitempty,file"`
+	ClientCertFile string `yaml:"tls_client_cert" validate:"omitempty,required_unless=ClientKeyFile '',file"`
+	ClientKeyFile  string `yaml:"tls_client_key" validate:"omitempty,required_unless=ClientCertFile '',file"`
+	TlsServerName  string `yaml:"tls_server_name"`
+	TlsInsecure    bool   `yaml:"tls_insecure"`
+
+	// TokenSinks write the token (or child tokens derived from it) to files for other local processes.
+	TokenSinks []VaultTokenSink `yaml:"token_sinks" validate:"omitempty,dive"`
+	// TokenCache is a storage URI the token is persisted to, so it is renewed instead of performing a new login after a
+	// restart. It is either a file URI that is encrypted using age, e.g. with a host-local key, or a URI of the user
+	// keyring.
+	TokenCache string `yaml:"token_cache"`
+}
+
+// VaultTokenSink configures a file the token of the agent, or a child token created from it, is written to.
+type VaultTokenSink struct {
+	// Dest is a file URI that optionally defines the owner, group and permissions of the file.
+	Dest string `yaml:"dest" validate:"required,file_uri"`
+	// Policies and Ttl create a child token with the given policies and TTL instead of writing the agent's token.
+	Policies []string `yaml:"policies"`
+	Ttl      string   `yaml:"ttl" validate:"omitempty,duration"`
+	// WrapTtl response-wraps the written token using the given TTL.
+	WrapTtl string `yaml:"wrap_ttl" validate:"omitempty,duration"`
+}
+
+// Endpoints returns Address and Addresses without duplicates.
+func (conf *Vault) Endpoints() []string {
+	var ret []string
+	seen := map[string]bool{}
+	for _, address := range append([]string{conf.Address}, conf.Addresses...) {
+		address = strings.TrimSuffix(address, "/")
+		if address == "" || seen[address] {
+			continue
+		}
+		seen[address] = true
+		ret = append(ret, address)
+	}
+	return ret
 }
 
 type VaultApproleCidrResolver struct {
@@ -31,8 +110,11 @@ func (conf *Vault) UnmarshalYAML(node *yaml.Node) error {
 
 	// Define conf temporary struct with default values
 	tmp := &Alias{
-		AuthMethod:   defaultAuthMethod,
-		MountApprole: defaultApproleMount,
+		AuthMethod:      defaultAuthMethod,
+		MountApprole:    defaultApproleMount,
+		MountCert:       defaultCertMount,
+		MountJwt:        defaultJwtMount,
+		MountKubernetes: defaultK8sMount,
 	}
 
 	// Unmarshal the yaml data into the temporary struct
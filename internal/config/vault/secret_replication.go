@@ -3,6 +3,7 @@ package vault
 import (
 	"path/filepath"
 
+	"github.com/soerenschneider/sc-agent/internal/domain"
 	"gopkg.in/yaml.v3"
 )
 
@@ -14,7 +15,71 @@ type SecretsReplication struct {
 	Enabled             bool                            `yaml:"enabled"`
 	VaultId             string                          `yaml:"vault"`
 	Kv2Mount            string                          `yaml:"kv2_mount" validate:"required"`
+	VaultEvents         bool                            `yaml:"vault_events"`
 	ReplicationRequests map[string]VaultReplicationItem `yaml:"replication_requests" validate:"dive"`
+	// Backends are named secret backends items can be read from instead of the default Vault client.
+	Backends map[string]SecretBackend `yaml:"backends" validate:"dive"`
+}
+
+const (
+	SecretBackendVault              = "vault"
+	SecretBackendSops               = "sops"
+	SecretBackendAge                = "age"
+	SecretBackendOnePasswordConnect = "onepassword_connect"
+	SecretBackendBitwarden          = "bitwarden"
+)
+
+// SecretBackend configures a source secrets are replicated from.
+type SecretBackend struct {
+	Type string `yaml:"type" validate:"required,oneof=vault sops age onepassword_connect bitwarden"`
+
+	// VaultId references the Vault (or OpenBao) instance for the "vault" backend.
+	VaultId  string `yaml:"vault" validate:"required_if=Type vault"`
+	Kv2Mount string `yaml:"kv2_mount"`
+
+	// File is the encrypted YAML or JSON file for the "sops" and "age" backends.
+	File string `yaml:"file" validate:"required_if=Type sops,required_if=Type age"`
+	// IdentityFile contains the age identities or the SSH private key used to decrypt File for the "age" backend.
+	IdentityFile string `yaml:"identity_file" validate:"required_if=Type age"`
+
+	// Address is the URL of the 1Password Connect server or the Bitwarden API, e.g. as served by "bw serve".
+	Address string `yaml:"address" validate:"required_if=Type onepassword_connect,required_if=Type bitwarden,omitempty,http_url"`
+	// TokenFile contains the bearer token sent to Address, it is required for 1Password Connect.
+	TokenFile string `yaml:"token_file" validate:"required_if=Type onepassword_connect"`
+}
+
+// VaultIds returns the ids of all Vault clients secrets are replicated from.
+func (conf *SecretsReplication) VaultIds() []string {
+	var ret []string
+	if conf.VaultId != "" {
+		ret = append(ret, conf.VaultId)
+	}
+
+	for _, backend := range conf.Backends {
+		if backend.Type == SecretBackendVault {
+			ret = append(ret, backend.VaultId)
+		}
+	}
+
+	return ret
+}
+
+func (conf *SecretBackend) UnmarshalYAML(node *yaml.Node) error {
+	type Alias SecretBackend // Create an alias to avoid recursion during unmarshalling
+
+	// Define conf temporary struct with default values
+	tmp := &Alias{
+		Kv2Mount: defaultSecretMount,
+	}
+
+	// Unmarshal the yaml data into the temporary struct
+	if err := node.Decode(&tmp); err != nil {
+		return err
+	}
+
+	// Assign the values from the temporary struct to the original struct
+	*conf = SecretBackend(*tmp)
+	return nil
 }
 
 func (conf *SecretsReplication) UnmarshalYAML(node *yaml.Node) error {
@@ -37,10 +102,44 @@ func (conf *SecretsReplication) UnmarshalYAML(node *yaml.Node) error {
 }
 
 type VaultReplicationItem struct {
-	SecretPath    string         `yaml:"secret_path" validate:"required"`
-	Formatter     string         `yaml:"formatter" validate:"required,oneof=env json template yaml"`
+	SecretPath    string         `yaml:"secret_path" validate:"required_unless=Formatter template"`
+	Formatter     string         `yaml:"formatter" validate:"required,oneof=dotenv env ini json netrc pgpass properties template toml yaml"`
 	FormatterArgs map[string]any `yaml:"formatter_args"`
-	DestUri       string         `yaml:"dest" validate:"required"`
+	// DestUri is the file the secret is written to. Ownership and permissions can be set using the userinfo and the
+	// "chmod" query parameter of the URI, e.g. "file://root:postgres@/etc/postgres/password?chmod=0640". Secrets can
+	// also be encrypted using "systemd-creds://<name>" or stored in the kernel keyring using
+	// "keyring://<keyring>/<description>".
+	DestUri string `yaml:"dest" validate:"required,secret_dest_uri"`
+	// Backend is the name of the backend the secret is read from, defaults to the configured Vault. For the "sops" and
+	// "age" backends, SecretPath addresses a nested map within the decrypted file, e.g. "prod/db". For 1Password
+	// Connect it is "<vault id>/<item id>", for Bitwarden the id of the item.
+	Backend string `yaml:"backend"`
+
+	// Dynamic marks the item as a dynamic secret, e.g. "database/creds/my-role", which is read from the logical path
+	// given as SecretPath. Its lease is renewed in the background and the secret is fetched again before its lease
+	// reaches its max TTL.
+	Dynamic bool `yaml:"dynamic"`
+	// LeaseIncrement is the duration-formatted increment that is requested when renewing the lease of a dynamic secret.
+	LeaseIncrement string `yaml:"lease_increment" validate:"omitempty,duration"`
+	// PostHooks are run after the content of the destination has changed, e.g. to reload a service. For dynamic
+	// secrets this happens when the credentials have been rotated.
+	PostHooks map[string]string `yaml:"post_hooks"`
+	// OnDelete defines how the local copy is treated after the secret has been deleted or destroyed in Vault: "keep"
+	// (default) keeps it, "remove" removes it, "tombstone" replaces it with empty content and "alert" keeps it but
+	// reports the deletion as error.
+	OnDelete string `yaml:"on_delete" validate:"omitempty,oneof=keep remove tombstone alert"`
+}
+
+func (conf *VaultReplicationItem) GetPostHooks() []domain.PostHook {
+	var postHooks []domain.PostHook
+	for key, val := range conf.PostHooks {
+		postHooks = append(postHooks, domain.PostHook{
+			Name: key,
+			Cmd:  val,
+		})
+	}
+
+	return postHooks
 }
 
 func (conf *VaultReplicationItem) UnmarshalYAML(node *yaml.Node) error {
@@ -59,8 +158,23 @@ func (conf *VaultReplicationItem) UnmarshalYAML(node *yaml.Node) error {
 	switch defaultExtension {
 	case ".json":
 		formatter = "json"
-	case ".yaml":
+	case ".yaml", ".yml":
 		formatter = "yaml"
+	case ".env":
+		formatter = "env"
+	case ".ini":
+		formatter = "ini"
+	case ".properties":
+		formatter = "properties"
+	case ".toml":
+		formatter = "toml"
+	}
+
+	switch filepath.Base(tmp.DestUri) {
+	case ".netrc":
+		formatter = "netrc"
+	case ".pgpass":
+		formatter = "pgpass"
 	}
 
 	if tmp.Formatter == "" && formatter != "" {
@@ -2,6 +2,8 @@ package http_server
 
 import (
 	"context"
+
+	"github.com/soerenschneider/sc-agent/internal/domain"
 )
 
 func (s *HttpServer) InfoGetComponents(_ context.Context, request InfoGetComponentsRequestObject) (InfoGetComponentsResponseObject, error) {
@@ -10,3 +12,17 @@ func (s *HttpServer) InfoGetComponents(_ context.Context, request InfoGetCompone
 		EnabledComponents: enabledComponents,
 	}, nil
 }
+
+func (s *HttpServer) InfoGetHealth(_ context.Context, request InfoGetHealthRequestObject) (InfoGetHealthResponseObject, error) {
+	health := s.services.Health()
+
+	status := InfoHealthStatusHealthy
+	if health.Status == domain.Degraded {
+		status = InfoHealthStatusDegraded
+	}
+
+	return InfoGetHealth200JSONResponse{
+		Status:   status,
+		Degraded: health.Degraded,
+	}, nil
+}
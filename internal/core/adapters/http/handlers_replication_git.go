@@ -0,0 +1,116 @@
+package http_server
+
+import (
+	"context"
+	"errors"
+
+	"github.com/soerenschneider/sc-agent/internal/domain/git_replication"
+)
+
+func (s *HttpServer) ReplicationGetGitItemsList(ctx context.Context, request ReplicationGetGitItemsListRequestObject) (ReplicationGetGitItemsListResponseObject, error) {
+	if s.services.GitReplication == nil {
+		return ReplicationGetGitItemsList501ApplicationProblemPlusJSONResponse{}, nil
+	}
+
+	items, err := s.services.GitReplication.GetReplicationItems()
+	if err != nil {
+		return ReplicationGetGitItemsList500ApplicationProblemPlusJSONResponse{}, nil
+	}
+
+	dto := convertGitReplicationItems(items)
+	return ReplicationGetGitItemsList200JSONResponse(dto), nil
+}
+
+func (s *HttpServer) ReplicationGetGitItem(ctx context.Context, request ReplicationGetGitItemRequestObject) (ReplicationGetGitItemResponseObject, error) {
+	if s.services.GitReplication == nil {
+		return ReplicationGetGitItem501ApplicationProblemPlusJSONResponse{}, nil
+	}
+
+	item, err := s.services.GitReplication.GetReplicationItem(request.Id)
+	if err != nil {
+		if errors.Is(err, git_replication.ErrGitReplicationItemNotFound) {
+			return ReplicationGetGitItem404ApplicationProblemPlusJSONResponse{}, nil
+		}
+
+		return ReplicationGetGitItem500ApplicationProblemPlusJSONResponse{}, nil
+	}
+
+	dto := ConvertGitReplicationItemToDto(item)
+	return ReplicationGetGitItem200JSONResponse(dto), nil
+}
+
+func (s *HttpServer) ReplicationPostGitItemSync(ctx context.Context, request ReplicationPostGitItemSyncRequestObject) (ReplicationPostGitItemSyncResponseObject, error) {
+	if s.services.GitReplication == nil {
+		return ReplicationPostGitItemSync501ApplicationProblemPlusJSONResponse{}, nil
+	}
+
+	item, err := s.services.GitReplication.GetReplicationItem(request.Id)
+	if err != nil {
+		if errors.Is(err, git_replication.ErrGitReplicationItemNotFound) {
+			return ReplicationPostGitItemSync404ApplicationProblemPlusJSONResponse{}, nil
+		}
+
+		return ReplicationPostGitItemSync500ApplicationProblemPlusJSONResponse{}, nil
+	}
+
+	if err := s.services.GitReplication.Replicate(ctx, item); err != nil {
+		return ReplicationPostGitItemSync500ApplicationProblemPlusJSONResponse{}, nil
+	}
+
+	item, err = s.services.GitReplication.GetReplicationItem(request.Id)
+	if err != nil {
+		return ReplicationPostGitItemSync500ApplicationProblemPlusJSONResponse{}, nil
+	}
+
+	dto := ConvertGitReplicationItemToDto(item)
+	return ReplicationPostGitItemSync200JSONResponse(dto), nil
+}
+
+func convertGitReplicationItems(items []git_replication.ReplicationItem) ReplicationGitItemsList {
+	ret := make([]ReplicationGitItem, len(items))
+
+	for idx := range items {
+		ret[idx] = ConvertGitReplicationItemToDto(items[idx])
+	}
+
+	return ReplicationGitItemsList{Data: ret}
+}
+
+func ConvertGitReplicationItemToDto(item git_replication.ReplicationItem) ReplicationGitItem {
+	paths := make([]ReplicationGitPath, len(item.ReplicationConf.Paths))
+	for idx, path := range item.ReplicationConf.Paths {
+		paths[idx] = ReplicationGitPath{
+			Src:   path.Source,
+			Dest:  path.Destinations,
+			IsDir: path.IsDir,
+		}
+	}
+
+	return ReplicationGitItem{
+		Id:               item.ReplicationConf.Id,
+		Repository:       item.ReplicationConf.Repository,
+		Ref:              item.ReplicationConf.Ref(),
+		Paths:            paths,
+		VerifySignatures: item.ReplicationConf.KeyringFile != "",
+		PostHooks:        convertPosthooks(item.PostHooks),
+		Status:           convertGitReplicationStatus(item.Status),
+		LastAttempt:      convertOptionalTime(item.LastAttempt),
+		LastSuccess:      convertOptionalTime(item.LastSuccess),
+		LastError:        item.LastError,
+		Commit:           item.Commit,
+		NextSync:         convertOptionalTime(item.NextSync),
+	}
+}
+
+func convertGitReplicationStatus(status git_replication.Status) ReplicationGitItemStatus {
+	switch status {
+	case git_replication.VerificationFailed:
+		return ReplicationGitItemStatusVerificationFailed
+	case git_replication.FailedStatus:
+		return ReplicationGitItemStatusFailed
+	case git_replication.Synced:
+		return ReplicationGitItemStatusSynced
+	default:
+		return ReplicationGitItemStatusUnknown
+	}
+}
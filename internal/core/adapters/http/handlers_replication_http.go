@@ -3,10 +3,14 @@ package http_server
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/soerenschneider/sc-agent/internal/domain/http_replication"
 )
 
+// httpReplicationSyncTimeout limits the duration of a replication that has been triggered using the API.
+const httpReplicationSyncTimeout = 5 * time.Minute
+
 func (s *HttpServer) ReplicationGetHttpItemsList(ctx context.Context, request ReplicationGetHttpItemsListRequestObject) (ReplicationGetHttpItemsListResponseObject, error) {
 	if s.services.HttpReplication == nil {
 		return ReplicationGetHttpItemsList501ApplicationProblemPlusJSONResponse{}, nil
@@ -38,15 +42,56 @@ func (s *HttpServer) ReplicationGetHttpItem(ctx context.Context, request Replica
 		return ReplicationGetHttpItem500ApplicationProblemPlusJSONResponse{}, nil
 	}
 
-	dto := convertHttpReplicationItem(item)
+	dto := ConvertHttpReplicationItemToDto(item)
 	return ReplicationGetHttpItem200JSONResponse(dto), nil
 }
 
+func (s *HttpServer) ReplicationPostHttpItemSync(ctx context.Context, request ReplicationPostHttpItemSyncRequestObject) (ReplicationPostHttpItemSyncResponseObject, error) {
+	if s.services.HttpReplication == nil {
+		return ReplicationPostHttpItemSync501ApplicationProblemPlusJSONResponse{}, nil
+	}
+
+	item, err := s.services.HttpReplication.GetReplicationItem(request.Id)
+	if err != nil {
+		if errors.Is(err, http_replication.ErrHttpReplicationItemNotFound) {
+			return ReplicationPostHttpItemSync404ApplicationProblemPlusJSONResponse{}, nil
+		}
+
+		return ReplicationPostHttpItemSync500ApplicationProblemPlusJSONResponse{}, nil
+	}
+
+	// the replication must not be aborted if the client disconnects, e.g. after writing the destination but before
+	// running the post hooks. Its outcome is recorded in the item's status either way.
+	result := make(chan error, 1)
+	go func() {
+		syncCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), httpReplicationSyncTimeout)
+		defer cancel()
+		result <- s.services.HttpReplication.Replicate(syncCtx, item)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ReplicationPostHttpItemSync500ApplicationProblemPlusJSONResponse{}, nil
+	case err := <-result:
+		if err != nil {
+			return ReplicationPostHttpItemSync500ApplicationProblemPlusJSONResponse{}, nil
+		}
+	}
+
+	item, err = s.services.HttpReplication.GetReplicationItem(request.Id)
+	if err != nil {
+		return ReplicationPostHttpItemSync500ApplicationProblemPlusJSONResponse{}, nil
+	}
+
+	dto := ConvertHttpReplicationItemToDto(item)
+	return ReplicationPostHttpItemSync200JSONResponse(dto), nil
+}
+
 func convertHttpReplicationItems(items []http_replication.ReplicationItem) ReplicationHttpItemsList {
 	ret := make([]ReplicationHttpItem, len(items))
 
 	for idx := range items {
-		ret[idx] = convertHttpReplicationItem(items[idx])
+		ret[idx] = ConvertHttpReplicationItemToDto(items[idx])
 	}
 
 	return ReplicationHttpItemsList{Data: ret}
@@ -61,10 +106,11 @@ func convertFileValidation(item *http_replication.FileValidation) *FileValidatio
 		Test:         FileValidationTest(item.Test),
 		Arg:          item.Arg,
 		InvertResult: item.InvertResult,
+		SignatureUrl: item.SignatureUrl,
 	}
 }
 
-func convertHttpReplicationItem(item http_replication.ReplicationItem) ReplicationHttpItem {
+func ConvertHttpReplicationItemToDto(item http_replication.ReplicationItem) ReplicationHttpItem {
 	return ReplicationHttpItem{
 		Id:             item.ReplicationConf.Id,
 		DestUris:       item.ReplicationConf.Destinations,
@@ -72,6 +118,12 @@ func convertHttpReplicationItem(item http_replication.ReplicationItem) Replicati
 		FileValidation: convertFileValidation(item.ReplicationConf.FileValidation),
 		PostHooks:      convertPosthooks(item.PostHooks),
 		Status:         convertHttpReplicationStatus(item.Status),
+		LastAttempt:    convertOptionalTime(item.LastAttempt),
+		LastSuccess:    convertOptionalTime(item.LastSuccess),
+		LastError:      item.LastError,
+		ContentHash:    item.ContentHash,
+		Version:        item.Version,
+		NextSync:       convertOptionalTime(item.NextSync),
 	}
 }
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"reflect"
+	"time"
 
 	"github.com/soerenschneider/sc-agent/internal/domain/secret_replication"
 )
@@ -42,6 +43,9 @@ func (s *HttpServer) ReplicationPostSecretsRequests(ctx context.Context, request
 
 	updatedSecret, err := s.services.SecretsReplication.Replicate(ctx, syncSecretRequest)
 	if err != nil {
+		if errors.Is(err, secret_replication.ErrDynamicSecretNotReplicable) {
+			return ReplicationPostSecretsRequests400ApplicationProblemPlusJSONResponse{}, nil
+		}
 		return ReplicationPostSecretsRequests500ApplicationProblemPlusJSONResponse{}, nil
 	}
 
@@ -69,6 +73,8 @@ func convertSecretReplicationStatus(status secret_replication.SecretReplicationS
 		return ReplicationSecretsItemStatusSynced
 	case secret_replication.FailedStatus:
 		return ReplicationSecretsItemStatusFailed
+	case secret_replication.DeletedStatus:
+		return ReplicationSecretsItemStatusDeleted
 	default:
 		return ReplicationSecretsItemStatusUnknown
 	}
@@ -77,11 +83,19 @@ func convertSecretReplicationStatus(status secret_replication.SecretReplicationS
 func convertSecretReplicationItem(item secret_replication.ReplicationItem) ReplicationSecretsItem {
 	status := convertSecretReplicationStatus(item.Status)
 	return ReplicationSecretsItem{
-		Id:         item.ReplicationConf.Id,
-		DestUri:    item.ReplicationConf.DestUri,
-		Formatter:  getType(item.Formatter),
-		SecretPath: item.ReplicationConf.SecretPath,
-		Status:     &status,
+		Id:                item.ReplicationConf.Id,
+		DestUri:           item.ReplicationConf.DestUri,
+		Formatter:         getType(item.Formatter),
+		SecretPath:        item.ReplicationConf.SecretPath,
+		Status:            &status,
+		ReplicatedVersion: item.ReplicatedVersion,
+		ReferencedSecrets: item.ReferencedSecrets,
+		LeaseExpiry:       convertOptionalTime(item.LeaseExpiry),
+		PostHooks:         convertPosthooks(item.PostHooks),
+		LastAttempt:       convertOptionalTime(item.LastAttempt),
+		LastSuccess:       convertOptionalTime(item.LastSuccess),
+		LastError:         item.LastError,
+		ContentHash:       item.ContentHash,
 	}
 }
 
@@ -95,6 +109,14 @@ func convertSecretReplicationItems(syncItems []secret_replication.ReplicationIte
 	return ReplicationSecretsItemsList{Data: ret}
 }
 
+// convertOptionalTime returns nil for zero timestamps so they are omitted from responses.
+func convertOptionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
 func getType(myvar interface{}) string {
 	if t := reflect.TypeOf(myvar); t.Kind() == reflect.Ptr {
 		return t.Elem().Name()
@@ -0,0 +1,105 @@
+package http_server
+
+import (
+	"context"
+	"errors"
+
+	"github.com/soerenschneider/sc-agent/internal/domain/vault"
+)
+
+func (s *HttpServer) VaultGetClientsList(ctx context.Context, request VaultGetClientsListRequestObject) (VaultGetClientsListResponseObject, error) {
+	if s.services.Vault == nil {
+		return VaultGetClientsList501ApplicationProblemPlusJSONResponse{}, nil
+	}
+
+	clients, err := s.services.Vault.GetClients()
+	if err != nil {
+		return VaultGetClientsList500ApplicationProblemPlusJSONResponse{}, nil
+	}
+
+	dto := convertVaultClients(clients)
+	return VaultGetClientsList200JSONResponse(dto), nil
+}
+
+func (s *HttpServer) VaultGetClient(ctx context.Context, request VaultGetClientRequestObject) (VaultGetClientResponseObject, error) {
+	if s.services.Vault == nil {
+		return VaultGetClient501ApplicationProblemPlusJSONResponse{}, nil
+	}
+
+	client, err := s.services.Vault.GetClient(request.Id)
+	if err != nil {
+		if errors.Is(err, vault.ErrVaultClientNotFound) {
+			return VaultGetClient404ApplicationProblemPlusJSONResponse{}, nil
+		}
+
+		return VaultGetClient500ApplicationProblemPlusJSONResponse{}, nil
+	}
+
+	dto := convertVaultClient(client)
+	return VaultGetClient200JSONResponse(dto), nil
+}
+
+func (s *HttpServer) VaultPostClientSecretIdRotation(ctx context.Context, request VaultPostClientSecretIdRotationRequestObject) (VaultPostClientSecretIdRotationResponseObject, error) {
+	if s.services.Vault == nil {
+		return VaultPostClientSecretIdRotation501ApplicationProblemPlusJSONResponse{}, nil
+	}
+
+	client, err := s.services.Vault.RotateSecretId(request.Id)
+	if err != nil {
+		if errors.Is(err, vault.ErrVaultClientNotFound) {
+			return VaultPostClientSecretIdRotation404ApplicationProblemPlusJSONResponse{}, nil
+		}
+
+		if errors.Is(err, vault.ErrSecretIdRotationDisabled) {
+			return VaultPostClientSecretIdRotation400ApplicationProblemPlusJSONResponse{}, nil
+		}
+
+		return VaultPostClientSecretIdRotation500ApplicationProblemPlusJSONResponse{}, nil
+	}
+
+	dto := convertVaultClient(client)
+	return VaultPostClientSecretIdRotation200JSONResponse(dto), nil
+}
+
+func convertVaultClients(clients []vault.ClientStatus) VaultClientsList {
+	ret := make([]VaultClient, len(clients))
+
+	for idx := range clients {
+		ret[idx] = convertVaultClient(clients[idx])
+	}
+
+	return VaultClientsList{Data: ret}
+}
+
+func convertVaultClient(client vault.ClientStatus) VaultClient {
+	ret := VaultClient{
+		Id:         client.Id,
+		AuthMethod: client.AuthMethod,
+		Address:    client.Address,
+		Token: &VaultToken{
+			Ttl:           int64(client.Token.TtlRemaining().Seconds()),
+			LeaseDuration: int64(client.Token.Ttl.Seconds()),
+			Expiration:    convertOptionalTime(client.Token.Expiration),
+			Authenticated: client.Token.IsAuthenticated(),
+			Renewable:     client.Token.Renewable,
+			LastLogin:     convertOptionalTime(client.Token.LastLogin),
+			LastRenewal:   convertOptionalTime(client.Token.LastRenewal),
+			LastError:     client.Token.LastError,
+			LastErrorTime: convertOptionalTime(client.Token.LastErrorTime),
+		},
+	}
+
+	if client.SecretId != nil {
+		ret.SecretId = &VaultSecretId{
+			Accessor:      client.SecretId.Accessor,
+			Expiration:    convertOptionalTime(client.SecretId.Expiration),
+			NextRotation:  convertOptionalTime(client.SecretId.NextRotation),
+			LastCheck:     convertOptionalTime(client.SecretId.LastCheck),
+			LastRotation:  convertOptionalTime(client.SecretId.LastRotation),
+			LastError:     client.SecretId.LastError,
+			LastErrorTime: convertOptionalTime(client.SecretId.LastErrorTime),
+		}
+	}
+
+	return ret
+}
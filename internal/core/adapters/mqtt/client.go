@@ -70,6 +70,9 @@ func (c *Client) StartListener(ctx context.Context, wg *sync.WaitGroup) error {
 	router.MustRegisterHandler("packages/list", packageHandler)
 	router.MustRegisterHandler("packages/upgrade", packageHandler)
 
+	httpReplicationHandler := &HttpReplicationHandler{services: c.services}
+	router.MustRegisterHandler("replication/http/+/sync", httpReplicationHandler)
+
 	if err := router.Subscribe(); err != nil {
 		return err
 	}
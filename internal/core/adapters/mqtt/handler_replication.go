@@ -0,0 +1,39 @@
+package mqtt
+
+import (
+	"context"
+	"path"
+
+	httpAdapter "github.com/soerenschneider/sc-agent/internal/core/adapters/http"
+	"github.com/soerenschneider/sc-agent/internal/core/ports"
+	"github.com/soerenschneider/sc-agent/internal/domain"
+)
+
+type HttpReplicationHandler struct {
+	services *ports.Components
+}
+
+// Handle replicates the item whose id is the second to last element of the topic, e.g. "replication/http/ca-crt/sync".
+func (h *HttpReplicationHandler) Handle(ctx context.Context, topic string, _ []byte) (any, error) {
+	if h.services.HttpReplication == nil {
+		return nil, domain.ErrComponentDisabled
+	}
+
+	id := path.Base(path.Dir(topic))
+	item, err := h.services.HttpReplication.GetReplicationItem(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.services.HttpReplication.Replicate(ctx, item); err != nil {
+		return nil, err
+	}
+
+	item, err = h.services.HttpReplication.GetReplicationItem(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// borrow code from http adapter to convert to dto
+	return httpAdapter.ConvertHttpReplicationItemToDto(item), nil
+}
@@ -2,14 +2,16 @@ package ports
 
 import (
 	"context"
-	"errors"
 	"reflect"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/soerenschneider/sc-agent/cmd/vault"
 	"github.com/soerenschneider/sc-agent/internal/config"
+	"github.com/soerenschneider/sc-agent/internal/domain"
+	"github.com/soerenschneider/sc-agent/internal/metrics"
 	"github.com/soerenschneider/sc-agent/internal/services/components/packages"
 )
 
@@ -28,6 +30,7 @@ type Components struct {
 	Acme               Acme
 	RebootManager      RebootManager
 	HttpReplication    HttpReplication
+	GitReplication     GitReplication
 	K0s                K0s
 	Libvirt            Libvirt
 	Packages           SystemPackages
@@ -37,18 +40,30 @@ type Components struct {
 	SecretsReplication SecretsReplication
 	Services           Systemd
 	SshCertificates    SshPki
+	Vault              VaultClients
 	Wol                WakeOnLan
+
+	// degraded contains the components that wait for their Vault client to log in for longer than the timeout
+	degraded      map[string]bool
+	degradedMutex sync.Mutex
 }
 
+// UsesVault returns whether Vault clients need to log in. This is the case as soon as a Vault client is configured,
+// as clients are not only used by Vault components but also by token sinks, Vault bearer tokens of the HTTP
+// replication and transit encrypted storage.
 func (s *Components) UsesVault() bool {
-	return s.SshCertificates != nil || s.Pki != nil || s.SecretsReplication != nil || s.Acme != nil
+	return s.Vault != nil || s.SshCertificates != nil || s.Pki != nil || s.SecretsReplication != nil || s.Acme != nil
 }
 
-func (s *Components) StartServices(ctx context.Context, conf config.Config, scAgentFatalErrors chan error) {
+func (s *Components) StartServices(ctx context.Context, wg *sync.WaitGroup, conf config.Config) {
 	if s.HttpReplication != nil {
 		go s.HttpReplication.StartReplication(ctx)
 	}
 
+	if s.GitReplication != nil {
+		go s.GitReplication.StartReplication(ctx)
+	}
+
 	if s.RebootManager != nil {
 		go func() {
 			_ = s.RebootManager.Start(ctx)
@@ -72,20 +87,6 @@ func (s *Components) StartServices(ctx context.Context, conf config.Config, scAg
 		return
 	}
 
-	vaultAuthReady := &sync.WaitGroup{}
-	vaultLogins := len(conf.Vault)
-	vaultAuthReady.Add(vaultLogins)
-	vault.StartTokenRenewal(ctx, vaultAuthReady, scAgentFatalErrors)
-
-	// wait on all members of the waitgroup but end forcefully after the timeout has passed
-	vaultLoginWait := make(chan struct{})
-
-	go func() {
-		log.Info().Str(logComponent, mainComponentName).Msgf("Waiting for %d Vault logins to succeed...", vaultLogins)
-		vaultAuthReady.Wait()
-		close(vaultLoginWait)
-	}()
-
 	var timeout time.Duration
 	if conf.VaultLoginTimeout == "" {
 		timeout = maxDuration
@@ -93,33 +94,115 @@ func (s *Components) StartServices(ctx context.Context, conf config.Config, scAg
 		timeout, _ = time.ParseDuration(conf.VaultLoginTimeout)
 	}
 
-	select {
-	case <-vaultLoginWait:
-		log.Info().Str(logComponent, mainComponentName).Msg("Vault login successful")
-	case <-time.After(timeout):
-		log.Error().Str(logComponent, mainComponentName).Msg("Vault login exceeded timeout")
-		scAgentFatalErrors <- errors.New("exceeded vault login timeout")
-	}
+	// each Vault client logs in independently, components are started as soon as the client they depend on has
+	// logged in, so an unreachable Vault instance only degrades the components that use it
+	vault.StartEndpointSelection(ctx)
+	vault.StartTokenRenewal(ctx)
+	vault.StartApproleSecretIdRotation(ctx)
 
-	go func() {
-		vault.StartApproleSecretIdRotation(ctx)
-		if s.SecretsReplication != nil {
+	if s.SecretsReplication != nil {
+		s.startAfterVaultLogin(ctx, "SecretsReplication", timeout, conf.SecretsReplication.VaultIds(), func() {
 			log.Info().Str(logComponent, mainComponentName).Msg("starting continuous secret syncer process")
-			go s.SecretsReplication.StartContinuousReplication(ctx)
-		}
-		if s.SshCertificates != nil {
+			go s.SecretsReplication.StartContinuousReplication(ctx, wg)
+		})
+	}
+	if s.SshCertificates != nil {
+		s.startAfterVaultLogin(ctx, "SshCertificates", timeout, []string{conf.SshSigner.VaultId}, func() {
 			log.Info().Str(logComponent, mainComponentName).Msg("starting management of ssh certificates")
 			go s.SshCertificates.WatchCertificates(ctx)
-		}
-		if s.Pki != nil {
+		})
+	}
+	if s.Pki != nil {
+		s.startAfterVaultLogin(ctx, "Pki", timeout, []string{conf.X509Pki.VaultId}, func() {
 			log.Info().Str(logComponent, mainComponentName).Msg("starting management of x509 certificates")
 			go s.Pki.WatchCertificates(ctx)
-		}
-		if s.Acme != nil {
+		})
+	}
+	if s.Acme != nil {
+		s.startAfterVaultLogin(ctx, "Acme", timeout, []string{conf.Acme.VaultId}, func() {
 			log.Info().Str(logComponent, mainComponentName).Msg("starting management of acme certificates")
 			go s.Acme.WatchCertificates(ctx)
+		})
+	}
+}
+
+// startAfterVaultLogin calls start as soon as all given Vault clients have logged in. Until then, the component is
+// reported as degraded after the timeout has passed.
+func (s *Components) startAfterVaultLogin(ctx context.Context, component string, timeout time.Duration, vaultIds []string, start func()) {
+	loggedIn := make(chan struct{})
+	go func() {
+		if vault.WaitForLogin(ctx, vaultIds...) {
+			close(loggedIn)
 		}
 	}()
+
+	go func() {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+				log.Warn().Str(logComponent, mainComponentName).Str("component", component).Strs("vault", vaultIds).Msg("Vault login exceeded timeout, component is degraded until the login succeeds")
+				s.setDegraded(component, true)
+				metrics.ComponentDegraded.WithLabelValues(component).Set(1)
+			case <-loggedIn:
+				s.setDegraded(component, false)
+				metrics.ComponentDegraded.WithLabelValues(component).Set(0)
+				start()
+				return
+			}
+		}
+	}()
+}
+
+func (s *Components) setDegraded(component string, degraded bool) {
+	s.degradedMutex.Lock()
+	defer s.degradedMutex.Unlock()
+
+	if s.degraded == nil {
+		s.degraded = map[string]bool{}
+	}
+
+	if degraded {
+		s.degraded[component] = true
+	} else {
+		delete(s.degraded, component)
+	}
+}
+
+// Health returns whether the agent is healthy or degraded, i.e. whether Vault clients are not authenticated or
+// components have been waiting for their Vault client to log in for longer than the timeout.
+func (s *Components) Health() domain.Health {
+	ret := domain.Health{
+		Status: domain.Healthy,
+	}
+
+	if s.Vault != nil {
+		clients, err := s.Vault.GetClients()
+		if err == nil {
+			for _, client := range clients {
+				if !client.Token.IsAuthenticated() {
+					ret.Degraded = append(ret.Degraded, "vault/"+client.Id)
+				}
+			}
+		}
+	}
+
+	s.degradedMutex.Lock()
+	for component := range s.degraded {
+		ret.Degraded = append(ret.Degraded, component)
+	}
+	s.degradedMutex.Unlock()
+
+	if len(ret.Degraded) > 0 {
+		ret.Status = domain.Degraded
+		sort.Strings(ret.Degraded)
+	}
+
+	return ret
 }
 
 func (s *Components) EnabledComponents() []string {
@@ -128,6 +211,9 @@ func (s *Components) EnabledComponents() []string {
 		t := v.Type()
 
 		for i := 0; i < v.NumField(); i++ {
+			if !t.Field(i).IsExported() {
+				continue
+			}
 			fieldValue := v.Field(i)
 			if !fieldValue.IsNil() { // Check if the member is not nil
 				enabledComponents = append(enabledComponents, t.Field(i).Name)
@@ -0,0 +1,107 @@
+package ports
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	cmdvault "github.com/soerenschneider/sc-agent/cmd/vault"
+	"github.com/soerenschneider/sc-agent/internal/config"
+	vault_config "github.com/soerenschneider/sc-agent/internal/config/vault"
+	"github.com/soerenschneider/sc-agent/internal/domain/vault"
+	"github.com/soerenschneider/sc-agent/internal/vaulttest"
+)
+
+type fakeVaultClients struct{}
+
+func (f *fakeVaultClients) GetClients() ([]vault.ClientStatus, error) {
+	return nil, nil
+}
+
+func (f *fakeVaultClients) GetClient(_ string) (vault.ClientStatus, error) {
+	return vault.ClientStatus{}, nil
+}
+
+func (f *fakeVaultClients) RotateSecretId(_ string) (vault.ClientStatus, error) {
+	return vault.ClientStatus{}, nil
+}
+
+func TestComponents_UsesVault(t *testing.T) {
+	tests := []struct {
+		name       string
+		components *Components
+		want       bool
+	}{
+		{
+			name:       "no vault",
+			components: &Components{},
+			want:       false,
+		},
+		{
+			// e.g. only token sinks, vault bearer tokens or transit encryption are configured
+			name:       "vault clients without vault components",
+			components: &Components{Vault: &fakeVaultClients{}},
+			want:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.components.UsesVault(); got != tt.want {
+				t.Errorf("UsesVault() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComponents_StartServicesWritesTokenSinks(t *testing.T) {
+	server := vaulttest.NewServer(t)
+	server.EnableApprole("approle")
+	server.AddApproleRole("approle", "sc-agent", "sc-agent", 24*time.Hour)
+	server.AddSecretId("approle", "sc-agent", "secret-id", time.Now())
+
+	dir := t.TempDir()
+	secretIdFile := filepath.Join(dir, "secret_id")
+	if err := os.WriteFile(secretIdFile, []byte("secret-id"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	sinkFile := filepath.Join(dir, "token")
+
+	// vault is only configured to write a token sink, no component uses it
+	conf := config.Config{
+		Vault: map[string]vault_config.Vault{
+			"sink-only": {
+				Address:      server.URL(),
+				AuthMethod:   "approle",
+				RoleId:       "sc-agent",
+				SecretIdFile: secretIdFile,
+				MountApprole: "approle",
+				TokenSinks:   []vault_config.VaultTokenSink{{Dest: "file://" + sinkFile}},
+			},
+		},
+	}
+	if err := cmdvault.BuildVaultClients(conf); err != nil {
+		t.Fatal(err)
+	}
+	clients, err := cmdvault.BuildVaultClientsService()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	components := &Components{Vault: clients}
+	components.StartServices(ctx, &sync.WaitGroup{}, conf)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if token, err := os.ReadFile(sinkFile); err == nil && strings.HasPrefix(string(token), "hvs.") {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatal("StartServices() did not write the token sink")
+}
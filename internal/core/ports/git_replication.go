@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/soerenschneider/sc-agent/internal/domain/git_replication"
+)
+
+type GitReplication interface {
+	Replicate(ctx context.Context, item git_replication.ReplicationItem) error
+	StartReplication(ctx context.Context)
+	GetReplicationItem(id string) (git_replication.ReplicationItem, error)
+	GetReplicationItems() ([]git_replication.ReplicationItem, error)
+}
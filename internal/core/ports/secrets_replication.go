@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"sync"
 
 	"github.com/soerenschneider/sc-agent/internal/domain/secret_replication"
 )
@@ -20,5 +21,7 @@ type SecretsReplication interface {
 	Replicate(ctx context.Context, syncRequest secret_replication.ReplicationItem) (bool, error)
 	GetReplicationItem(id string) (secret_replication.ReplicationItem, error)
 	GetReplicationItems() []secret_replication.ReplicationItem
-	StartContinuousReplication(ctx context.Context)
+	// StartContinuousReplication periodically replicates all items. Components that need to clean up on shutdown, e.g.
+	// revoking leases of dynamic secrets, are added to the given WaitGroup.
+	StartContinuousReplication(ctx context.Context, wg *sync.WaitGroup)
 }
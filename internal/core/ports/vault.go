@@ -0,0 +1,11 @@
+package ports
+
+import (
+	"github.com/soerenschneider/sc-agent/internal/domain/vault"
+)
+
+type VaultClients interface {
+	GetClients() ([]vault.ClientStatus, error)
+	GetClient(id string) (vault.ClientStatus, error)
+	RotateSecretId(id string) (vault.ClientStatus, error)
+}
@@ -0,0 +1,96 @@
+package git_replication
+
+import (
+	"errors"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/soerenschneider/sc-agent/internal/domain"
+)
+
+type Status int
+
+const (
+	Unknown            Status = iota
+	Synced             Status = iota
+	FailedStatus       Status = iota
+	VerificationFailed Status = iota
+)
+
+var (
+	ErrGitReplicationItemNotFound  = errors.New("could not find item")
+	ErrSignatureVerificationFailed = errors.New("signature verification failed")
+)
+
+type ReplicationConf struct {
+	Id string
+	// Repository is the HTTPS or SSH URL of the repository
+	Repository string
+	// Branch is the branch that is checked out, mutually exclusive with Tag
+	Branch string
+	// Tag is the tag that is checked out, mutually exclusive with Branch
+	Tag string
+	// WorkDir is the local directory the repository is cloned to
+	WorkDir string
+	// KeyringFile is the file containing the armored OpenPGP public keys the checked out commit or tag must be
+	// signed with. Signatures are not verified if it is empty.
+	KeyringFile string
+	Paths       []PathMapping
+	// Schedule defines when the item is replicated, the service's default interval is used if it is nil.
+	Schedule Schedule
+}
+
+// Ref returns the name of the branch or tag that is checked out.
+func (c ReplicationConf) Ref() string {
+	if c.Tag != "" {
+		return c.Tag
+	}
+	return c.Branch
+}
+
+// PathMapping maps a file or directory of the repository to a destination on the local system.
+type PathMapping struct {
+	// Source is the path of the file or directory relative to the root of the repository
+	Source string
+	// Destinations are the configured destinations of the path
+	Destinations []string
+	// IsDir is true if Source is a directory that is mirrored to Destination
+	IsDir       bool
+	Destination StorageImplementation
+}
+
+// Schedule returns the next point in time after the given time an item should be replicated at.
+type Schedule interface {
+	Next(time.Time) time.Time
+}
+
+// AuthProvider returns the auth method that is used to access the remote repository. It is invoked for each
+// replication, so rotated credentials, e.g. ssh certificates that are renewed by the ssh component, are picked up.
+type AuthProvider interface {
+	AuthMethod() (transport.AuthMethod, error)
+}
+
+type ReplicationItem struct {
+	ReplicationConf ReplicationConf
+	PostHooks       []domain.PostHook
+	Status          Status
+	// Auth is used to authenticate against the remote repository, anonymous access is used if it is nil
+	Auth AuthProvider
+
+	// LastAttempt is the point in time of the latest replication attempt, zero if it has not been attempted yet
+	LastAttempt time.Time
+	// LastSuccess is the point in time of the latest successful replication
+	LastSuccess time.Time
+	// LastError is the error of the latest replication attempt, empty if it has been successful
+	LastError string
+	// Commit is the hash of the latest replicated commit
+	Commit string
+	// NextSync is the point in time of the next scheduled replication, zero if it is not scheduled
+	NextSync time.Time
+}
+
+type StorageImplementation interface {
+	Read() ([]byte, error)
+	CanRead() error
+	Write([]byte) error
+}
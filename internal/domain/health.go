@@ -0,0 +1,15 @@
+package domain
+
+type HealthStatus string
+
+const (
+	Healthy  HealthStatus = "healthy"
+	Degraded HealthStatus = "degraded"
+)
+
+// Health describes whether the agent is fully operational. The agent keeps running in a degraded state, e.g. if a
+// Vault instance is unreachable, and lists the affected components.
+type Health struct {
+	Status   HealthStatus
+	Degraded []string
+}
@@ -2,11 +2,14 @@ package http_replication
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"net/http"
 	"regexp"
+	"time"
 
 	"github.com/soerenschneider/sc-agent/internal/config"
 	"github.com/soerenschneider/sc-agent/internal/domain"
@@ -32,18 +35,90 @@ type ReplicationConf struct {
 	Destinations    []string
 	TrimWhitespaces bool
 	FileValidation  *FileValidation
+	// Schedule defines when the item is replicated, the service's default interval is used if it is nil.
+	Schedule Schedule
+}
+
+// Schedule returns the next point in time after the given time an item should be replicated at.
+type Schedule interface {
+	Next(time.Time) time.Time
 }
 
 type FileValidation struct {
 	InvertResult bool
 	Test         string
 	Arg          string
+	// SignatureUrl is the URL of the detached signature for the signature tests, defaults to the source URL with a
+	// test specific suffix.
+	SignatureUrl string
+}
+
+// RequiresSignature returns true if the test verifies a detached signature that needs to be fetched before calling
+// VerifySignature.
+func (f *FileValidation) RequiresSignature() bool {
+	switch f.Test {
+	case config.FileValidationTestMinisign, config.FileValidationTestGpg, config.FileValidationTestCosign:
+		return true
+	default:
+		return false
+	}
+}
+
+// GetSignatureUrl returns the URL of the detached signature for the given source.
+func (f *FileValidation) GetSignatureUrl(source string) string {
+	if f.SignatureUrl != "" {
+		return f.SignatureUrl
+	}
+
+	switch f.Test {
+	case config.FileValidationTestMinisign:
+		return source + ".minisig"
+	case config.FileValidationTestGpg:
+		return source + ".asc"
+	default:
+		return source + ".sig"
+	}
+}
+
+// VerifySignature verifies the detached signature of the given value using the public key that is configured as Arg.
+func (f *FileValidation) VerifySignature(value []byte, signature []byte) (bool, error) {
+	if f.InvertResult {
+		return false, errors.New("signature verification can not be inverted")
+	}
+
+	publicKey, err := readPublicKey(f.Arg)
+	if err != nil {
+		return false, err
+	}
+
+	switch f.Test {
+	case config.FileValidationTestMinisign:
+		err = verifyMinisign(publicKey, value, signature)
+	case config.FileValidationTestGpg:
+		err = verifyGpg(publicKey, value, signature)
+	case config.FileValidationTestCosign:
+		err = verifyCosign(publicKey, value, signature)
+	default:
+		return false, fmt.Errorf("validation type %s does not verify signatures", f.Test)
+	}
+
+	if err != nil {
+		if errors.Is(err, ErrInvalidSignature) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
 }
 
 func (f *FileValidation) Accept(value []byte) (bool, error) {
 	var result bool
 	var err error
 
+	if f.RequiresSignature() {
+		return false, fmt.Errorf("validation type %s requires a signature", f.Test)
+	}
+
 	switch f.Test {
 	case config.FileValidationTestSha256:
 		hash := sha256.Sum256(value)
@@ -81,6 +156,33 @@ type ReplicationItem struct {
 	Destination     StorageImplementation
 	PostHooks       []domain.PostHook
 	Status          Status
+
+	// Client is an optional client that is used instead of the service's default client, e.g. for mTLS.
+	Client Client
+	// Authenticator optionally adds credentials to the requests of this item.
+	Authenticator Authenticator
+
+	// LastAttempt is the point in time of the latest replication attempt, zero if it has not been attempted yet
+	LastAttempt time.Time
+	// LastSuccess is the point in time of the latest successful replication
+	LastSuccess time.Time
+	// LastError is the error of the latest replication attempt, empty if it has been successful
+	LastError string
+	// ContentHash is the sha256 hash of the replicated content
+	ContentHash string
+	// Version is the version of the replicated content as announced by the server using the ETag or Last-Modified
+	// header, empty if unknown
+	Version string
+	// NextSync is the point in time of the next scheduled replication, zero if it is not scheduled
+	NextSync time.Time
+}
+
+type Client interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+type Authenticator interface {
+	Authenticate(ctx context.Context, req *http.Request) error
 }
 
 type StorageImplementation interface {
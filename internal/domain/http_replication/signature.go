@@ -0,0 +1,184 @@
+package http_replication
+
+import (
+	"bufio"
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	minisignAlgLegacy    = "Ed"
+	minisignAlgPrehashed = "ED"
+	minisignKeyIdLen     = 8
+	minisignTrustedLabel = "trusted comment: "
+)
+
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// readPublicKey returns the public key that is either given inline or as absolute path to a file.
+func readPublicKey(arg string) ([]byte, error) {
+	if !filepath.IsAbs(arg) {
+		return []byte(arg), nil
+	}
+
+	data, err := os.ReadFile(arg)
+	if err != nil {
+		return nil, fmt.Errorf("could not read public key: %w", err)
+	}
+	return data, nil
+}
+
+// verifyMinisign verifies a minisign signature, including its trusted comment, using the minisign public key.
+func verifyMinisign(publicKey, value, signature []byte) error {
+	keyData, err := decodeMinisignLine(publicKey)
+	if err != nil {
+		return fmt.Errorf("could not decode minisign public key: %w", err)
+	}
+	if len(keyData) != 2+minisignKeyIdLen+ed25519.PublicKeySize || string(keyData[:2]) != minisignAlgLegacy {
+		return errors.New("invalid minisign public key")
+	}
+	keyId := keyData[2 : 2+minisignKeyIdLen]
+	key := ed25519.PublicKey(keyData[2+minisignKeyIdLen:])
+
+	lines := nonEmptyLines(signature)
+	if len(lines) != 4 || !strings.HasPrefix(lines[2], minisignTrustedLabel) {
+		return errors.New("malformed minisign signature")
+	}
+
+	sigData, err := base64.StdEncoding.DecodeString(lines[1])
+	if err != nil || len(sigData) != 2+minisignKeyIdLen+ed25519.SignatureSize {
+		return errors.New("malformed minisign signature")
+	}
+	globalSig, err := base64.StdEncoding.DecodeString(lines[3])
+	if err != nil || len(globalSig) != ed25519.SignatureSize {
+		return errors.New("malformed minisign global signature")
+	}
+
+	if !bytes.Equal(sigData[2:2+minisignKeyIdLen], keyId) {
+		return fmt.Errorf("%w: signed by a different key", ErrInvalidSignature)
+	}
+
+	message := value
+	switch string(sigData[:2]) {
+	case minisignAlgLegacy:
+	case minisignAlgPrehashed:
+		digest := blake2b.Sum512(value)
+		message = digest[:]
+	default:
+		return fmt.Errorf("unsupported minisign algorithm %q", sigData[:2])
+	}
+
+	sig := sigData[2+minisignKeyIdLen:]
+	if !ed25519.Verify(key, message, sig) {
+		return ErrInvalidSignature
+	}
+
+	trustedComment := strings.TrimPrefix(lines[2], minisignTrustedLabel)
+	if !ed25519.Verify(key, append(bytes.Clone(sig), trustedComment...), globalSig) {
+		return fmt.Errorf("%w: trusted comment has been tampered with", ErrInvalidSignature)
+	}
+
+	return nil
+}
+
+// decodeMinisignLine decodes the base64 encoded key, skipping an optional "untrusted comment" line.
+func decodeMinisignLine(data []byte) ([]byte, error) {
+	lines := nonEmptyLines(data)
+	if len(lines) == 0 {
+		return nil, errors.New("empty key")
+	}
+	return base64.StdEncoding.DecodeString(lines[len(lines)-1])
+}
+
+func nonEmptyLines(data []byte) []string {
+	var ret []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" {
+			ret = append(ret, line)
+		}
+	}
+	return ret
+}
+
+// verifyGpg verifies an armored or binary detached OpenPGP signature using an armored or binary public keyring.
+func verifyGpg(publicKey, value, signature []byte) error {
+	var keyring openpgp.EntityList
+	var err error
+	if isArmored(publicKey) {
+		keyring, err = openpgp.ReadArmoredKeyRing(bytes.NewReader(publicKey))
+	} else {
+		keyring, err = openpgp.ReadKeyRing(bytes.NewReader(publicKey))
+	}
+	if err != nil {
+		return fmt.Errorf("could not read gpg public key: %w", err)
+	}
+
+	if isArmored(signature) {
+		_, err = openpgp.CheckArmoredDetachedSignature(keyring, bytes.NewReader(value), bytes.NewReader(signature), nil)
+	} else {
+		_, err = openpgp.CheckDetachedSignature(keyring, bytes.NewReader(value), bytes.NewReader(signature), nil)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	return nil
+}
+
+func isArmored(data []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte("-----BEGIN"))
+}
+
+// verifyCosign verifies a base64 encoded signature as created by "cosign sign-blob --key" using the PEM encoded public
+// key. Keyless signatures are not supported.
+func verifyCosign(publicKey, value, signature []byte) error {
+	block, _ := pem.Decode(publicKey)
+	if block == nil {
+		return errors.New("could not decode cosign public key")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("could not parse cosign public key: %w", err)
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(signature)))
+	if err != nil {
+		return fmt.Errorf("could not decode cosign signature: %w", err)
+	}
+
+	digest := sha256.Sum256(value)
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(k, digest[:], sig) {
+			return ErrInvalidSignature
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(k, value, sig) {
+			return ErrInvalidSignature
+		}
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], sig); err != nil {
+			return ErrInvalidSignature
+		}
+	default:
+		return fmt.Errorf("unsupported cosign public key type %T", key)
+	}
+
+	return nil
+}
@@ -0,0 +1,228 @@
+package http_replication
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"golang.org/x/crypto/blake2b"
+)
+
+type minisignKey struct {
+	id   []byte
+	priv ed25519.PrivateKey
+}
+
+func newMinisignKey(t *testing.T) minisignKey {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return minisignKey{id: []byte("12345678"), priv: priv}
+}
+
+func (k minisignKey) publicKey() string {
+	data := append([]byte("Ed"), k.id...)
+	data = append(data, k.priv.Public().(ed25519.PublicKey)...)
+	return "untrusted comment: minisign public key\n" + base64.StdEncoding.EncodeToString(data) + "\n"
+}
+
+func (k minisignKey) sign(value []byte, prehashed bool, trustedComment string) []byte {
+	alg := "Ed"
+	message := value
+	if prehashed {
+		alg = "ED"
+		digest := blake2b.Sum512(value)
+		message = digest[:]
+	}
+
+	sig := ed25519.Sign(k.priv, message)
+	sigData := append([]byte(alg), k.id...)
+	sigData = append(sigData, sig...)
+	globalSig := ed25519.Sign(k.priv, append(bytes.Clone(sig), trustedComment...))
+
+	return []byte(fmt.Sprintf("untrusted comment: signature\n%s\ntrusted comment: %s\n%s\n",
+		base64.StdEncoding.EncodeToString(sigData), trustedComment, base64.StdEncoding.EncodeToString(globalSig)))
+}
+
+func TestFileValidation_VerifySignatureMinisign(t *testing.T) {
+	key := newMinisignKey(t)
+	otherKey := newMinisignKey(t)
+	otherKey.id = []byte("87654321")
+	value := []byte("hello")
+
+	tamperedComment := key.sign(value, true, "timestamp:1")
+	tamperedComment = bytes.Replace(tamperedComment, []byte("timestamp:1"), []byte("timestamp:2"), 1)
+
+	tests := []struct {
+		name      string
+		signature []byte
+		value     []byte
+		want      bool
+		wantErr   bool
+	}{
+		{
+			name:      "legacy signature",
+			signature: key.sign(value, false, "timestamp:1"),
+			value:     value,
+			want:      true,
+		},
+		{
+			name:      "prehashed signature",
+			signature: key.sign(value, true, "timestamp:1"),
+			value:     value,
+			want:      true,
+		},
+		{
+			name:      "modified content",
+			signature: key.sign(value, true, "timestamp:1"),
+			value:     []byte("hello!"),
+			want:      false,
+		},
+		{
+			name:      "different key",
+			signature: otherKey.sign(value, true, "timestamp:1"),
+			value:     value,
+			want:      false,
+		},
+		{
+			name:      "tampered trusted comment",
+			signature: tamperedComment,
+			value:     value,
+			want:      false,
+		},
+		{
+			name:      "malformed signature",
+			signature: []byte("not a signature"),
+			value:     value,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &FileValidation{
+				Test: "minisign",
+				Arg:  key.publicKey(),
+			}
+			got, err := f.VerifySignature(tt.value, tt.signature)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifySignature() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("VerifySignature() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileValidation_VerifySignatureCosign(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKey := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	// the public key is read from disk if an absolute path is given
+	keyFile := filepath.Join(t.TempDir(), "cosign.pub")
+	if err := os.WriteFile(keyFile, publicKey, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	value := []byte("hello")
+	digest := sha256.Sum256(value)
+	sig, err := ecdsa.SignASN1(rand.Reader, priv, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature := []byte(base64.StdEncoding.EncodeToString(sig))
+
+	for _, arg := range []string{string(publicKey), keyFile} {
+		f := &FileValidation{Test: "cosign", Arg: arg}
+		if got, err := f.VerifySignature(value, signature); err != nil || !got {
+			t.Errorf("VerifySignature() = %v, %v, want true", got, err)
+		}
+		if got, err := f.VerifySignature([]byte("hello!"), signature); err != nil || got {
+			t.Errorf("VerifySignature() of modified content = %v, %v, want false", got, err)
+		}
+	}
+
+	inverted := &FileValidation{Test: "cosign", Arg: string(publicKey), InvertResult: true}
+	if _, err := inverted.VerifySignature(value, signature); err == nil {
+		t.Error("expected error for inverted signature verification")
+	}
+}
+
+func TestFileValidation_VerifySignatureGpg(t *testing.T) {
+	entity, err := openpgp.NewEntity("sc-agent", "", "sc-agent@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var publicKey bytes.Buffer
+	w, err := armor.Encode(&publicKey, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := entity.Serialize(w); err != nil {
+		t.Fatal(err)
+	}
+	_ = w.Close()
+
+	value := []byte("hello")
+	var armoredSig, binarySig bytes.Buffer
+	if err := openpgp.ArmoredDetachSign(&armoredSig, entity, bytes.NewReader(value), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := openpgp.DetachSign(&binarySig, entity, bytes.NewReader(value), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	f := &FileValidation{Test: "gpg", Arg: publicKey.String()}
+	for _, sig := range [][]byte{armoredSig.Bytes(), binarySig.Bytes()} {
+		if got, err := f.VerifySignature(value, sig); err != nil || !got {
+			t.Errorf("VerifySignature() = %v, %v, want true", got, err)
+		}
+		if got, err := f.VerifySignature([]byte("hello!"), sig); err != nil || got {
+			t.Errorf("VerifySignature() of modified content = %v, %v, want false", got, err)
+		}
+	}
+}
+
+func TestFileValidation_GetSignatureUrl(t *testing.T) {
+	tests := []struct {
+		test         string
+		signatureUrl string
+		want         string
+	}{
+		{test: "minisign", want: "https://example.com/ca.crt.minisig"},
+		{test: "gpg", want: "https://example.com/ca.crt.asc"},
+		{test: "cosign", want: "https://example.com/ca.crt.sig"},
+		{test: "gpg", signatureUrl: "https://example.com/sigs/ca.crt.gpg", want: "https://example.com/sigs/ca.crt.gpg"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.test, func(t *testing.T) {
+			f := &FileValidation{Test: tt.test, SignatureUrl: tt.signatureUrl}
+			if got := f.GetSignatureUrl("https://example.com/ca.crt"); got != tt.want {
+				t.Errorf("GetSignatureUrl() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
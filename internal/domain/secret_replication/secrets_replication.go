@@ -1,36 +1,106 @@
 package secret_replication
 
-import "errors"
+import (
+	"errors"
+	"time"
 
-var ErrSecretsReplicationItemNotFound = errors.New("could not find item")
+	"github.com/soerenschneider/sc-agent/internal/domain"
+)
+
+var (
+	ErrSecretsReplicationItemNotFound = errors.New("could not find item")
+	ErrDynamicSecretNotReplicable     = errors.New("dynamic secrets are replicated according to their lease")
+	ErrSecretDeleted                  = errors.New("secret has been deleted")
+	ErrLeasedSecretNotReadable        = errors.New("secrets with a lease can only be replicated as dynamic item")
+)
 
 type Formatter interface {
 	Format(data map[string]any) ([]byte, error)
 }
 
+// SecretReader reads secrets on behalf of formatters that render content from more than a single secret.
+type SecretReader interface {
+	// ReadKv2Secret reads the data of a secret stored in the configured KV v2 engine.
+	ReadKv2Secret(path string) (map[string]any, error)
+	// ReadSecret reads the data of an arbitrary static path, e.g. from a different secrets engine. Secrets with a lease,
+	// e.g. dynamic database credentials, are rejected.
+	ReadSecret(path string) (map[string]any, error)
+}
+
+// MultiSecretFormatter is a Formatter that is able to reference secrets other than the item's secret path.
+type MultiSecretFormatter interface {
+	Formatter
+	FormatSecrets(data map[string]any, reader SecretReader) ([]byte, error)
+}
+
 type StorageImplementation interface {
 	Read() ([]byte, error)
 	CanRead() error
 	Write([]byte) error
 }
 
+// Deleter is implemented by storage implementations that are able to remove their data.
+type Deleter interface {
+	Delete() error
+}
+
 type SecretReplicationStatus int
 
 const (
 	UnknownStatus      SecretReplicationStatus = iota
 	FailedStatus       SecretReplicationStatus = iota
 	SynchronizedStatus SecretReplicationStatus = iota
+	DeletedStatus      SecretReplicationStatus = iota
+)
+
+// OnDeletePolicy defines how a replicated secret is treated after it has been deleted or destroyed in Vault.
+type OnDeletePolicy string
+
+const (
+	// OnDeleteKeep keeps the local copy of the secret
+	OnDeleteKeep OnDeletePolicy = "keep"
+	// OnDeleteRemove removes the local copy of the secret
+	OnDeleteRemove OnDeletePolicy = "remove"
+	// OnDeleteTombstone replaces the local copy of the secret with empty content
+	OnDeleteTombstone OnDeletePolicy = "tombstone"
+	// OnDeleteAlert keeps the local copy of the secret but treats the deletion as error
+	OnDeleteAlert OnDeletePolicy = "alert"
 )
 
 type ReplicationConf struct {
 	Id         string
 	SecretPath string
 	DestUri    string
+	// Backend is the name of the secret backend the secret is read from, the default Vault client is used if empty
+	Backend string
+
+	// Dynamic denotes a dynamic secret that is read from a logical path and whose lease is managed
+	Dynamic bool
+	// LeaseIncrement is the increment requested when renewing the lease of a dynamic secret
+	LeaseIncrement time.Duration
+	// OnDelete defines how the local copy is treated after the secret has been deleted
+	OnDelete OnDeletePolicy
 }
 
 type ReplicationItem struct {
 	ReplicationConf ReplicationConf
 	Formatter       Formatter
 	Destination     StorageImplementation
+	PostHooks       []domain.PostHook
 	Status          SecretReplicationStatus
+
+	// ReplicatedVersion is the KV v2 version of the secret that has been replicated, 0 if unknown
+	ReplicatedVersion int
+	// ReferencedSecrets contains the KV v2 versions of all secrets the replicated item has been rendered from
+	ReferencedSecrets map[string]int
+	// LeaseExpiry is the point in time the lease of a dynamic secret expires, zero if not applicable
+	LeaseExpiry time.Time
+	// ContentHash is the sha256 hash of the content that has been written to the destination
+	ContentHash string
+	// LastAttempt is the point in time of the latest replication attempt, zero if it has not been attempted yet
+	LastAttempt time.Time
+	// LastSuccess is the point in time of the latest successful replication
+	LastSuccess time.Time
+	// LastError is the error of the latest replication attempt, empty if it has been successful
+	LastError string
 }
@@ -0,0 +1,63 @@
+package vault
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrVaultClientNotFound      = errors.New("vault client not found")
+	ErrSecretIdRotationDisabled = errors.New("secret_id rotation not enabled for vault client")
+)
+
+type ClientStatus struct {
+	Id         string
+	AuthMethod string
+	// Address is the address of the Vault endpoint that is currently used
+	Address  string
+	Token    TokenStatus
+	SecretId *SecretIdStatus
+}
+
+// TokenStatus describes the health of the token the client is using.
+type TokenStatus struct {
+	// Ttl is the lease duration of the token as returned by the last login or renewal
+	Ttl time.Duration
+	// Expiration is the point in time the token expires if it is not renewed, it is zero for tokens without TTL
+	Expiration    time.Time
+	Renewable     bool
+	LastLogin     time.Time
+	LastRenewal   time.Time
+	LastError     string
+	LastErrorTime time.Time
+}
+
+// IsAuthenticated returns whether the client has logged in and its token has not expired yet.
+func (t TokenStatus) IsAuthenticated() bool {
+	if t.LastLogin.IsZero() {
+		return false
+	}
+
+	return t.Expiration.IsZero() || time.Now().Before(t.Expiration)
+}
+
+// TtlRemaining returns the remaining lifetime of the token.
+func (t TokenStatus) TtlRemaining() time.Duration {
+	if t.Expiration.IsZero() {
+		return 0
+	}
+
+	return max(0, time.Until(t.Expiration))
+}
+
+// SecretIdStatus describes the state of the AppRole secret_id rotation.
+type SecretIdStatus struct {
+	Accessor   string
+	Expiration time.Time
+	// NextRotation is the point in time after which the secret_id is rotated on its next check
+	NextRotation  time.Time
+	LastCheck     time.Time
+	LastRotation  time.Time
+	LastError     string
+	LastErrorTime time.Time
+}
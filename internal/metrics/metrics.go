@@ -45,11 +45,23 @@ var (
 		Help:      "Heartbeat of sc-agent",
 	})
 
+	ComponentDegraded = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "component_degraded_bool",
+		Help:      "Whether a component is degraded because its Vault client has not logged in within the login timeout",
+	}, []string{"component"})
+
 	CertStorageErrors = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: namespace,
 		Name:      "certstorage_errors_total",
 		Help:      "Total errors of component cert-storage",
 	}, []string{"error"})
+
+	StorageRollbacks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "storage_rollbacks_total",
+		Help:      "Total rollbacks of written files after their post hooks failed",
+	}, []string{"component", "id", "result"})
 )
 
 func init() {
@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const subsystemGitReplication = "git_replication"
+
+var (
+	GitReplicationTimestamp = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystemGitReplication,
+		Name:      "timestamp_seconds",
+		Help:      "Timestamp of the last attempt to replicate an item",
+	}, []string{"id"})
+
+	GitReplicationCommitTimestamp = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystemGitReplication,
+		Name:      "commit_timestamp_seconds",
+		Help:      "Committer timestamp of the replicated commit",
+	}, []string{"id"})
+
+	GitReplicationUpdates = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystemGitReplication,
+		Name:      "updates_total",
+		Help:      "Total number of replications that changed at least one destination",
+	}, []string{"id"})
+
+	GitReplicationErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystemGitReplication,
+		Name:      "errors_total",
+		Help:      "Errors while replicating",
+	}, []string{"id", "error"})
+)
@@ -35,4 +35,11 @@ var (
 		Name:      "errors_total",
 		Help:      "Errors while replicating",
 	}, []string{"id", "error"})
+
+	HttpReplicationNotModified = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystemHttpReplication,
+		Name:      "not_modified_total",
+		Help:      "Total number of conditional requests that have been answered with 304 Not Modified",
+	}, []string{"id"})
 )
@@ -0,0 +1,31 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const subsystemVaultEndpoints = "vault_endpoints"
+
+var (
+	VaultEndpointActive = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystemVaultEndpoints,
+		Name:      "active_bool",
+		Help:      "Whether the address is the endpoint currently used by the client",
+	}, []string{"name", "address"})
+
+	VaultEndpointHealthy = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystemVaultEndpoints,
+		Name:      "healthy_bool",
+		Help:      "Whether the endpoint is initialized, unsealed and able to serve requests",
+	}, []string{"name", "address"})
+
+	VaultEndpointFailovers = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystemVaultEndpoints,
+		Name:      "failovers_total",
+		Help:      "Number of times the client switched to a different endpoint",
+	}, []string{"name"})
+)
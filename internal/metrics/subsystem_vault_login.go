@@ -36,6 +36,20 @@ var (
 		Help:      "Expiration date of the token",
 	}, []string{"name"})
 
+	VaultAuthenticated = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystemVaultRenewal,
+		Name:      "authenticated_bool",
+		Help:      "Whether the client is logged in to Vault",
+	}, []string{"name"})
+
+	VaultTokenCacheRestores = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystemVaultRenewal,
+		Name:      "token_cache_restores_total",
+		Help:      "Number of cached tokens that have been renewed instead of performing a login",
+	}, []string{"name"})
+
 	VaultTokenRenewErrors = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: namespace,
 		Subsystem: subsystemVaultRenewal,
@@ -49,4 +63,18 @@ var (
 		Name:      "renewals_total",
 		Help:      "Expiration date of the token",
 	}, []string{"name"})
+
+	VaultTokenSinkWrites = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystemVaultRenewal,
+		Name:      "token_sink_writes_total",
+		Help:      "Number of tokens written to token sinks",
+	}, []string{"name", "sink"})
+
+	VaultTokenSinkErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystemVaultRenewal,
+		Name:      "token_sink_errors_total",
+		Help:      "Number of errors while writing tokens to token sinks",
+	}, []string{"name", "sink"})
 )
@@ -22,6 +22,55 @@ var (
 		Help:      "Total amount of secrets read",
 	}, []string{"path"})
 
+	SecretsVersion = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystemVaultSecretSyncer,
+		Name:      "secret_version",
+		Help:      "KV v2 version of the replicated secret",
+	}, []string{"path"})
+
+	SecretsEventsReceived = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystemVaultSecretSyncer,
+		Name:      "vault_events_received_total",
+		Help:      "Total amount of Vault events received for secrets",
+	}, []string{"path"})
+
+	DynamicSecretsLeaseRenewals = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystemVaultSecretSyncer,
+		Name:      "dynamic_secrets_lease_renewals_total",
+		Help:      "Total amount of renewed leases of dynamic secrets",
+	}, []string{"path"})
+
+	DynamicSecretsRotations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystemVaultSecretSyncer,
+		Name:      "dynamic_secrets_rotations_total",
+		Help:      "Total amount of rotated credentials of dynamic secrets",
+	}, []string{"path"})
+
+	DynamicSecretsLeaseExpiry = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystemVaultSecretSyncer,
+		Name:      "dynamic_secrets_lease_expiry_timestamp_seconds",
+		Help:      "Timestamp of the expiry of the lease of a dynamic secret",
+	}, []string{"path"})
+
+	SecretsDeleted = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystemVaultSecretSyncer,
+		Name:      "secrets_deleted_total",
+		Help:      "Total amount of replicated secrets that have been noticed to be deleted, by the applied on_delete policy",
+	}, []string{"id", "policy"})
+
+	SecretsStatus = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: subsystemVaultSecretSyncer,
+		Name:      "status",
+		Help:      "Status of a replication item, 0 = unknown, 1 = failed, 2 = synchronized, 3 = deleted",
+	}, []string{"id"})
+
 	SecretReplicationErrors = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: namespace,
 		Subsystem: subsystemVaultSecretSyncer,
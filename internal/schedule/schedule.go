@@ -0,0 +1,49 @@
+package schedule
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Schedule returns the next point in time after the given time a task should run at.
+type Schedule interface {
+	Next(time.Time) time.Time
+}
+
+// Interval schedules tasks at a fixed interval. To avoid all agents hitting a server at the same time, the interval is
+// randomly spread by up to half the jitter in both directions.
+type Interval struct {
+	Interval time.Duration
+	Jitter   time.Duration
+}
+
+const minInterval = 10 * time.Second
+
+// NewInterval returns a schedule for the given interval using a jitter of 10% of the interval.
+func NewInterval(interval time.Duration) (Interval, error) {
+	if interval < minInterval {
+		return Interval{}, fmt.Errorf("interval must be at least %v", minInterval)
+	}
+
+	return Interval{
+		Interval: interval,
+		Jitter:   interval / 10,
+	}, nil
+}
+
+func (s Interval) Next(t time.Time) time.Time {
+	next := t.Add(s.Interval)
+	if s.Jitter <= 0 {
+		return next
+	}
+
+	return next.Add(-s.Jitter / 2).Add(rand.N(s.Jitter)) // #nosec G404
+}
+
+// NewCron parses a standard cron expression with five fields, e.g. "0 4 * * *".
+func NewCron(spec string) (Schedule, error) {
+	return cron.ParseStandard(spec)
+}
@@ -0,0 +1,42 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInterval_Next(t *testing.T) {
+	schedule, err := NewInterval(time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	for i := 0; i < 100; i++ {
+		next := schedule.Next(now)
+		if next.Before(now.Add(57*time.Minute)) || next.After(now.Add(63*time.Minute)) {
+			t.Fatalf("Next() = %v, expected to be within 1h +/- 3m of %v", next, now)
+		}
+	}
+
+	if _, err := NewInterval(time.Second); err == nil {
+		t.Error("expected error for interval below minimum")
+	}
+}
+
+func TestNewCron(t *testing.T) {
+	schedule, err := NewCron("30 4 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.Local)
+	want := time.Date(2024, 6, 2, 4, 30, 0, 0, time.Local)
+	if got := schedule.Next(now); !got.Equal(want) {
+		t.Errorf("Next() = %v, want %v", got, want)
+	}
+
+	if _, err := NewCron("every day"); err == nil {
+		t.Error("expected error for invalid cron expression")
+	}
+}
@@ -107,7 +107,7 @@ func (s *Service) ReadAcme(ctx context.Context, managedCertConfig domain.Managed
 	metrics.AcmeReadRequests.WithLabelValues(commonName).Inc()
 	metrics.AcmeRequestsTimestamp.WithLabelValues(commonName).SetToCurrentTime()
 
-	storage, ok := s.certStorage[commonName]
+	certStorage, ok := s.certStorage[commonName]
 	if !ok {
 		metrics.AcmeErrors.WithLabelValues(commonName, "no_storage").Inc()
 		return ErrCertConfigNotFound
@@ -132,7 +132,7 @@ func (s *Service) ReadAcme(ctx context.Context, managedCertConfig domain.Managed
 
 	_, found := s.cached[commonName]
 	if !found {
-		existingCert, err := storage.ReadCert()
+		existingCert, err := certStorage.ReadCert()
 		if err == nil {
 			s.cached[commonName] = hash(existingCert.Raw)
 		}
@@ -147,7 +147,7 @@ func (s *Service) ReadAcme(ctx context.Context, managedCertConfig domain.Managed
 	s.cached[commonName] = certHash
 
 	log.Info().Str(logComponent, acmeServiceComponent).Str(logCommonName, commonName).Msg("writing cert data")
-	if err := storage.WriteCert(cert); err != nil {
+	if err := certStorage.WriteCert(cert); err != nil {
 		metrics.AcmeErrors.WithLabelValues(commonName, "write_cert").Inc()
 		return fmt.Errorf("could not write acme cert to disk: %w", err)
 	}
@@ -156,6 +156,11 @@ func (s *Service) ReadAcme(ctx context.Context, managedCertConfig domain.Managed
 		err := pkg.RunPostIssueHooks(managedCertConfig.PostHooks)
 		if err != nil {
 			metrics.AcmeErrors.WithLabelValues(commonName, "run_hooks").Inc()
+			// the cached hash is removed so the certificate is written again on the next run
+			delete(s.cached, commonName)
+			if rollbackErr := storage.Rollback(ctx, acmeServiceComponent, commonName, err, certStorage); rollbackErr != nil {
+				metrics.AcmeErrors.WithLabelValues(commonName, "rollback").Inc()
+			}
 		}
 		return err
 	}
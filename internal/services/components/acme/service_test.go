@@ -0,0 +1,117 @@
+package acme
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/soerenschneider/sc-agent/internal/config/vault"
+	"github.com/soerenschneider/sc-agent/internal/vaulttest"
+)
+
+// putAcmeCert issues a certificate using the PKI engine and stores it in the KV v2 engine like acmevault does.
+func putAcmeCert(t *testing.T, server *vaulttest.Server, commonName string) string {
+	t.Helper()
+
+	issued, err := server.Client().Logical().Write("pki/issue/acme", map[string]any{"common_name": commonName})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert := issued.Data["certificate"].(string)
+	encode := func(key string) string {
+		return base64.StdEncoding.EncodeToString([]byte(issued.Data[key].(string) + "\n"))
+	}
+
+	prefix := "acmevault/prod/client/" + commonName
+	server.PutKv2("secret", prefix+"/certificate", map[string]any{
+		acmevaultKeyCertificate: encode("certificate"),
+		acmevaultKeyIssuer:      encode("issuing_ca"),
+		acmevaultVersion:        "v1",
+	})
+	server.PutKv2("secret", prefix+"/privatekey", map[string]any{
+		acmevaultKeyPrivateKey: encode("private_key"),
+	})
+
+	return cert
+}
+
+func TestService_ReadAcmeLifecycle(t *testing.T) {
+	server := vaulttest.NewServer(t)
+	server.EnablePki("pki")
+	server.EnableKv2("secret")
+
+	client, err := NewVaultClient(server.Client().Logical())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	conf := vault.Acme{
+		MountPath: "secret",
+		ManagedCerts: []vault.AcmeCertConfig{
+			{
+				CommonName: "example.com",
+				Storage: []vault.CertStorage{
+					{
+						CertFile: filepath.Join(dir, "cert.pem"),
+						KeyFile:  filepath.Join(dir, "key.pem"),
+						CaFile:   filepath.Join(dir, "ca.pem"),
+					},
+				},
+			},
+		},
+	}
+
+	service, err := NewService(client, conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		name  string
+		renew bool
+	}{
+		{
+			name:  "initial certificate",
+			renew: true,
+		},
+		{
+			name: "unchanged certificate",
+		},
+		{
+			name:  "renewed certificate",
+			renew: true,
+		},
+	}
+
+	var want string
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			if step.renew {
+				want = putAcmeCert(t, server, "example.com")
+			}
+
+			if err := service.ReadAcme(context.Background(), conf.ManagedCerts[0].ToDomainModel()); err != nil {
+				t.Fatalf("ReadAcme() error = %v", err)
+			}
+
+			got, err := os.ReadFile(filepath.Join(dir, "cert.pem"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.TrimSpace(string(got)) != strings.TrimSpace(want) {
+				t.Errorf("ReadAcme() wrote %q, want %q", got, want)
+			}
+
+			for _, file := range []string{"key.pem", "ca.pem"} {
+				if _, err := os.Stat(filepath.Join(dir, file)); err != nil {
+					t.Errorf("ReadAcme() did not write %s: %v", file, err)
+				}
+			}
+		})
+	}
+}
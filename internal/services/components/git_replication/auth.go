@@ -0,0 +1,121 @@
+package git_replication
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"golang.org/x/crypto/ssh"
+)
+
+// SshKeyAuth authenticates using a private key and an optional certificate. Both are read from disk for every
+// replication, so keys and certificates that are rotated, e.g. by the ssh component, are picked up without a restart.
+type SshKeyAuth struct {
+	user           string
+	keyFile        string
+	certFile       string
+	knownHostsFile string
+}
+
+func NewSshKeyAuth(user, keyFile, certFile, knownHostsFile string) (*SshKeyAuth, error) {
+	if keyFile == "" {
+		return nil, errors.New("empty key file passed")
+	}
+
+	if user == "" {
+		user = gitssh.DefaultUsername
+	}
+
+	return &SshKeyAuth{
+		user:           user,
+		keyFile:        keyFile,
+		certFile:       certFile,
+		knownHostsFile: knownHostsFile,
+	}, nil
+}
+
+func (a *SshKeyAuth) AuthMethod() (transport.AuthMethod, error) {
+	auth, err := gitssh.NewPublicKeysFromFile(a.user, a.keyFile, "")
+	if err != nil {
+		return nil, fmt.Errorf("could not read ssh key: %w", err)
+	}
+
+	if a.certFile != "" {
+		auth.Signer, err = newCertSigner(a.certFile, auth.Signer)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// go-git falls back to the known_hosts files of the user if no callback is set
+	if a.knownHostsFile != "" {
+		auth.HostKeyCallback, err = gitssh.NewKnownHostsCallback(a.knownHostsFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not read known hosts: %w", err)
+		}
+	}
+
+	return auth, nil
+}
+
+func newCertSigner(certFile string, signer ssh.Signer) (ssh.Signer, error) {
+	data, err := os.ReadFile(certFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not read ssh certificate: %w", err)
+	}
+
+	pubKey, _, _, _, err := ssh.ParseAuthorizedKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse ssh certificate: %w", err)
+	}
+
+	cert, ok := pubKey.(*ssh.Certificate)
+	if !ok {
+		return nil, fmt.Errorf("%q does not contain a ssh certificate", certFile)
+	}
+
+	return ssh.NewCertSigner(cert, signer)
+}
+
+// BasicAuth authenticates using HTTP basic auth, e.g. with a personal access token. The password is read from a file
+// for every replication, so rotated passwords are picked up automatically.
+type BasicAuth struct {
+	username     string
+	passwordFile string
+}
+
+func NewBasicAuth(username, passwordFile string) (*BasicAuth, error) {
+	if username == "" {
+		return nil, errors.New("empty username passed")
+	}
+
+	if passwordFile == "" {
+		return nil, errors.New("empty password file passed")
+	}
+
+	return &BasicAuth{
+		username:     username,
+		passwordFile: passwordFile,
+	}, nil
+}
+
+func (a *BasicAuth) AuthMethod() (transport.AuthMethod, error) {
+	data, err := os.ReadFile(a.passwordFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not read password: %w", err)
+	}
+
+	password := strings.TrimSpace(string(data))
+	if password == "" {
+		return nil, fmt.Errorf("file %q is empty", a.passwordFile)
+	}
+
+	return &githttp.BasicAuth{
+		Username: a.username,
+		Password: password,
+	}, nil
+}
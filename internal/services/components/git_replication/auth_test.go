@@ -0,0 +1,111 @@
+package git_replication
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestSshKeyAuth_AuthMethod(t *testing.T) {
+	dir := t.TempDir()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := ssh.MarshalPrivateKey(priv, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFile := filepath.Join(dir, "id_ed25519")
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(block), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, caPriv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caSigner, err := ssh.NewSignerFromKey(caPriv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert := &ssh.Certificate{
+		Key:             sshPub,
+		CertType:        ssh.UserCert,
+		ValidPrincipals: []string{"git"},
+		ValidBefore:     ssh.CertTimeInfinity,
+	}
+	if err := cert.SignCert(rand.Reader, caSigner); err != nil {
+		t.Fatal(err)
+	}
+	certFile := filepath.Join(dir, "id_ed25519-cert.pub")
+	if err := os.WriteFile(certFile, ssh.MarshalAuthorizedKey(cert), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	auth, err := NewSshKeyAuth("", keyFile, certFile, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	method, err := auth.AuthMethod()
+	if err != nil {
+		t.Fatalf("AuthMethod() unexpected error: %v", err)
+	}
+
+	keys, ok := method.(*gitssh.PublicKeys)
+	if !ok {
+		t.Fatalf("expected *ssh.PublicKeys, got %T", method)
+	}
+	if keys.User != gitssh.DefaultUsername {
+		t.Errorf("expected user %q, got %q", gitssh.DefaultUsername, keys.User)
+	}
+	if _, isCert := keys.Signer.PublicKey().(*ssh.Certificate); !isCert {
+		t.Error("expected signer to present the certificate")
+	}
+
+	// a public key is not accepted as certificate
+	if err := os.WriteFile(certFile, ssh.MarshalAuthorizedKey(sshPub), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := auth.AuthMethod(); err == nil {
+		t.Error("expected error for file not containing a certificate")
+	}
+}
+
+func TestBasicAuth_AuthMethod(t *testing.T) {
+	passwordFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(passwordFile, []byte("secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	auth, err := NewBasicAuth("deploy", passwordFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	method, err := auth.AuthMethod()
+	if err != nil {
+		t.Fatalf("AuthMethod() unexpected error: %v", err)
+	}
+	basic, ok := method.(*githttp.BasicAuth)
+	if !ok || basic.Username != "deploy" || basic.Password != "secret" {
+		t.Errorf("unexpected auth method %v", method)
+	}
+
+	if err := os.WriteFile(passwordFile, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := auth.AuthMethod(); err == nil {
+		t.Error("expected error for empty password file")
+	}
+}
@@ -0,0 +1,504 @@
+package git_replication
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/rs/zerolog/log"
+	"github.com/soerenschneider/sc-agent/internal/domain/git_replication"
+	"github.com/soerenschneider/sc-agent/internal/metrics"
+	"github.com/soerenschneider/sc-agent/internal/schedule"
+	"github.com/soerenschneider/sc-agent/internal/storage"
+	"github.com/soerenschneider/sc-agent/pkg"
+)
+
+const (
+	logComponent            = "component"
+	gitReplicationComponent = "git-replication"
+	remoteName              = "origin"
+	defaultInterval         = 10 * time.Minute
+	defaultJitter           = 5 * time.Minute
+)
+
+// ContentHasher is implemented by destinations that can not be read back, e.g. mirrored directories, but know the hash
+// of the content they hold.
+type ContentHasher interface {
+	ContentHash() (string, error)
+}
+
+// TreeWriter is implemented by destinations directories of the repository are mirrored to.
+type TreeWriter interface {
+	WriteTree(hash string, walk func(write storage.EntryWriter) error) error
+}
+
+type Service struct {
+	managedItems    map[string]git_replication.ReplicationItem
+	once            sync.Once
+	defaultSchedule git_replication.Schedule
+
+	// itemLocks make sure scheduled and manually triggered replications of an item never run at the same time
+	itemLocks     map[string]*sync.Mutex
+	itemLocksLock sync.Mutex
+
+	state     map[string]replicationState
+	stateLock sync.RWMutex
+}
+
+// replicationState holds the outcome of the replication attempts of a single item.
+type replicationState struct {
+	status      git_replication.Status
+	lastAttempt time.Time
+	lastSuccess time.Time
+	lastError   string
+	commit      string
+	nextSync    time.Time
+}
+
+func New(items []git_replication.ReplicationItem) (*Service, error) {
+	managedItems := map[string]git_replication.ReplicationItem{}
+	for _, item := range items {
+		if item.ReplicationConf.WorkDir == "" {
+			return nil, fmt.Errorf("empty work dir for item %q", item.ReplicationConf.Id)
+		}
+		if (item.ReplicationConf.Branch == "") == (item.ReplicationConf.Tag == "") {
+			return nil, fmt.Errorf("exactly one of branch and tag must be set for item %q", item.ReplicationConf.Id)
+		}
+		managedItems[item.ReplicationConf.Id] = item
+	}
+
+	return &Service{
+		managedItems: managedItems,
+		defaultSchedule: schedule.Interval{
+			Interval: defaultInterval,
+			Jitter:   defaultJitter,
+		},
+		itemLocks: map[string]*sync.Mutex{},
+		state:     map[string]replicationState{},
+	}, nil
+}
+
+func (s *Service) GetReplicationItem(id string) (git_replication.ReplicationItem, error) {
+	item, found := s.managedItems[id]
+	if !found {
+		return git_replication.ReplicationItem{}, git_replication.ErrGitReplicationItemNotFound
+	}
+
+	s.stateLock.RLock()
+	state := s.state[id]
+	s.stateLock.RUnlock()
+
+	item.Status = state.status
+	item.LastAttempt = state.lastAttempt
+	item.LastSuccess = state.lastSuccess
+	item.LastError = state.lastError
+	item.Commit = state.commit
+	item.NextSync = state.nextSync
+
+	return item, nil
+}
+
+func (s *Service) GetReplicationItems() ([]git_replication.ReplicationItem, error) {
+	ret := make([]git_replication.ReplicationItem, 0, len(s.managedItems))
+	for key := range s.managedItems {
+		item, _ := s.GetReplicationItem(key)
+		ret = append(ret, item)
+	}
+
+	return ret, nil
+}
+
+func (s *Service) StartReplication(ctx context.Context) {
+	s.once.Do(func() {
+		if len(s.managedItems) == 0 {
+			log.Warn().Str(logComponent, gitReplicationComponent).Msg("no items defined, not scheduling replications")
+			return
+		}
+
+		log.Info().Str(logComponent, gitReplicationComponent).Msgf("start replication of %d items", len(s.managedItems))
+		wg := &sync.WaitGroup{}
+		for _, item := range s.managedItems {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.schedule(ctx, item)
+			}()
+		}
+		wg.Wait()
+	})
+}
+
+// schedule replicates the item immediately and afterwards according to its schedule until the context is canceled.
+func (s *Service) schedule(ctx context.Context, item git_replication.ReplicationItem) {
+	schedule := item.ReplicationConf.Schedule
+	if schedule == nil {
+		schedule = s.defaultSchedule
+	}
+
+	for {
+		if err := s.Replicate(ctx, item); err != nil {
+			log.Error().Err(err).Str(logComponent, gitReplicationComponent).Str("id", item.ReplicationConf.Id).Msg("replicating item failed")
+		}
+
+		next := schedule.Next(time.Now())
+		s.setNextSync(item.ReplicationConf.Id, next)
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+func (s *Service) Replicate(ctx context.Context, item git_replication.ReplicationItem) error {
+	lock := s.getItemLock(item.ReplicationConf.Id)
+	lock.Lock()
+	defer lock.Unlock()
+
+	commit, err := s.replicate(ctx, item)
+	s.recordResult(item, commit, err)
+	return err
+}
+
+func (s *Service) getItemLock(id string) *sync.Mutex {
+	s.itemLocksLock.Lock()
+	defer s.itemLocksLock.Unlock()
+
+	lock, found := s.itemLocks[id]
+	if !found {
+		lock = &sync.Mutex{}
+		s.itemLocks[id] = lock
+	}
+	return lock
+}
+
+func (s *Service) replicate(ctx context.Context, item git_replication.ReplicationItem) (string, error) {
+	id := item.ReplicationConf.Id
+	metrics.GitReplicationTimestamp.WithLabelValues(id).SetToCurrentTime()
+
+	var auth transport.AuthMethod
+	if item.Auth != nil {
+		var err error
+		auth, err = item.Auth.AuthMethod()
+		if err != nil {
+			metrics.GitReplicationErrors.WithLabelValues(id, "auth").Inc()
+			return "", fmt.Errorf("could not build auth: %w", err)
+		}
+	}
+
+	repo, err := s.syncRepository(ctx, item, auth)
+	if err != nil {
+		metrics.GitReplicationErrors.WithLabelValues(id, "fetch").Inc()
+		return "", err
+	}
+
+	commit, err := resolveCommit(repo, item.ReplicationConf)
+	if err != nil {
+		metrics.GitReplicationErrors.WithLabelValues(id, "resolve").Inc()
+		return "", err
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return "", err
+	}
+	if err := worktree.Checkout(&git.CheckoutOptions{Hash: commit.Hash, Force: true}); err != nil {
+		metrics.GitReplicationErrors.WithLabelValues(id, "checkout").Inc()
+		return "", fmt.Errorf("could not checkout %s: %w", commit.Hash, err)
+	}
+	metrics.GitReplicationCommitTimestamp.WithLabelValues(id).Set(float64(commit.Committer.When.Unix()))
+
+	if err := s.syncPaths(ctx, item, commit); err != nil {
+		return "", err
+	}
+
+	return commit.Hash.String(), nil
+}
+
+// syncRepository clones the repository into the item's work dir or fetches the latest changes if it already has been
+// cloned. The work dir is cloned again if it holds a repository with a different remote.
+func (s *Service) syncRepository(ctx context.Context, item git_replication.ReplicationItem, auth transport.AuthMethod) (*git.Repository, error) {
+	conf := item.ReplicationConf
+	repo, err := git.PlainOpen(conf.WorkDir)
+	if err == nil && !hasRemote(repo, conf.Repository) {
+		log.Warn().Str(logComponent, gitReplicationComponent).Str("id", conf.Id).Msg("work dir contains a different repository, cloning again")
+		if err := os.RemoveAll(conf.WorkDir); err != nil {
+			return nil, err
+		}
+		err = git.ErrRepositoryNotExists
+	}
+
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		log.Info().Str(logComponent, gitReplicationComponent).Str("id", conf.Id).Msgf("cloning %s", conf.Repository)
+		repo, err = git.PlainCloneContext(ctx, conf.WorkDir, false, &git.CloneOptions{
+			URL:        conf.Repository,
+			RemoteName: remoteName,
+			Auth:       auth,
+			NoCheckout: true,
+			Tags:       git.AllTags,
+		})
+		if err != nil {
+			_ = os.RemoveAll(conf.WorkDir)
+			return nil, fmt.Errorf("could not clone %s: %w", conf.Repository, err)
+		}
+		return repo, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not open %s: %w", conf.WorkDir, err)
+	}
+
+	err = repo.FetchContext(ctx, &git.FetchOptions{
+		RemoteName: remoteName,
+		Auth:       auth,
+		Tags:       git.AllTags,
+		Force:      true,
+		RefSpecs: []gitconfig.RefSpec{
+			gitconfig.RefSpec(fmt.Sprintf("+refs/heads/*:refs/remotes/%s/*", remoteName)),
+			"+refs/tags/*:refs/tags/*",
+		},
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return nil, fmt.Errorf("could not fetch %s: %w", conf.Repository, err)
+	}
+
+	return repo, nil
+}
+
+func hasRemote(repo *git.Repository, url string) bool {
+	remote, err := repo.Remote(remoteName)
+	if err != nil {
+		return false
+	}
+
+	urls := remote.Config().URLs
+	return len(urls) > 0 && urls[0] == url
+}
+
+// resolveCommit returns the commit the configured branch or tag points to. If signature verification is enabled, the
+// commit, or the annotated tag in case it is signed, must carry a valid signature of one of the trusted keys.
+func resolveCommit(repo *git.Repository, conf git_replication.ReplicationConf) (*object.Commit, error) {
+	var ref *plumbing.Reference
+	var err error
+	if conf.Tag != "" {
+		ref, err = repo.Tag(conf.Tag)
+	} else {
+		ref, err = repo.Reference(plumbing.NewRemoteReferenceName(remoteName, conf.Branch), true)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve %q: %w", conf.Ref(), err)
+	}
+
+	// annotated tags point to a tag object instead of the commit
+	var tag *object.Tag
+	var commit *object.Commit
+	if tag, err = repo.TagObject(ref.Hash()); err == nil {
+		commit, err = tag.Commit()
+	} else {
+		commit, err = repo.CommitObject(ref.Hash())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve commit of %q: %w", conf.Ref(), err)
+	}
+
+	if conf.KeyringFile == "" {
+		return commit, nil
+	}
+
+	keyring, err := os.ReadFile(conf.KeyringFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not read keyring: %w", err)
+	}
+
+	if tag != nil && tag.PGPSignature != "" {
+		_, err = tag.Verify(string(keyring))
+	} else {
+		_, err = commit.Verify(string(keyring))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", git_replication.ErrSignatureVerificationFailed, commit.Hash, err)
+	}
+
+	return commit, nil
+}
+
+// syncPaths writes all configured paths of the commit that differ from their destinations. Post hooks are run once
+// if at least one destination has been written. If the post hooks fail, all written destinations are rolled back.
+func (s *Service) syncPaths(ctx context.Context, item git_replication.ReplicationItem, commit *object.Commit) error {
+	id := item.ReplicationConf.Id
+	tree, err := commit.Tree()
+	if err != nil {
+		return err
+	}
+
+	var written []any
+	for _, mapping := range item.ReplicationConf.Paths {
+		updated, err := writePath(tree, mapping)
+		if err != nil {
+			metrics.GitReplicationErrors.WithLabelValues(id, "write_file").Inc()
+			if rollbackErr := storage.Rollback(ctx, gitReplicationComponent, id, err, written...); rollbackErr != nil {
+				metrics.GitReplicationErrors.WithLabelValues(id, "rollback").Inc()
+			}
+			return fmt.Errorf("could not write %q: %w", mapping.Source, err)
+		}
+		if updated {
+			log.Info().Str(logComponent, gitReplicationComponent).Str("id", id).Str("path", mapping.Source).Msg("wrote path to disk")
+			written = append(written, mapping.Destination)
+		}
+	}
+
+	if len(written) == 0 {
+		return nil
+	}
+
+	metrics.GitReplicationUpdates.WithLabelValues(id).Inc()
+	if err := pkg.RunPostIssueHooks(item.PostHooks); err != nil {
+		metrics.GitReplicationErrors.WithLabelValues(id, "post_hooks").Inc()
+		if rollbackErr := storage.Rollback(ctx, gitReplicationComponent, id, err, written...); rollbackErr != nil {
+			metrics.GitReplicationErrors.WithLabelValues(id, "rollback").Inc()
+		}
+		return err
+	}
+
+	return nil
+}
+
+// writePath writes the file or directory of the tree to its destination unless the destination already holds the
+// content. It returns whether the destination has been written.
+func writePath(tree *object.Tree, mapping git_replication.PathMapping) (bool, error) {
+	if mapping.IsDir {
+		return writeDir(tree, mapping)
+	}
+
+	data, err := readFile(tree, mapping.Source)
+	if err != nil {
+		return false, err
+	}
+
+	if destinationHasContent(mapping.Destination, storage.ContentHash(data)) {
+		return false, nil
+	}
+
+	return true, mapping.Destination.Write(data)
+}
+
+func readFile(tree *object.Tree, name string) ([]byte, error) {
+	file, err := tree.File(name)
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err := file.Contents()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(contents), nil
+}
+
+// writeDir writes the files of the directory directly to the destination. The hash of the directory's git tree
+// identifies its content, so unchanged directories are detected without reading their files.
+func writeDir(tree *object.Tree, mapping git_replication.PathMapping) (bool, error) {
+	dest, ok := mapping.Destination.(TreeWriter)
+	if !ok {
+		return false, fmt.Errorf("destination of directory %q can not hold a directory", mapping.Source)
+	}
+
+	subtree, err := tree.Tree(mapping.Source)
+	if err != nil {
+		return false, err
+	}
+
+	hash := storage.ContentHash([]byte(subtree.Hash.String()))
+	if destinationHasContent(mapping.Destination, hash) {
+		return false, nil
+	}
+
+	return true, dest.WriteTree(hash, func(write storage.EntryWriter) error {
+		return writeFiles(subtree, write)
+	})
+}
+
+func writeFiles(tree *object.Tree, write storage.EntryWriter) error {
+	return tree.Files().ForEach(func(file *object.File) error {
+		switch file.Mode {
+		case filemode.Symlink:
+			target, err := file.Contents()
+			if err != nil {
+				return err
+			}
+			return write(file.Name, fs.ModeSymlink, target, nil)
+		case filemode.Regular, filemode.Deprecated, filemode.Executable:
+			perm := fs.FileMode(0644)
+			if file.Mode == filemode.Executable {
+				perm = 0755
+			}
+
+			reader, err := file.Reader()
+			if err != nil {
+				return err
+			}
+			defer func() {
+				_ = reader.Close()
+			}()
+			return write(file.Name, perm, "", reader)
+		default:
+			// submodules are not part of the tree's files
+			return nil
+		}
+	})
+}
+
+// destinationHasContent returns true if the destination holds the content with the given hash.
+func destinationHasContent(dest git_replication.StorageImplementation, hash string) bool {
+	if hasher, ok := dest.(ContentHasher); ok {
+		destHash, err := hasher.ContentHash()
+		return err == nil && destHash == hash
+	}
+
+	data, err := dest.Read()
+	return err == nil && storage.ContentMatches(data, hash)
+}
+
+func (s *Service) recordResult(item git_replication.ReplicationItem, commit string, err error) {
+	now := time.Now()
+	s.stateLock.Lock()
+	defer s.stateLock.Unlock()
+
+	state := s.state[item.ReplicationConf.Id]
+	state.lastAttempt = now
+	switch {
+	case err == nil:
+		state.status = git_replication.Synced
+		state.lastSuccess = now
+		state.lastError = ""
+		state.commit = commit
+	case errors.Is(err, git_replication.ErrSignatureVerificationFailed):
+		state.status = git_replication.VerificationFailed
+		state.lastError = err.Error()
+	default:
+		state.status = git_replication.FailedStatus
+		state.lastError = err.Error()
+	}
+	s.state[item.ReplicationConf.Id] = state
+}
+
+func (s *Service) setNextSync(id string, next time.Time) {
+	s.stateLock.Lock()
+	defer s.stateLock.Unlock()
+
+	state := s.state[id]
+	state.nextSync = next
+	s.state[id] = state
+}
@@ -0,0 +1,353 @@
+package git_replication
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport/client"
+	"github.com/go-git/go-git/v5/plumbing/transport/server"
+	"github.com/soerenschneider/sc-agent/internal/domain"
+	"github.com/soerenschneider/sc-agent/internal/domain/git_replication"
+	"github.com/soerenschneider/sc-agent/internal/storage"
+)
+
+func init() {
+	// serve local repositories in-process instead of relying on the git binaries
+	client.InstallProtocol("file", server.DefaultServer)
+}
+
+type remoteRepo struct {
+	t    *testing.T
+	dir  string
+	repo *git.Repository
+}
+
+func newRemoteRepo(t *testing.T) *remoteRepo {
+	t.Helper()
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &remoteRepo{t: t, dir: dir, repo: repo}
+}
+
+// url returns the path of the git dir, as the in-process server expects a bare repository.
+func (r *remoteRepo) url() string {
+	return filepath.Join(r.dir, ".git")
+}
+
+func (r *remoteRepo) commit(files map[string]string, signKey *openpgp.Entity) string {
+	r.t.Helper()
+	worktree, err := r.repo.Worktree()
+	if err != nil {
+		r.t.Fatal(err)
+	}
+
+	for name, content := range files {
+		path := filepath.Join(r.dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			r.t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			r.t.Fatal(err)
+		}
+		if _, err := worktree.Add(name); err != nil {
+			r.t.Fatal(err)
+		}
+	}
+
+	hash, err := worktree.Commit("update", &git.CommitOptions{
+		Author:  &object.Signature{Name: "sc-agent", Email: "sc-agent@example.com", When: time.Now()},
+		SignKey: signKey,
+	})
+	if err != nil {
+		r.t.Fatal(err)
+	}
+	return hash.String()
+}
+
+func newItem(t *testing.T, remote *remoteRepo, dest, destDir string) git_replication.ReplicationItem {
+	t.Helper()
+	fileStorage, err := storage.NewFilesystemStorageFromUri(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dirStorage, err := storage.NewArchiveStorage(destDir, storage.ArchiveFormatTarGz)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return git_replication.ReplicationItem{
+		ReplicationConf: git_replication.ReplicationConf{
+			Id:         "config",
+			Repository: remote.url(),
+			Branch:     "master",
+			WorkDir:    filepath.Join(t.TempDir(), "work"),
+			Paths: []git_replication.PathMapping{
+				{Source: "nginx/nginx.conf", Destination: fileStorage},
+				{Source: "site", IsDir: true, Destination: dirStorage},
+			},
+		},
+	}
+}
+
+func readDest(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("could not read %s: %v", path, err)
+	}
+	// the filesystem storage terminates files with a newline
+	return strings.TrimSpace(string(data))
+}
+
+func TestService_Replicate(t *testing.T) {
+	remote := newRemoteRepo(t)
+	hash := remote.commit(map[string]string{
+		"nginx/nginx.conf":  "v1",
+		"site/index.html":   "index v1",
+		"site/css/app.css":  "css",
+		"unrelated/file.md": "readme",
+	}, nil)
+
+	destDir := t.TempDir()
+	dest := filepath.Join(destDir, "nginx.conf")
+	site := filepath.Join(destDir, "site")
+	item := newItem(t, remote, dest, site)
+
+	service, err := New([]git_replication.ReplicationItem{item})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := service.Replicate(context.Background(), item); err != nil {
+		t.Fatalf("Replicate() unexpected error: %v", err)
+	}
+
+	if got := readDest(t, dest); got != "v1" {
+		t.Errorf("nginx.conf = %q, want %q", got, "v1")
+	}
+	if got := readDest(t, filepath.Join(site, "css/app.css")); got != "css" {
+		t.Errorf("app.css = %q, want %q", got, "css")
+	}
+	got, _ := service.GetReplicationItem("config")
+	if got.Status != git_replication.Synced || got.Commit != hash {
+		t.Errorf("expected synced status and commit %s, got %v, %s", hash, got.Status, got.Commit)
+	}
+
+	// only changed paths are written
+	siteTarget, _ := os.Readlink(site)
+	hash = remote.commit(map[string]string{"nginx/nginx.conf": "v2"}, nil)
+	if err := service.Replicate(context.Background(), item); err != nil {
+		t.Fatalf("Replicate() unexpected error: %v", err)
+	}
+	if got := readDest(t, dest); got != "v2" {
+		t.Errorf("nginx.conf = %q, want %q", got, "v2")
+	}
+	if target, _ := os.Readlink(site); target != siteTarget {
+		t.Errorf("expected unchanged directory not to be replaced, got %q, want %q", target, siteTarget)
+	}
+	got, _ = service.GetReplicationItem("config")
+	if got.Commit != hash {
+		t.Errorf("expected commit %s, got %s", hash, got.Commit)
+	}
+}
+
+func TestService_ReplicateRollback(t *testing.T) {
+	remote := newRemoteRepo(t)
+	remote.commit(map[string]string{"nginx/nginx.conf": "v1", "site/index.html": "index v1"}, nil)
+
+	destDir := t.TempDir()
+	dest := filepath.Join(destDir, "nginx.conf")
+	site := filepath.Join(destDir, "site")
+	item := newItem(t, remote, dest, site)
+
+	service, err := New([]git_replication.ReplicationItem{item})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := service.Replicate(context.Background(), item); err != nil {
+		t.Fatalf("Replicate() unexpected error: %v", err)
+	}
+
+	remote.commit(map[string]string{"nginx/nginx.conf": "v2", "site/index.html": "index v2"}, nil)
+	item.PostHooks = []domain.PostHook{{Name: "validate", Cmd: "false"}}
+	if err := service.Replicate(context.Background(), item); err == nil {
+		t.Fatal("Replicate() expected error of failing post hook")
+	}
+
+	if got := readDest(t, dest); got != "v1" {
+		t.Errorf("nginx.conf = %q, want rolled back content %q", got, "v1")
+	}
+	if got := readDest(t, filepath.Join(site, "index.html")); got != "index v1" {
+		t.Errorf("index.html = %q, want rolled back content %q", got, "index v1")
+	}
+	got, _ := service.GetReplicationItem("config")
+	if got.Status != git_replication.FailedStatus || got.LastError == "" {
+		t.Errorf("expected failed status with error, got %v, %q", got.Status, got.LastError)
+	}
+}
+
+func TestService_ReplicateTag(t *testing.T) {
+	remote := newRemoteRepo(t)
+	hash := remote.commit(map[string]string{"nginx/nginx.conf": "v1", "site/index.html": "index"}, nil)
+	head, err := remote.repo.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := remote.repo.CreateTag("v1.0.0", head.Hash(), &git.CreateTagOptions{
+		Tagger:  &object.Signature{Name: "sc-agent", Email: "sc-agent@example.com", When: time.Now()},
+		Message: "release",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	remote.commit(map[string]string{"nginx/nginx.conf": "v2"}, nil)
+
+	destDir := t.TempDir()
+	item := newItem(t, remote, filepath.Join(destDir, "nginx.conf"), filepath.Join(destDir, "site"))
+	item.ReplicationConf.Branch = ""
+	item.ReplicationConf.Tag = "v1.0.0"
+
+	service, err := New([]git_replication.ReplicationItem{item})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := service.Replicate(context.Background(), item); err != nil {
+		t.Fatalf("Replicate() unexpected error: %v", err)
+	}
+
+	if got := readDest(t, filepath.Join(destDir, "nginx.conf")); got != "v1" {
+		t.Errorf("nginx.conf = %q, want %q", got, "v1")
+	}
+	got, _ := service.GetReplicationItem("config")
+	if got.Commit != hash {
+		t.Errorf("expected commit %s of tag, got %s", hash, got.Commit)
+	}
+}
+
+func TestService_ReplicateVerifySignatures(t *testing.T) {
+	entity, err := openpgp.NewEntity("sc-agent", "", "sc-agent@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var publicKey bytes.Buffer
+	w, err := armor.Encode(&publicKey, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := entity.Serialize(w); err != nil {
+		t.Fatal(err)
+	}
+	_ = w.Close()
+
+	keyring := filepath.Join(t.TempDir(), "keyring.asc")
+	if err := os.WriteFile(keyring, publicKey.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	remote := newRemoteRepo(t)
+	remote.commit(map[string]string{"nginx/nginx.conf": "v1", "site/index.html": "index"}, entity)
+
+	destDir := t.TempDir()
+	dest := filepath.Join(destDir, "nginx.conf")
+	item := newItem(t, remote, dest, filepath.Join(destDir, "site"))
+	item.ReplicationConf.KeyringFile = keyring
+
+	service, err := New([]git_replication.ReplicationItem{item})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := service.Replicate(context.Background(), item); err != nil {
+		t.Fatalf("Replicate() unexpected error: %v", err)
+	}
+
+	// unsigned commits must not be replicated
+	remote.commit(map[string]string{"nginx/nginx.conf": "v2"}, nil)
+	if err := service.Replicate(context.Background(), item); !errors.Is(err, git_replication.ErrSignatureVerificationFailed) {
+		t.Fatalf("Replicate() expected ErrSignatureVerificationFailed, got %v", err)
+	}
+
+	if got := readDest(t, dest); got != "v1" {
+		t.Errorf("nginx.conf = %q, want %q", got, "v1")
+	}
+	got, _ := service.GetReplicationItem("config")
+	if got.Status != git_replication.VerificationFailed {
+		t.Errorf("expected status VerificationFailed, got %v", got.Status)
+	}
+}
+
+func TestService_ReplicateRemoteChanged(t *testing.T) {
+	first := newRemoteRepo(t)
+	first.commit(map[string]string{"nginx/nginx.conf": "first", "site/index.html": "index"}, nil)
+	second := newRemoteRepo(t)
+	second.commit(map[string]string{"nginx/nginx.conf": "second", "site/index.html": "index"}, nil)
+
+	destDir := t.TempDir()
+	dest := filepath.Join(destDir, "nginx.conf")
+	item := newItem(t, first, dest, filepath.Join(destDir, "site"))
+
+	service, err := New([]git_replication.ReplicationItem{item})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := service.Replicate(context.Background(), item); err != nil {
+		t.Fatalf("Replicate() unexpected error: %v", err)
+	}
+
+	// the work dir is cloned again if the repository of the item changes
+	item.ReplicationConf.Repository = second.url()
+	if err := service.Replicate(context.Background(), item); err != nil {
+		t.Fatalf("Replicate() unexpected error: %v", err)
+	}
+	if got := readDest(t, dest); got != "second" {
+		t.Errorf("nginx.conf = %q, want %q", got, "second")
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		conf    git_replication.ReplicationConf
+		wantErr bool
+	}{
+		{
+			name: "branch",
+			conf: git_replication.ReplicationConf{Id: "a", WorkDir: "/tmp/a", Branch: "main"},
+		},
+		{
+			name:    "branch and tag",
+			conf:    git_replication.ReplicationConf{Id: "a", WorkDir: "/tmp/a", Branch: "main", Tag: "v1"},
+			wantErr: true,
+		},
+		{
+			name:    "neither branch nor tag",
+			conf:    git_replication.ReplicationConf{Id: "a", WorkDir: "/tmp/a"},
+			wantErr: true,
+		},
+		{
+			name:    "missing work dir",
+			conf:    git_replication.ReplicationConf{Id: "a", Branch: "main"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New([]git_replication.ReplicationItem{{ReplicationConf: tt.conf}})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
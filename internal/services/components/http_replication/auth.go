@@ -0,0 +1,190 @@
+package http_replication
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	vault "github.com/hashicorp/vault/api"
+)
+
+// TokenSource returns the bearer token that is used to authenticate requests.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// BearerTokenAuth authenticates requests using the "Authorization: Bearer" header.
+type BearerTokenAuth struct {
+	tokenSource TokenSource
+}
+
+func NewBearerTokenAuth(tokenSource TokenSource) (*BearerTokenAuth, error) {
+	if tokenSource == nil {
+		return nil, errors.New("empty token source passed")
+	}
+
+	return &BearerTokenAuth{tokenSource: tokenSource}, nil
+}
+
+func (a *BearerTokenAuth) Authenticate(ctx context.Context, req *http.Request) error {
+	token, err := a.tokenSource.Token(ctx)
+	if err != nil {
+		return fmt.Errorf("could not get bearer token: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// BasicAuth authenticates requests using HTTP basic auth. The password is read from a file for every request, so
+// rotated passwords are picked up automatically.
+type BasicAuth struct {
+	username     string
+	passwordFile string
+}
+
+func NewBasicAuth(username, passwordFile string) (*BasicAuth, error) {
+	if username == "" {
+		return nil, errors.New("empty username passed")
+	}
+
+	if passwordFile == "" {
+		return nil, errors.New("empty password file passed")
+	}
+
+	return &BasicAuth{
+		username:     username,
+		passwordFile: passwordFile,
+	}, nil
+}
+
+func (a *BasicAuth) Authenticate(_ context.Context, req *http.Request) error {
+	password, err := readTrimmedFile(a.passwordFile)
+	if err != nil {
+		return fmt.Errorf("could not read password: %w", err)
+	}
+
+	req.SetBasicAuth(a.username, password)
+	return nil
+}
+
+// FileTokenSource reads the token from a file.
+type FileTokenSource struct {
+	file string
+}
+
+func NewFileTokenSource(file string) (*FileTokenSource, error) {
+	if file == "" {
+		return nil, errors.New("empty file passed")
+	}
+
+	return &FileTokenSource{file: file}, nil
+}
+
+func (s *FileTokenSource) Token(_ context.Context) (string, error) {
+	return readTrimmedFile(s.file)
+}
+
+type Kv2Reader interface {
+	Get(ctx context.Context, secretPath string) (*vault.KVSecret, error)
+}
+
+// VaultTokenSource reads the token from a key of a kv2 secret.
+type VaultTokenSource struct {
+	client     Kv2Reader
+	secretPath string
+	key        string
+}
+
+func NewVaultTokenSource(client Kv2Reader, secretPath, key string) (*VaultTokenSource, error) {
+	if client == nil {
+		return nil, errors.New("empty client passed")
+	}
+
+	if secretPath == "" || key == "" {
+		return nil, errors.New("secret path and key must not be empty")
+	}
+
+	return &VaultTokenSource{
+		client:     client,
+		secretPath: secretPath,
+		key:        key,
+	}, nil
+}
+
+func (s *VaultTokenSource) Token(ctx context.Context) (string, error) {
+	secret, err := s.client.Get(ctx, s.secretPath)
+	if err != nil {
+		return "", err
+	}
+
+	val, found := secret.Data[s.key]
+	if !found {
+		return "", fmt.Errorf("key %q not found in secret %q", s.key, s.secretPath)
+	}
+
+	token, ok := val.(string)
+	if !ok || token == "" {
+		return "", fmt.Errorf("key %q of secret %q is not a non-empty string", s.key, s.secretPath)
+	}
+
+	return token, nil
+}
+
+// NewMtlsClient returns a client that authenticates using the given client certificate. The certificate is read from
+// disk for every handshake, so certificates that are renewed by the pki component are picked up without a restart.
+func NewMtlsClient(certFile, keyFile, caFile string) (*http.Client, error) {
+	// check early if the key pair is readable to detect misconfigurations during startup
+	if _, err := tls.LoadX509KeyPair(certFile, keyFile); err != nil {
+		return nil, fmt.Errorf("could not load client certificate: %w", err)
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetClientCertificate: func(_ *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+			if err != nil {
+				return nil, err
+			}
+			return &cert, nil
+		},
+	}
+
+	if caFile != "" {
+		caData, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not read ca file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caData) {
+			return nil, fmt.Errorf("no certificates found in %q", caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   ClientTimeout,
+	}, nil
+}
+
+func readTrimmedFile(file string) (string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+
+	content := strings.TrimSpace(string(data))
+	if content == "" {
+		return "", fmt.Errorf("file %q is empty", file)
+	}
+	return content, nil
+}
@@ -3,20 +3,20 @@ package http_replication
 import (
 	"bytes"
 	"context"
-	"crypto/sha256"
-	"encoding/hex"
 	"errors"
 	"fmt"
 	"hash/fnv"
 	"io"
-	"math/rand/v2"
 	"net/http"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/soerenschneider/sc-agent/internal/domain/http_replication"
 	"github.com/soerenschneider/sc-agent/internal/metrics"
+	"github.com/soerenschneider/sc-agent/internal/schedule"
+	"github.com/soerenschneider/sc-agent/internal/storage"
 	"github.com/soerenschneider/sc-agent/pkg"
 )
 
@@ -24,8 +24,13 @@ const (
 	logComponent             = "component"
 	httpReplicationComponent = "http-replication"
 	defaultInterval          = 10 * time.Minute
+	defaultJitter            = 5 * time.Minute
 )
 
+// ClientTimeout limits the duration of a single request, including reading the response body, of the clients that
+// are used for the replication.
+const ClientTimeout = 5 * time.Minute
+
 type Client interface {
 	Do(req *http.Request) (*http.Response, error)
 }
@@ -36,12 +41,41 @@ type StorageImplementation interface {
 	Write([]byte) error
 }
 
+// ContentHasher is implemented by destinations that can not be read back, e.g. extracted archives, but know the hash
+// of the content they hold.
+type ContentHasher interface {
+	ContentHash() (string, error)
+}
+
 type Service struct {
-	client       Client
-	managedItems map[string]http_replication.ReplicationItem
-	cache        map[string]string
-	once         sync.Once
-	interval     time.Duration
+	client          Client
+	managedItems    map[string]http_replication.ReplicationItem
+	cache           map[string]string
+	cacheLock       sync.Mutex
+	once            sync.Once
+	defaultSchedule http_replication.Schedule
+
+	// destinationLocks make sure scheduled and manually triggered replications never write the same destination at
+	// the same time
+	destinationLocks     map[string]*sync.Mutex
+	destinationLocksLock sync.Mutex
+
+	state     map[string]replicationState
+	stateLock sync.RWMutex
+}
+
+// replicationState holds the outcome of the replication attempts of a single item.
+type replicationState struct {
+	status      http_replication.Status
+	lastAttempt time.Time
+	lastSuccess time.Time
+	lastError   string
+	contentHash string
+	version     string
+	// etag and lastModified are the validators that are sent with conditional requests
+	etag         string
+	lastModified string
+	nextSync     time.Time
 }
 
 func New(client Client, items []http_replication.ReplicationItem) (*Service, error) {
@@ -58,7 +92,12 @@ func New(client Client, items []http_replication.ReplicationItem) (*Service, err
 		client:       client,
 		managedItems: managedItems,
 		cache:        map[string]string{},
-		interval:     defaultInterval,
+		defaultSchedule: schedule.Interval{
+			Interval: defaultInterval,
+			Jitter:   defaultJitter,
+		},
+		destinationLocks: map[string]*sync.Mutex{},
+		state:            map[string]replicationState{},
 	}
 
 	return ret, nil
@@ -70,11 +109,17 @@ func (s *Service) GetReplicationItem(id string) (http_replication.ReplicationIte
 		return http_replication.ReplicationItem{}, http_replication.ErrHttpReplicationItemNotFound
 	}
 
-	item.Status = http_replication.Unknown
-	_, found = s.cache[id]
-	if found {
-		item.Status = http_replication.Synced
-	}
+	s.stateLock.RLock()
+	state := s.state[id]
+	s.stateLock.RUnlock()
+
+	item.Status = state.status
+	item.LastAttempt = state.lastAttempt
+	item.LastSuccess = state.lastSuccess
+	item.LastError = state.lastError
+	item.ContentHash = state.contentHash
+	item.Version = state.version
+	item.NextSync = state.nextSync
 
 	return item, nil
 }
@@ -94,54 +139,121 @@ func (s *Service) GetReplicationItems() ([]http_replication.ReplicationItem, err
 func (s *Service) StartReplication(ctx context.Context) {
 	s.once.Do(func() {
 		if len(s.managedItems) == 0 {
-			log.Warn().Str(logComponent, httpReplicationComponent).Msg("no certificates defined, not scheduling auto-renewals")
+			log.Warn().Str(logComponent, httpReplicationComponent).Msg("no items defined, not scheduling replications")
 			return
 		}
 
 		log.Info().Str(logComponent, httpReplicationComponent).Msgf("start replication of %d items", len(s.managedItems))
-		jitter := 5 * time.Minute
-		checkInterval := s.interval - (jitter / 2)
-		ticker := time.NewTicker(checkInterval)
-		s.autoRenew(ctx)
-
-		for {
-			select {
-			case <-ctx.Done():
-				ticker.Stop()
-				return
-			case <-ticker.C:
-				time.Sleep(rand.N(jitter)) // #nosec G404
-				s.autoRenew(ctx)
-			}
+		wg := &sync.WaitGroup{}
+		for _, item := range s.managedItems {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.schedule(ctx, item)
+			}()
 		}
+		wg.Wait()
 	})
 }
 
-func (s *Service) autoRenew(ctx context.Context) {
-	for _, req := range s.managedItems {
+// schedule replicates the item immediately and afterwards according to its schedule until the context is canceled.
+func (s *Service) schedule(ctx context.Context, item http_replication.ReplicationItem) {
+	schedule := item.ReplicationConf.Schedule
+	if schedule == nil {
+		schedule = s.defaultSchedule
+	}
+
+	for {
+		if err := s.Replicate(ctx, item); err != nil {
+			log.Error().Err(err).Str(logComponent, httpReplicationComponent).Str("id", item.ReplicationConf.Id).Msg("replicating item failed")
+		}
+
+		next := schedule.Next(time.Now())
+		s.setNextSync(item.ReplicationConf.Id, next)
+		timer := time.NewTimer(time.Until(next))
 		select {
 		case <-ctx.Done():
+			timer.Stop()
 			return
-		default:
-			if err := s.Replicate(ctx, req); err != nil {
-				log.Error().Err(err).Str(logComponent, httpReplicationComponent).Str("id", req.ReplicationConf.Id).Msg("replicating item failed")
-			}
+		case <-timer.C:
 		}
 	}
 }
 
 func (s *Service) Replicate(ctx context.Context, conf http_replication.ReplicationItem) error {
+	unlock := s.lockDestinations(conf)
+	defer unlock()
+
+	result, err := s.replicate(ctx, conf)
+	s.recordResult(conf, result, err)
+	return err
+}
+
+// lockDestinations acquires the locks for all destinations of the item and returns a function to release them. Locks
+// are acquired in a stable order to prevent deadlocks between items sharing destinations.
+func (s *Service) lockDestinations(conf http_replication.ReplicationItem) func() {
+	keys := slices.Clone(conf.ReplicationConf.Destinations)
+	if len(keys) == 0 {
+		keys = []string{conf.ReplicationConf.Id}
+	}
+	slices.Sort(keys)
+	keys = slices.Compact(keys)
+
+	locks := make([]*sync.Mutex, 0, len(keys))
+	s.destinationLocksLock.Lock()
+	for _, key := range keys {
+		lock, found := s.destinationLocks[key]
+		if !found {
+			lock = &sync.Mutex{}
+			s.destinationLocks[key] = lock
+		}
+		locks = append(locks, lock)
+	}
+	s.destinationLocksLock.Unlock()
+
+	for _, lock := range locks {
+		lock.Lock()
+	}
+
+	return func() {
+		for idx := len(locks) - 1; idx >= 0; idx-- {
+			locks[idx].Unlock()
+		}
+	}
+}
+
+// replicationResult describes the content that has been replicated.
+type replicationResult struct {
+	contentHash  string
+	version      string
+	etag         string
+	lastModified string
+}
+
+func (s *Service) replicate(ctx context.Context, conf http_replication.ReplicationItem) (replicationResult, error) {
 	metrics.HttpReplicationTimestamp.WithLabelValues(conf.ReplicationConf.Id).SetToCurrentTime()
 	metrics.HttpReplicationRequests.WithLabelValues(conf.ReplicationConf.Id).Inc()
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, conf.ReplicationConf.Source, nil)
-	if err != nil {
-		return err
-	}
 
-	resp, err := s.client.Do(req)
+	resp, err := s.doRequest(ctx, conf, conf.ReplicationConf.Source, true)
 	if err != nil {
 		metrics.HttpReplicationErrors.WithLabelValues(conf.ReplicationConf.Id, "request_errors").Inc()
-		return err
+		return replicationResult{}, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified {
+		_ = resp.Body.Close()
+		metrics.HttpReplicationNotModified.WithLabelValues(conf.ReplicationConf.Id).Inc()
+		if result, ok := s.getUnmodifiedResult(conf); ok {
+			return result, nil
+		}
+
+		// the server's content has not changed but our local copy has, we need to download the content again
+		log.Info().Str(logComponent, httpReplicationComponent).Str("id", conf.ReplicationConf.Id).Msg("noticed file has changed on disk, downloading content again")
+		resp, err = s.doRequest(ctx, conf, conf.ReplicationConf.Source, false)
+		if err != nil {
+			metrics.HttpReplicationErrors.WithLabelValues(conf.ReplicationConf.Id, "request_errors").Inc()
+			return replicationResult{}, err
+		}
 	}
 
 	defer func() {
@@ -150,65 +262,169 @@ func (s *Service) Replicate(ctx context.Context, conf http_replication.Replicati
 
 	if resp.StatusCode/100 != 2 {
 		metrics.HttpReplicationErrors.WithLabelValues(conf.ReplicationConf.Id, "request_errors").Inc()
-		return fmt.Errorf("wrong status code, expected 2xx got %d", resp.StatusCode)
+		return replicationResult{}, fmt.Errorf("wrong status code, expected 2xx got %d", resp.StatusCode)
 	}
 
 	data, err := io.ReadAll(resp.Body)
 	if err != nil {
 		metrics.HttpReplicationErrors.WithLabelValues(conf.ReplicationConf.Id, "data_errors").Inc()
-		return err
-	}
-
-	if conf.ReplicationConf.TrimWhitespaces {
-		data = bytes.TrimSpace(data)
+		return replicationResult{}, err
 	}
 
 	if len(bytes.TrimSpace(data)) == 0 {
 		metrics.HttpReplicationErrors.WithLabelValues(conf.ReplicationConf.Id, "data_errors").Inc()
-		return errors.New("empty payload")
+		return replicationResult{}, errors.New("empty payload")
+	}
+
+	result := replicationResult{
+		contentHash:  hashContent(content(data, conf)),
+		version:      getVersion(resp),
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+	}
+	return result, s.updateFile(ctx, data, conf)
+}
+
+// doRequest sends a GET request for the given url using the item's client and authentication. If conditional is true
+// and the content has been replicated before, the request is sent using the "If-None-Match" and "If-Modified-Since"
+// headers so the server can answer with 304 Not Modified instead of transferring the content again.
+func (s *Service) doRequest(ctx context.Context, conf http_replication.ReplicationItem, url string, conditional bool) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if conditional {
+		s.setConditionalHeaders(conf, req)
+	}
+
+	if conf.Authenticator != nil {
+		if err := conf.Authenticator.Authenticate(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+
+	var client Client = s.client
+	if conf.Client != nil {
+		client = conf.Client
+	}
+
+	return client.Do(req)
+}
+
+func (s *Service) setConditionalHeaders(conf http_replication.ReplicationItem, req *http.Request) {
+	if _, cached := s.getCachedHash(conf.ReplicationConf.Id); !cached {
+		return
+	}
+
+	s.stateLock.RLock()
+	state := s.state[conf.ReplicationConf.Id]
+	s.stateLock.RUnlock()
+
+	if state.etag != "" {
+		req.Header.Set("If-None-Match", state.etag)
+	}
+	if state.lastModified != "" {
+		req.Header.Set("If-Modified-Since", state.lastModified)
+	}
+}
+
+// getUnmodifiedResult returns the result of the previous replication if the local copy is still identical to the
+// content that has been replicated.
+func (s *Service) getUnmodifiedResult(conf http_replication.ReplicationItem) (replicationResult, bool) {
+	hash, cached := s.getCachedHash(conf.ReplicationConf.Id)
+	if !cached {
+		return replicationResult{}, false
+	}
+
+	if !destinationHasContent(conf, hash) {
+		return replicationResult{}, false
 	}
 
-	return s.updateFile(data, conf)
+	s.stateLock.RLock()
+	state := s.state[conf.ReplicationConf.Id]
+	s.stateLock.RUnlock()
+
+	return replicationResult{
+		contentHash:  hash,
+		version:      state.version,
+		etag:         state.etag,
+		lastModified: state.lastModified,
+	}, true
+}
+
+// fetchSignature downloads the detached signature of the item's content.
+func (s *Service) fetchSignature(ctx context.Context, conf http_replication.ReplicationItem) ([]byte, error) {
+	url := conf.ReplicationConf.FileValidation.GetSignatureUrl(conf.ReplicationConf.Source)
+	resp, err := s.doRequest(ctx, conf, url, false)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("wrong status code for signature %q, expected 2xx got %d", url, resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
 }
 
-func (s *Service) updateFile(data []byte, conf http_replication.ReplicationItem) error {
+// content returns the content that is written for the given response body.
+func content(body []byte, conf http_replication.ReplicationItem) []byte {
+	if conf.ReplicationConf.TrimWhitespaces {
+		return bytes.TrimSpace(body)
+	}
+	return body
+}
+
+// updateFile validates the response body and writes its content to the destinations. Validation is performed on the
+// body as it has been received, as signatures and checksums are created over the original bytes.
+func (s *Service) updateFile(ctx context.Context, body []byte, conf http_replication.ReplicationItem) error {
+	data := content(body, conf)
 	hash := hashContent(data)
 
-	oldHash, itemAlreadyCached := s.cache[conf.ReplicationConf.Id]
+	oldHash, itemAlreadyCached := s.getCachedHash(conf.ReplicationConf.Id)
 	if itemAlreadyCached && oldHash == hash {
 		// item is already downloaded. let's check if the item on disk has been changed by a 3rd party since our last check.
-		diskContent, err := conf.Destination.Read()
-		if err == nil {
-			diskHash := hashContent(diskContent)
-			if diskHash == hash {
-				// file exists locally and is identical to the item we downloaded, we're done
-				return nil
-			}
-			log.Info().Str(logComponent, httpReplicationComponent).Str("id", conf.ReplicationConf.Id).Msg("noticed file has changed on disk, proceeding to overwrite")
+		if destinationHasContent(conf, hash) {
+			// file exists locally and is identical to the item we downloaded, we're done
+			return nil
 		}
+		log.Info().Str(logComponent, httpReplicationComponent).Str("id", conf.ReplicationConf.Id).Msg("noticed file has changed on disk, proceeding to overwrite")
 	}
 
 	var validationSuccess = true
 	if conf.ReplicationConf.FileValidation != nil {
 		var err error
-		validationSuccess, err = conf.ReplicationConf.FileValidation.Accept(data)
+		if conf.ReplicationConf.FileValidation.RequiresSignature() {
+			var signature []byte
+			signature, err = s.fetchSignature(ctx, conf)
+			if err != nil {
+				metrics.HttpReplicationErrors.WithLabelValues(conf.ReplicationConf.Id, "request_errors").Inc()
+				return fmt.Errorf("could not fetch signature: %w", err)
+			}
+			validationSuccess, err = conf.ReplicationConf.FileValidation.VerifySignature(body, signature)
+		} else {
+			validationSuccess, err = conf.ReplicationConf.FileValidation.Accept(body)
+		}
 		if err != nil {
 			return fmt.Errorf("could not validate replicated file: %w", err)
 		}
 	}
 
 	if !validationSuccess {
-		conf.Status = http_replication.ValidationFailed
 		metrics.HttpReplicationErrors.WithLabelValues(conf.ReplicationConf.Id, "file_validation_failed").Inc()
 		return http_replication.ErrFileValidationFailed
 	}
 
-	s.cache[conf.ReplicationConf.Id] = hash
+	s.setCachedHash(conf.ReplicationConf.Id, hash)
 	updateMetricsHash(conf.ReplicationConf.Id, data)
 
 	if !itemAlreadyCached {
-		read, err := conf.Destination.Read()
-		if err == nil && hash == hashContent(read) {
+		if destinationHasContent(conf, hash) {
 			log.Debug().Str(logComponent, httpReplicationComponent).Str("id", conf.ReplicationConf.Id).Msg("file already exists locally")
 			return nil
 		}
@@ -222,18 +438,87 @@ func (s *Service) updateFile(data []byte, conf http_replication.ReplicationItem)
 
 	if err := pkg.RunPostIssueHooks(conf.PostHooks); err != nil {
 		metrics.HttpReplicationErrors.WithLabelValues(conf.ReplicationConf.Id, "post_hooks").Inc()
+		if rollbackErr := storage.Rollback(ctx, httpReplicationComponent, conf.ReplicationConf.Id, err, conf.Destination); rollbackErr != nil {
+			metrics.HttpReplicationErrors.WithLabelValues(conf.ReplicationConf.Id, "rollback").Inc()
+		}
 		return err
 	}
 
 	return nil
 }
 
+func (s *Service) recordResult(conf http_replication.ReplicationItem, result replicationResult, err error) {
+	now := time.Now()
+	s.stateLock.Lock()
+	defer s.stateLock.Unlock()
+
+	state := s.state[conf.ReplicationConf.Id]
+	state.lastAttempt = now
+	switch {
+	case err == nil:
+		state.status = http_replication.Synced
+		state.lastSuccess = now
+		state.lastError = ""
+		state.contentHash = result.contentHash
+		state.version = result.version
+		state.etag = result.etag
+		state.lastModified = result.lastModified
+	case errors.Is(err, http_replication.ErrFileValidationFailed):
+		state.status = http_replication.ValidationFailed
+		state.lastError = err.Error()
+	default:
+		state.status = http_replication.FailedStatus
+		state.lastError = err.Error()
+	}
+	s.state[conf.ReplicationConf.Id] = state
+}
+
+func (s *Service) setNextSync(id string, next time.Time) {
+	s.stateLock.Lock()
+	defer s.stateLock.Unlock()
+
+	state := s.state[id]
+	state.nextSync = next
+	s.state[id] = state
+}
+
+// getCachedHash returns the hash of the content that has been validated during the latest replication.
+func (s *Service) getCachedHash(id string) (string, bool) {
+	s.cacheLock.Lock()
+	defer s.cacheLock.Unlock()
+
+	hash, found := s.cache[id]
+	return hash, found
+}
+
+func (s *Service) setCachedHash(id, hash string) {
+	s.cacheLock.Lock()
+	defer s.cacheLock.Unlock()
+
+	s.cache[id] = hash
+}
+
+// getVersion returns the version of the response's content as announced by the server, if any.
+func getVersion(resp *http.Response) string {
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		return etag
+	}
+	return resp.Header.Get("Last-Modified")
+}
+
+// destinationHasContent returns true if the item's destination holds the content with the given hash.
+func destinationHasContent(conf http_replication.ReplicationItem, hash string) bool {
+	if hasher, ok := conf.Destination.(ContentHasher); ok {
+		destHash, err := hasher.ContentHash()
+		return err == nil && destHash == hash
+	}
+
+	data, err := conf.Destination.Read()
+	return err == nil && storage.ContentMatches(data, hash)
+}
+
 func hashContent(data []byte) string {
-	hasher := sha256.New()
-	hasher.Write(bytes.TrimSpace(data))
-	hashBytes := hasher.Sum(nil)
-	hashString := hex.EncodeToString(hashBytes)
-	return hashString
+	return storage.ContentHash(data)
 }
 
 func updateMetricsHash(id string, data []byte) {
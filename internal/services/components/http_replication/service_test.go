@@ -0,0 +1,449 @@
+package http_replication
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/soerenschneider/sc-agent/internal/domain/http_replication"
+	"github.com/soerenschneider/sc-agent/internal/storage"
+)
+
+func TestService_ReplicateStatus(t *testing.T) {
+	statusCode := http.StatusOK
+	body := "hello"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		w.WriteHeader(statusCode)
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	item := http_replication.ReplicationItem{
+		ReplicationConf: http_replication.ReplicationConf{
+			Id:     "ca",
+			Source: server.URL,
+		},
+		Destination: &storage.InMemory{},
+	}
+
+	service, err := New(server.Client(), []http_replication.ReplicationItem{item})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, _ := service.GetReplicationItem("ca")
+	if got.Status != http_replication.Unknown || !got.LastAttempt.IsZero() {
+		t.Fatalf("expected unknown status before first attempt, got %v", got.Status)
+	}
+
+	if err := service.Replicate(context.Background(), item); err != nil {
+		t.Fatalf("Replicate() unexpected error: %v", err)
+	}
+
+	got, _ = service.GetReplicationItem("ca")
+	if got.Status != http_replication.Synced {
+		t.Errorf("Status = %v, want %v", got.Status, http_replication.Synced)
+	}
+	if got.ContentHash != hashContent([]byte("hello")) {
+		t.Errorf("ContentHash = %q", got.ContentHash)
+	}
+	if got.Version != `"v1"` {
+		t.Errorf("Version = %q, want %q", got.Version, `"v1"`)
+	}
+	if got.LastSuccess.IsZero() || got.LastError != "" {
+		t.Errorf("expected successful attempt, got LastSuccess = %v, LastError = %q", got.LastSuccess, got.LastError)
+	}
+	lastSuccess := got.LastSuccess
+
+	statusCode = http.StatusServiceUnavailable
+	if err := service.Replicate(context.Background(), item); err == nil {
+		t.Fatal("Replicate() expected error")
+	}
+
+	got, _ = service.GetReplicationItem("ca")
+	if got.Status != http_replication.FailedStatus {
+		t.Errorf("Status = %v, want %v", got.Status, http_replication.FailedStatus)
+	}
+	if got.LastError == "" {
+		t.Error("expected LastError to be set")
+	}
+	if !got.LastSuccess.Equal(lastSuccess) || got.ContentHash != hashContent([]byte("hello")) {
+		t.Error("expected last success and content hash to be retained")
+	}
+
+	statusCode = http.StatusOK
+	body = "hello again"
+	item.ReplicationConf.FileValidation = &http_replication.FileValidation{Test: "starts_with", Arg: "world"}
+	service.managedItems["ca"] = item
+	if err := service.Replicate(context.Background(), item); err == nil {
+		t.Fatal("Replicate() expected validation error")
+	}
+
+	got, _ = service.GetReplicationItem("ca")
+	if got.Status != http_replication.ValidationFailed {
+		t.Errorf("Status = %v, want %v", got.Status, http_replication.ValidationFailed)
+	}
+}
+
+func TestService_ReplicateConditionalRequests(t *testing.T) {
+	transfers := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		transfers++
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte("hello"))
+	}))
+	defer server.Close()
+
+	dest := &storage.InMemory{}
+	item := http_replication.ReplicationItem{
+		ReplicationConf: http_replication.ReplicationConf{
+			Id:     "ca",
+			Source: server.URL,
+		},
+		Destination: dest,
+	}
+
+	service, err := New(server.Client(), []http_replication.ReplicationItem{item})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := service.Replicate(context.Background(), item); err != nil {
+			t.Fatalf("Replicate() unexpected error: %v", err)
+		}
+	}
+	if transfers != 1 {
+		t.Errorf("expected content to be transferred once, got %d", transfers)
+	}
+
+	got, _ := service.GetReplicationItem("ca")
+	if got.Status != http_replication.Synced || got.Version != `"v1"` || got.ContentHash != hashContent([]byte("hello")) {
+		t.Errorf("unexpected status after not modified response: %+v", got)
+	}
+
+	// the local copy has been modified, the content needs to be downloaded again
+	dest.Data = []byte("tampered\n")
+	if err := service.Replicate(context.Background(), item); err != nil {
+		t.Fatalf("Replicate() unexpected error: %v", err)
+	}
+	if transfers != 2 {
+		t.Errorf("expected content to be transferred again, got %d transfers", transfers)
+	}
+	if string(dest.Data) != "hello\n" {
+		t.Errorf("expected local copy to be restored, got %q", dest.Data)
+	}
+}
+
+func TestService_ReplicateAuthentication(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	passwordFile := filepath.Join(dir, "password")
+	if err := os.WriteFile(tokenFile, []byte("s3cr3t\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(passwordFile, []byte("hunter2\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if r.Header.Get("Authorization") != "Bearer s3cr3t" && (!ok || user != "agent" || password != "hunter2") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte("hello"))
+	}))
+	defer server.Close()
+
+	tokenSource, _ := NewFileTokenSource(tokenFile)
+	bearerAuth, _ := NewBearerTokenAuth(tokenSource)
+	basicAuth, _ := NewBasicAuth("agent", passwordFile)
+	wrongPassword, _ := NewBasicAuth("agent", tokenFile)
+
+	tests := []struct {
+		name          string
+		authenticator http_replication.Authenticator
+		wantErr       bool
+	}{
+		{
+			name:    "no auth",
+			wantErr: true,
+		},
+		{
+			name:          "bearer token",
+			authenticator: bearerAuth,
+		},
+		{
+			name:          "basic auth",
+			authenticator: basicAuth,
+		},
+		{
+			name:          "wrong password",
+			authenticator: wrongPassword,
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := http_replication.ReplicationItem{
+				ReplicationConf: http_replication.ReplicationConf{
+					Id:     "ca",
+					Source: server.URL,
+				},
+				Destination:   &storage.InMemory{},
+				Authenticator: tt.authenticator,
+			}
+
+			service, err := New(server.Client(), []http_replication.ReplicationItem{item})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := service.Replicate(context.Background(), item); (err != nil) != tt.wantErr {
+				t.Errorf("Replicate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestService_ReplicateSignatureValidation(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, _ := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	publicKey := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	digest := sha256.Sum256([]byte("hello"))
+	sig, err := ecdsa.SignASN1(rand.Reader, priv, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := "hello"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/ca.crt.sig" {
+			_, _ = w.Write([]byte(base64.StdEncoding.EncodeToString(sig)))
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	dest := &storage.InMemory{}
+	item := http_replication.ReplicationItem{
+		ReplicationConf: http_replication.ReplicationConf{
+			Id:             "ca",
+			Source:         server.URL + "/ca.crt",
+			FileValidation: &http_replication.FileValidation{Test: "cosign", Arg: string(publicKey)},
+		},
+		Destination: dest,
+	}
+
+	service, err := New(server.Client(), []http_replication.ReplicationItem{item})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := service.Replicate(context.Background(), item); err != nil {
+		t.Fatalf("Replicate() unexpected error: %v", err)
+	}
+
+	body = "forged"
+	if err := service.Replicate(context.Background(), item); !errors.Is(err, http_replication.ErrFileValidationFailed) {
+		t.Fatalf("Replicate() expected ErrFileValidationFailed, got %v", err)
+	}
+	if string(dest.Data) != "hello\n" {
+		t.Errorf("expected forged content not to be written, got %q", dest.Data)
+	}
+}
+
+func TestService_ReplicateArchive(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, _ := zw.Create("index.html")
+	_, _ = w.Write([]byte("hello"))
+	_ = zw.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(buf.Bytes())
+	}))
+	defer server.Close()
+
+	dest := filepath.Join(t.TempDir(), "site")
+	archive, err := storage.NewArchiveStorage(dest, storage.ArchiveFormatZip)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	item := http_replication.ReplicationItem{
+		ReplicationConf: http_replication.ReplicationConf{
+			Id:     "site",
+			Source: server.URL + "/site.zip",
+		},
+		Destination: archive,
+	}
+
+	service, err := New(server.Client(), []http_replication.ReplicationItem{item})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := service.Replicate(context.Background(), item); err != nil {
+		t.Fatalf("Replicate() unexpected error: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(dest, "index.html"))
+	if err != nil || string(content) != "hello" {
+		t.Fatalf("expected archive to be extracted, got %q, %v", content, err)
+	}
+
+	if err := service.Replicate(context.Background(), item); err != nil {
+		t.Fatalf("Replicate() unexpected error: %v", err)
+	}
+
+	// removing the extracted archive is detected using its content hash
+	if err := archive.Rollback(); err != nil {
+		t.Fatalf("Rollback() unexpected error: %v", err)
+	}
+	if err := service.Replicate(context.Background(), item); err != nil {
+		t.Fatalf("Replicate() unexpected error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "index.html")); err != nil {
+		t.Errorf("expected removed archive to be extracted again: %v", err)
+	}
+}
+
+// overlapDetectingStorage records whether writes happened concurrently.
+type overlapDetectingStorage struct {
+	storage.InMemory
+	writing  atomic.Int32
+	overlaps atomic.Int32
+}
+
+func (s *overlapDetectingStorage) Write(data []byte) error {
+	if s.writing.Add(1) > 1 {
+		s.overlaps.Add(1)
+	}
+	defer s.writing.Add(-1)
+
+	time.Sleep(5 * time.Millisecond)
+	return nil
+}
+
+func (s *overlapDetectingStorage) Read() ([]byte, error) {
+	return nil, storage.ErrNoCertFound
+}
+
+func TestService_ReplicateSharedDestination(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.Path))
+	}))
+	defer server.Close()
+
+	dest := &overlapDetectingStorage{}
+	var items []http_replication.ReplicationItem
+	for _, id := range []string{"a", "b"} {
+		items = append(items, http_replication.ReplicationItem{
+			ReplicationConf: http_replication.ReplicationConf{
+				Id:           id,
+				Source:       server.URL + "/" + id,
+				Destinations: []string{"/etc/shared"},
+			},
+			Destination: dest,
+		})
+	}
+
+	service, err := New(server.Client(), items)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 5; i++ {
+		for _, item := range items {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := service.Replicate(context.Background(), item); err != nil {
+					t.Errorf("Replicate() unexpected error: %v", err)
+				}
+			}()
+		}
+	}
+	wg.Wait()
+
+	if dest.overlaps.Load() > 0 {
+		t.Errorf("detected %d concurrent writes to the same destination", dest.overlaps.Load())
+	}
+}
+
+func TestService_ReplicateSignatureValidationTrimWhitespaces(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, _ := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	publicKey := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	// the signature is created over the original content including the trailing whitespace
+	body := "hello\n\n"
+	digest := sha256.Sum256([]byte(body))
+	sig, err := ecdsa.SignASN1(rand.Reader, priv, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/ca.crt.sig" {
+			_, _ = w.Write([]byte(base64.StdEncoding.EncodeToString(sig)))
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	dest := &storage.InMemory{}
+	item := http_replication.ReplicationItem{
+		ReplicationConf: http_replication.ReplicationConf{
+			Id:              "ca",
+			Source:          server.URL + "/ca.crt",
+			TrimWhitespaces: true,
+			FileValidation:  &http_replication.FileValidation{Test: "cosign", Arg: string(publicKey)},
+		},
+		Destination: dest,
+	}
+
+	service, err := New(server.Client(), []http_replication.ReplicationItem{item})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := service.Replicate(context.Background(), item); err != nil {
+		t.Fatalf("Replicate() unexpected error: %v", err)
+	}
+	if string(dest.Data) != "hello\n" {
+		t.Errorf("expected trimmed content to be written, got %q", dest.Data)
+	}
+}
@@ -166,13 +166,13 @@ func (s *Service) Issue(ctx context.Context, conf domain.ManagedCertificateConfi
 	metrics.PkiReadRequests.WithLabelValues(conf.CertificateConfig.Id).Inc()
 	metrics.PkiRequestTimestamp.WithLabelValues(conf.CertificateConfig.Id).SetToCurrentTime()
 
-	storage, ok := s.certStorage[conf.CertificateConfig.Id]
+	certStorage, ok := s.certStorage[conf.CertificateConfig.Id]
 	if !ok {
 		metrics.PkiErrors.WithLabelValues(conf.CertificateConfig.Id, "no_storage").Inc()
 		return ErrCertConfigNotFound
 	}
 
-	issueNewCertificate, err := s.shouldIssueNewCertificate(ctx, storage)
+	issueNewCertificate, err := s.shouldIssueNewCertificate(ctx, certStorage)
 	if err == nil && !issueNewCertificate {
 		log.Info().Str(logComponent, pkiServiceComponent).Str(logCommonName, conf.CertificateConfig.CommonName).Str(logAction, "nop").Msg("cert exists and does not need a renewal")
 		return nil
@@ -196,14 +196,19 @@ func (s *Service) Issue(ctx context.Context, conf domain.ManagedCertificateConfi
 		log.Info().Str(logComponent, pkiServiceComponent).Str(logCommonName, conf.CertificateConfig.CommonName).Str(logAction, "issued").Int64(logExpiration, x509Cert.NotAfter.Unix()).Msgf("issued certificate valid until %v (%s)", x509Cert.NotAfter, time.Until(x509Cert.NotAfter).Round(time.Second))
 	}
 
-	if err := storage.WriteCert(cert); err != nil {
+	if err := certStorage.WriteCert(cert); err != nil {
 		metrics.PkiErrors.WithLabelValues(conf.CertificateConfig.Id, "write_cert").Inc()
 		return err
 	}
 
 	if len(conf.PostHooks) > 0 {
-		metrics.PkiErrors.WithLabelValues(conf.CertificateConfig.Id, "run_hooks").Inc()
-		return pkg.RunPostIssueHooks(conf.PostHooks)
+		if err := pkg.RunPostIssueHooks(conf.PostHooks); err != nil {
+			metrics.PkiErrors.WithLabelValues(conf.CertificateConfig.Id, "run_hooks").Inc()
+			if rollbackErr := storage.Rollback(ctx, pkiServiceComponent, conf.CertificateConfig.Id, err, certStorage); rollbackErr != nil {
+				metrics.PkiErrors.WithLabelValues(conf.CertificateConfig.Id, "rollback").Inc()
+			}
+			return err
+		}
 	}
 
 	return nil
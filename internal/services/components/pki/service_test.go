@@ -0,0 +1,137 @@
+package pki
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/soerenschneider/sc-agent/internal/config/vault"
+	"github.com/soerenschneider/sc-agent/internal/vaulttest"
+	"github.com/soerenschneider/sc-agent/pkg/pki"
+)
+
+func countRequests(server *vaulttest.Server, suffix string) int {
+	count := 0
+	for _, req := range server.Requests() {
+		if strings.HasSuffix(req.Path, suffix) {
+			count++
+		}
+	}
+	return count
+}
+
+func TestService_IssueLifecycle(t *testing.T) {
+	server := vaulttest.NewServer(t)
+	server.EnablePki("pki")
+
+	client, err := NewVaultClient(server.Client().Logical())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	conf := vault.X509Pki{
+		MountPath: "pki",
+		ManagedCerts: []vault.CertConfig{
+			{
+				Id:         "web",
+				Role:       "servers",
+				CommonName: "web.example.com",
+				AltNames:   []string{"www.example.com"},
+				Ttl:        "24h",
+				Storage: []vault.CertStorage{
+					{
+						CertFile: filepath.Join(dir, "cert.pem"),
+						KeyFile:  filepath.Join(dir, "key.pem"),
+						CaFile:   filepath.Join(dir, "ca.pem"),
+					},
+				},
+			},
+		},
+	}
+
+	service, err := NewService(client, conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	managed, err := service.GetManagedCertificateConfig("web")
+	if err == nil {
+		t.Fatalf("GetManagedCertificateConfig() expected error before issuing, got %+v", managed)
+	}
+
+	ctx := context.Background()
+	if err := service.Issue(ctx, conf.ManagedCerts[0].ToDomainModel()); err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+
+	certData, err := os.ReadFile(filepath.Join(dir, "cert.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := pki.ParseCertPem(certData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := verifyCertAgainstCa(cert, server.PkiCa("pki")); err != nil {
+		t.Errorf("Issue() wrote certificate that can not be verified: %v", err)
+	}
+	if cert.Subject.CommonName != "web.example.com" || !strings.Contains(strings.Join(cert.DNSNames, ","), "www.example.com") {
+		t.Errorf("Issue() wrote certificate for %q %v", cert.Subject.CommonName, cert.DNSNames)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "key.pem")); err != nil {
+		t.Errorf("Issue() did not write private key: %v", err)
+	}
+
+	// the certificate is valid and must not be issued again
+	if err := service.Issue(ctx, conf.ManagedCerts[0].ToDomainModel()); err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	if got := countRequests(server, "/issue/servers"); got != 1 {
+		t.Errorf("Issue() expected certificate to be issued once, got %d", got)
+	}
+
+	managed, err = service.GetManagedCertificateConfig("web")
+	if err != nil || managed.Certificate == nil {
+		t.Fatalf("GetManagedCertificateConfig() = %+v, %v", managed, err)
+	}
+
+	ca, err := service.ReadCa(ctx)
+	if err != nil {
+		t.Fatalf("ReadCa() error = %v", err)
+	}
+	caBlock, _ := pem.Decode(ca)
+	if caBlock == nil || !server.PkiCa("pki").Equal(mustParseCertificate(t, caBlock.Bytes)) {
+		t.Errorf("ReadCa() returned unexpected ca %q", ca)
+	}
+
+	server.RevokeCertificate("pki", pki.FormatSerial(cert.SerialNumber))
+	crlData, err := client.ReadCrl(ctx, true)
+	if err != nil {
+		t.Fatalf("ReadCrl() error = %v", err)
+	}
+	crl, err := x509.ParseRevocationList(crlData)
+	if err != nil {
+		t.Fatalf("ReadCrl() returned invalid crl: %v", err)
+	}
+	if err := crl.CheckSignatureFrom(server.PkiCa("pki")); err != nil {
+		t.Errorf("ReadCrl() returned crl with invalid signature: %v", err)
+	}
+	if len(crl.RevokedCertificateEntries) != 1 || crl.RevokedCertificateEntries[0].SerialNumber.Cmp(cert.SerialNumber) != 0 {
+		t.Errorf("ReadCrl() expected revoked certificate, got %v", crl.RevokedCertificateEntries)
+	}
+}
+
+func mustParseCertificate(t *testing.T, der []byte) *x509.Certificate {
+	t.Helper()
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
+	"github.com/soerenschneider/sc-agent/internal/storage"
 	"github.com/soerenschneider/sc-agent/pkg/pki"
 )
 
@@ -178,3 +179,15 @@ func (f *KeyPairSink) writeToIndividualSlots(certData *pki.CertData) error {
 
 	return nil
 }
+
+// Rollback restores the previous content of all storage implementations that have been written to.
+func (f *KeyPairSink) Rollback() error {
+	var impls []any
+	for _, impl := range []StorageImplementation{f.cert, f.privateKey, f.ca, f.caChain} {
+		if impl != nil {
+			impls = append(impls, impl)
+		}
+	}
+
+	return storage.RollbackAll(impls...)
+}
@@ -33,6 +33,15 @@ func (fs *MultiKeyPairSink) WriteCert(certData *pki2.CertData) error {
 	return errs
 }
 
+// Rollback restores the previous content of all sinks.
+func (fs *MultiKeyPairSink) Rollback() error {
+	impls := make([]any, len(fs.sinks))
+	for idx := range fs.sinks {
+		impls[idx] = fs.sinks[idx]
+	}
+	return storage.RollbackAll(impls...)
+}
+
 // sanitize checks whether all configured storage backends contain data.
 // This is needed in cases where a new storage backend is added to the configuration after data was already
 // written to existing backends. The new backend would not receive existing data due to the way the Read() method
@@ -0,0 +1,140 @@
+package secret_replication
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"filippo.io/age"
+	"filippo.io/age/agessh"
+	"gopkg.in/yaml.v3"
+)
+
+const sopsCmdTimeout = 30 * time.Second
+
+type commandRunner func(ctx context.Context, name string, args ...string) ([]byte, error)
+
+// SopsBackend reads secrets from a SOPS-encrypted YAML or JSON file. The file is decrypted using the "sops" binary, so
+// all key management services supported by SOPS can be used. The secret path addresses a nested map within the
+// decrypted document, e.g. "prod/db".
+type SopsBackend struct {
+	file string
+	run  commandRunner
+}
+
+func NewSopsBackend(file string) (*SopsBackend, error) {
+	if file == "" {
+		return nil, errors.New("empty file passed")
+	}
+
+	return &SopsBackend{
+		file: file,
+		run:  runCommand,
+	}, nil
+}
+
+func (b *SopsBackend) ReadSecret(ctx context.Context, path string) (map[string]any, error) {
+	ctx, cancel := context.WithTimeout(ctx, sopsCmdTimeout)
+	defer cancel()
+
+	decrypted, err := b.run(ctx, "sops", "--decrypt", "--output-type", "json", b.file)
+	if err != nil {
+		return nil, fmt.Errorf("could not decrypt %q: %w", b.file, err)
+	}
+
+	return parseDocument(decrypted, path)
+}
+
+// AgeBackend reads secrets from an age-encrypted YAML or JSON file. The secret path addresses a nested map within the
+// decrypted document, e.g. "prod/db".
+type AgeBackend struct {
+	file         string
+	identityFile string
+}
+
+func NewAgeBackend(file, identityFile string) (*AgeBackend, error) {
+	if file == "" {
+		return nil, errors.New("empty file passed")
+	}
+
+	if identityFile == "" {
+		return nil, errors.New("empty identity file passed")
+	}
+
+	return &AgeBackend{
+		file:         file,
+		identityFile: identityFile,
+	}, nil
+}
+
+func (b *AgeBackend) ReadSecret(_ context.Context, path string) (map[string]any, error) {
+	identities, err := parseAgeIdentities(b.identityFile)
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, err := os.Open(b.file)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = encrypted.Close()
+	}()
+
+	reader, err := age.Decrypt(encrypted, identities...)
+	if err != nil {
+		return nil, fmt.Errorf("could not decrypt %q: %w", b.file, err)
+	}
+
+	decrypted, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("could not decrypt %q: %w", b.file, err)
+	}
+
+	return parseDocument(decrypted, path)
+}
+
+// parseAgeIdentities parses native age identities or an unencrypted SSH private key.
+func parseAgeIdentities(file string) ([]age.Identity, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("could not read age identity file: %w", err)
+	}
+
+	if bytes.Contains(data, []byte("-----BEGIN")) {
+		identity, err := agessh.ParseIdentity(data)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse ssh identity %q: %w", file, err)
+		}
+		return []age.Identity{identity}, nil
+	}
+
+	return age.ParseIdentities(bytes.NewReader(data))
+}
+
+// parseDocument parses a YAML or JSON document and returns the map at the given path.
+func parseDocument(data []byte, path string) (map[string]any, error) {
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("could not parse decrypted document: %w", err)
+	}
+
+	return lookupPath(doc, path)
+}
+
+func runCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...) // #nosec G204
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
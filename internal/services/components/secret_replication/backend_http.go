@@ -0,0 +1,192 @@
+package secret_replication
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	vault "github.com/hashicorp/vault/api"
+)
+
+const maxBackendResponseBytes = 1 << 20
+
+type HttpClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// OnePasswordConnectBackend reads items from a 1Password Connect server. The secret path is of the form
+// "<vault id>/<item id>", the fields of the item are returned keyed by their label.
+type OnePasswordConnectBackend struct {
+	client    HttpClient
+	address   string
+	tokenFile string
+}
+
+func NewOnePasswordConnectBackend(client HttpClient, address, tokenFile string) (*OnePasswordConnectBackend, error) {
+	if client == nil {
+		return nil, errors.New("empty client passed")
+	}
+
+	if address == "" {
+		return nil, errors.New("empty address passed")
+	}
+
+	if tokenFile == "" {
+		return nil, errors.New("empty token file passed")
+	}
+
+	return &OnePasswordConnectBackend{
+		client:    client,
+		address:   strings.TrimSuffix(address, "/"),
+		tokenFile: tokenFile,
+	}, nil
+}
+
+type onePasswordItem struct {
+	Fields []struct {
+		Id    string `json:"id"`
+		Label string `json:"label"`
+		Value string `json:"value"`
+	} `json:"fields"`
+}
+
+func (b *OnePasswordConnectBackend) ReadSecret(ctx context.Context, path string) (map[string]any, error) {
+	vaultId, itemId, found := strings.Cut(strings.Trim(path, "/"), "/")
+	if !found || vaultId == "" || itemId == "" || strings.Contains(itemId, "/") {
+		return nil, fmt.Errorf("expected path of the form <vault>/<item>, got %q", path)
+	}
+
+	endpoint := fmt.Sprintf("%s/v1/vaults/%s/items/%s", b.address, url.PathEscape(vaultId), url.PathEscape(itemId))
+	var item onePasswordItem
+	if err := getJson(ctx, b.client, endpoint, b.tokenFile, &item); err != nil {
+		return nil, err
+	}
+
+	ret := make(map[string]any, len(item.Fields))
+	for _, field := range item.Fields {
+		key := field.Label
+		if key == "" {
+			key = field.Id
+		}
+		ret[key] = field.Value
+	}
+	return ret, nil
+}
+
+// BitwardenBackend reads items from a Bitwarden-compatible API as served by "bw serve". The secret path is the id of
+// the item. The username, password and notes of the item as well as its custom fields are returned.
+type BitwardenBackend struct {
+	client    HttpClient
+	address   string
+	tokenFile string
+}
+
+// NewBitwardenBackend returns a backend for the given address. The token file is optional, "bw serve" does not
+// require authentication but may be put behind an authenticating proxy.
+func NewBitwardenBackend(client HttpClient, address, tokenFile string) (*BitwardenBackend, error) {
+	if client == nil {
+		return nil, errors.New("empty client passed")
+	}
+
+	if address == "" {
+		return nil, errors.New("empty address passed")
+	}
+
+	return &BitwardenBackend{
+		client:    client,
+		address:   strings.TrimSuffix(address, "/"),
+		tokenFile: tokenFile,
+	}, nil
+}
+
+type bitwardenResponse struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+	Data    struct {
+		Notes string `json:"notes"`
+		Login *struct {
+			Username string `json:"username"`
+			Password string `json:"password"`
+		} `json:"login"`
+		Fields []struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		} `json:"fields"`
+	} `json:"data"`
+}
+
+func (b *BitwardenBackend) ReadSecret(ctx context.Context, path string) (map[string]any, error) {
+	itemId := strings.Trim(path, "/")
+	if itemId == "" {
+		return nil, errors.New("empty item id")
+	}
+
+	var resp bitwardenResponse
+	if err := getJson(ctx, b.client, b.address+"/object/item/"+url.PathEscape(itemId), b.tokenFile, &resp); err != nil {
+		return nil, err
+	}
+
+	if !resp.Success {
+		return nil, fmt.Errorf("could not read item %q: %s", itemId, resp.Message)
+	}
+
+	ret := map[string]any{}
+	if resp.Data.Login != nil {
+		ret["username"] = resp.Data.Login.Username
+		ret["password"] = resp.Data.Login.Password
+	}
+	if resp.Data.Notes != "" {
+		ret["notes"] = resp.Data.Notes
+	}
+	for _, field := range resp.Data.Fields {
+		ret[field.Name] = field.Value
+	}
+	return ret, nil
+}
+
+// getJson sends a GET request to the endpoint and decodes the JSON response. If a token file is given, its content is
+// sent as bearer token. The token is read for every request, so rotated tokens are picked up automatically.
+func getJson(ctx context.Context, client HttpClient, endpoint, tokenFile string, target any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	if tokenFile != "" {
+		token, err := os.ReadFile(tokenFile)
+		if err != nil {
+			return fmt.Errorf("could not read token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %q", vault.ErrSecretNotFound, endpoint)
+	}
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("wrong status code, expected 2xx got %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBackendResponseBytes))
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(body, target)
+}
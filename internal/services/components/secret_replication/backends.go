@@ -0,0 +1,87 @@
+package secret_replication
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	vault "github.com/hashicorp/vault/api"
+)
+
+var ErrBackendUnsupported = errors.New("operation not supported by secret backend")
+
+// SecretBackend reads secrets from a source other than Vault. Secrets are returned as map that is passed to the
+// formatters like the data of a KV v2 secret.
+type SecretBackend interface {
+	ReadSecret(ctx context.Context, path string) (map[string]any, error)
+}
+
+// BackendClient adapts a SecretBackend to a ReplicationClient. Backends have no notion of versions or leases, so their
+// secrets are read on every replication and can not be used for dynamic secrets.
+type BackendClient struct {
+	backend SecretBackend
+}
+
+func NewBackendClient(backend SecretBackend) (*BackendClient, error) {
+	if backend == nil {
+		return nil, errors.New("empty backend passed")
+	}
+
+	return &BackendClient{backend: backend}, nil
+}
+
+func (c *BackendClient) ReadSecret(ctx context.Context, path string) (*vault.KVSecret, error) {
+	data, err := c.backend.ReadSecret(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vault.KVSecret{Data: data}, nil
+}
+
+func (c *BackendClient) ReadSecretMetadata(_ context.Context, _ string) (*vault.KVMetadata, error) {
+	return nil, ErrBackendUnsupported
+}
+
+func (c *BackendClient) Read(ctx context.Context, path string) (*vault.Secret, error) {
+	data, err := c.backend.ReadSecret(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vault.Secret{Data: data}, nil
+}
+
+func (c *BackendClient) RenewLease(_ context.Context, _ string, _ time.Duration) (*vault.Secret, error) {
+	return nil, ErrBackendUnsupported
+}
+
+func (c *BackendClient) RevokeLease(_ context.Context, _ string) error {
+	return ErrBackendUnsupported
+}
+
+// lookupPath returns the map found at the slash-separated path within the given document, e.g. "prod/db" returns the
+// value of the key "db" of the map "prod". An empty path returns the whole document.
+func lookupPath(doc map[string]any, path string) (map[string]any, error) {
+	current := doc
+	for _, key := range strings.Split(strings.Trim(path, "/"), "/") {
+		if key == "" {
+			continue
+		}
+
+		val, found := current[key]
+		if !found {
+			return nil, fmt.Errorf("%w: %q", vault.ErrSecretNotFound, path)
+		}
+
+		next, ok := val.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("value of %q at %q is not a map", key, path)
+		}
+		current = next
+	}
+
+	return current, nil
+}
@@ -0,0 +1,350 @@
+package secret_replication
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+	vault "github.com/hashicorp/vault/api"
+	"github.com/soerenschneider/sc-agent/internal/domain/secret_replication"
+	"github.com/soerenschneider/sc-agent/internal/services/components/secret_replication/formatter"
+	"github.com/soerenschneider/sc-agent/internal/storage"
+)
+
+func Test_lookupPath(t *testing.T) {
+	doc := map[string]any{
+		"prod": map[string]any{
+			"db": map[string]any{"password": "secret"},
+		},
+		"plain": "value",
+	}
+
+	tests := []struct {
+		name         string
+		path         string
+		want         map[string]any
+		wantNotFound bool
+		wantErr      bool
+	}{
+		{
+			name: "empty path",
+			path: "",
+			want: doc,
+		},
+		{
+			name: "nested path",
+			path: "/prod/db/",
+			want: map[string]any{"password": "secret"},
+		},
+		{
+			name:         "missing key",
+			path:         "prod/api",
+			wantNotFound: true,
+			wantErr:      true,
+		},
+		{
+			name:    "no map",
+			path:    "plain",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := lookupPath(doc, tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("lookupPath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if errors.Is(err, vault.ErrSecretNotFound) != tt.wantNotFound {
+				t.Errorf("lookupPath() error = %v, wantNotFound %v", err, tt.wantNotFound)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("lookupPath() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSopsBackend_ReadSecret(t *testing.T) {
+	backend, err := NewSopsBackend("/etc/sc-agent/secrets.enc.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var gotArgs []string
+	backend.run = func(_ context.Context, name string, args ...string) ([]byte, error) {
+		gotArgs = append([]string{name}, args...)
+		return []byte(`{"prod": {"db": {"password": "secret"}}}`), nil
+	}
+
+	got, err := backend.ReadSecret(context.Background(), "prod/db")
+	if err != nil {
+		t.Fatalf("ReadSecret() error = %v", err)
+	}
+
+	want := map[string]any{"password": "secret"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadSecret() got = %v, want %v", got, want)
+	}
+
+	wantArgs := []string{"sops", "--decrypt", "--output-type", "json", "/etc/sc-agent/secrets.enc.yaml"}
+	if !reflect.DeepEqual(gotArgs, wantArgs) {
+		t.Errorf("ReadSecret() args = %v, want %v", gotArgs, wantArgs)
+	}
+}
+
+func TestAgeBackend_ReadSecret(t *testing.T) {
+	dir := t.TempDir()
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	identityFile := filepath.Join(dir, "identity.txt")
+	if err := os.WriteFile(identityFile, []byte(identity.String()+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var encrypted bytes.Buffer
+	writer, err := age.Encrypt(&encrypted, identity.Recipient())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := writer.Write([]byte("prod:\n  db:\n    password: secret\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	file := filepath.Join(dir, "secrets.yaml.age")
+	if err := os.WriteFile(file, encrypted.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	backend, err := NewAgeBackend(file, identityFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := backend.ReadSecret(context.Background(), "prod/db")
+	if err != nil {
+		t.Fatalf("ReadSecret() error = %v", err)
+	}
+
+	want := map[string]any{"password": "secret"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadSecret() got = %v, want %v", got, want)
+	}
+}
+
+func writeToken(t *testing.T, token string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(file, []byte(token+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestOnePasswordConnectBackend_ReadSecret(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/v1/vaults/infra/items/db" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"fields": [{"id": "password", "label": "db-password", "value": "secret"}, {"id": "username", "value": "admin"}]}`))
+	}))
+	defer server.Close()
+
+	backend, err := NewOnePasswordConnectBackend(server.Client(), server.URL+"/", writeToken(t, "token"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		path         string
+		want         map[string]any
+		wantNotFound bool
+		wantErr      bool
+	}{
+		{
+			name: "existing item",
+			path: "infra/db",
+			want: map[string]any{"db-password": "secret", "username": "admin"},
+		},
+		{
+			name:         "missing item",
+			path:         "infra/api",
+			wantNotFound: true,
+			wantErr:      true,
+		},
+		{
+			name:    "invalid path",
+			path:    "db",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := backend.ReadSecret(context.Background(), tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadSecret() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if errors.Is(err, vault.ErrSecretNotFound) != tt.wantNotFound {
+				t.Errorf("ReadSecret() error = %v, wantNotFound %v", err, tt.wantNotFound)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadSecret() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBitwardenBackend_ReadSecret(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch strings.TrimPrefix(r.URL.Path, "/object/item/") {
+		case "db":
+			_, _ = w.Write([]byte(`{"success": true, "data": {"notes": "prod", "login": {"username": "admin", "password": "secret"}, "fields": [{"name": "port", "value": "5432"}]}}`))
+		default:
+			_, _ = w.Write([]byte(`{"success": false, "message": "Not found."}`))
+		}
+	}))
+	defer server.Close()
+
+	backend, err := NewBitwardenBackend(server.Client(), server.URL, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := backend.ReadSecret(context.Background(), "db")
+	if err != nil {
+		t.Fatalf("ReadSecret() error = %v", err)
+	}
+
+	want := map[string]any{"username": "admin", "password": "secret", "notes": "prod", "port": "5432"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadSecret() got = %v, want %v", got, want)
+	}
+
+	if _, err := backend.ReadSecret(context.Background(), "api"); err == nil {
+		t.Error("ReadSecret() expected error for unsuccessful response")
+	}
+}
+
+type fakeBackend struct {
+	data  map[string]any
+	reads int
+}
+
+func (f *fakeBackend) ReadSecret(_ context.Context, _ string) (map[string]any, error) {
+	f.reads++
+	return f.data, nil
+}
+
+func TestService_ReplicateWithBackend(t *testing.T) {
+	backend := &fakeBackend{data: map[string]any{"password": "secret"}}
+	backendClient, err := NewBackendClient(backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dest := &storage.InMemory{}
+	item := secret_replication.ReplicationItem{
+		ReplicationConf: secret_replication.ReplicationConf{
+			Id:         "db",
+			SecretPath: "prod/db",
+			Backend:    "sops",
+		},
+		Formatter:   &formatter.JsonFormatter{},
+		Destination: dest,
+	}
+
+	service, err := NewService(nil, []secret_replication.ReplicationItem{item}, WithBackend("sops", backendClient))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := service.Replicate(context.Background(), item)
+	if err != nil {
+		t.Fatalf("Replicate() error = %v", err)
+	}
+	if !updated {
+		t.Error("Replicate() expected initial replication to update destination")
+	}
+
+	// backends are not versioned, so the secret is read again but the unchanged content is not written
+	updated, err = service.Replicate(context.Background(), item)
+	if err != nil {
+		t.Fatalf("Replicate() error = %v", err)
+	}
+	if updated {
+		t.Error("Replicate() expected unchanged secret not to update destination")
+	}
+	if backend.reads != 2 {
+		t.Errorf("Replicate() reads = %d, want 2", backend.reads)
+	}
+}
+
+func TestNewService_Backends(t *testing.T) {
+	backendClient, err := NewBackendClient(&fakeBackend{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		client  ReplicationClient
+		conf    secret_replication.ReplicationConf
+		wantErr bool
+	}{
+		{
+			name:   "named backend",
+			client: nil,
+			conf:   secret_replication.ReplicationConf{Id: "a", SecretPath: "a", Backend: "sops"},
+		},
+		{
+			name:    "unknown backend",
+			client:  &fakeClient{},
+			conf:    secret_replication.ReplicationConf{Id: "a", SecretPath: "a", Backend: "bitwarden"},
+			wantErr: true,
+		},
+		{
+			name:    "no default client",
+			client:  nil,
+			conf:    secret_replication.ReplicationConf{Id: "a", SecretPath: "a"},
+			wantErr: true,
+		},
+		{
+			name:    "dynamic secret from backend",
+			client:  nil,
+			conf:    secret_replication.ReplicationConf{Id: "a", SecretPath: "a", Backend: "sops", Dynamic: true},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := secret_replication.ReplicationItem{
+				ReplicationConf: tt.conf,
+				Formatter:       &formatter.JsonFormatter{},
+				Destination:     &storage.InMemory{},
+			}
+			_, err := NewService(tt.client, []secret_replication.ReplicationItem{item}, WithBackend("sops", backendClient))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewService() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package secret_replication
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	"github.com/soerenschneider/sc-agent/internal/domain/secret_replication"
+	"github.com/soerenschneider/sc-agent/internal/events"
+	"github.com/soerenschneider/sc-agent/internal/metrics"
+	"github.com/soerenschneider/sc-agent/pkg"
+)
+
+const eventTypeSecretDeleted = "secrets_replication.secret_deleted"
+
+type secretDeletedEvent struct {
+	Id         string `json:"id"`
+	SecretPath string `json:"secret_path"`
+	DestUri    string `json:"dest_uri"`
+	Policy     string `json:"policy"`
+	Reason     string `json:"reason"`
+}
+
+// handleDeletedSecret applies the item's on_delete policy after one of the secrets it is rendered from has been
+// deleted or destroyed. The policy is only applied once, subsequent calls return without altering the local copy
+// until the secret has been replicated successfully again.
+func (s *Service) handleDeletedSecret(ctx context.Context, item secret_replication.ReplicationItem, reason error) (bool, error) {
+	s.replicatedLock.RLock()
+	alreadyHandled := s.replicated[item.ReplicationConf.Id].status == secret_replication.DeletedStatus
+	s.replicatedLock.RUnlock()
+	if alreadyHandled {
+		return false, nil
+	}
+
+	policy := item.ReplicationConf.OnDelete
+	if policy == "" {
+		policy = secret_replication.OnDeleteKeep
+	}
+
+	log.Warn().Err(reason).Str(logComponent, componentName).Str("id", item.ReplicationConf.Id).Str("policy", string(policy)).Msg("secret has been deleted")
+	metrics.SecretsDeleted.WithLabelValues(item.ReplicationConf.Id, string(policy)).Inc()
+	s.emitSecretDeletedEvent(ctx, item, policy, reason)
+
+	updated, err := s.applyOnDeletePolicy(item, policy)
+	if err != nil {
+		s.recordResult(item, secret_replication.FailedStatus, err)
+		return updated, err
+	}
+
+	// the secret versions are reset so the secret is read again on the next replication
+	contentHash, _ := s.getCachedHash(item.ReplicationConf.Id)
+	s.replicatedLock.Lock()
+	replicated := s.replicated[item.ReplicationConf.Id]
+	replicated.versions = nil
+	replicated.untracked = false
+	if updated {
+		replicated.contentHash = contentHash
+	}
+	s.replicated[item.ReplicationConf.Id] = replicated
+	s.replicatedLock.Unlock()
+
+	if policy == secret_replication.OnDeleteAlert {
+		s.recordResult(item, secret_replication.DeletedStatus, reason)
+		return false, reason
+	}
+	s.recordResult(item, secret_replication.DeletedStatus, nil)
+
+	if updated {
+		if err := pkg.RunPostIssueHooks(item.PostHooks); err != nil {
+			metrics.SecretReplicationErrors.WithLabelValues(item.ReplicationConf.SecretPath, "post_hooks").Inc()
+			s.rollback(ctx, item, err)
+			return true, err
+		}
+	}
+
+	return updated, nil
+}
+
+func (s *Service) applyOnDeletePolicy(item secret_replication.ReplicationItem, policy secret_replication.OnDeletePolicy) (bool, error) {
+	switch policy {
+	case secret_replication.OnDeleteRemove:
+		deleter, ok := item.Destination.(secret_replication.Deleter)
+		if !ok {
+			return false, fmt.Errorf("destination %q does not support removing secrets", item.ReplicationConf.DestUri)
+		}
+
+		if err := item.Destination.CanRead(); err != nil {
+			// nothing to remove
+			s.deleteCachedHash(item.ReplicationConf.Id)
+			return false, nil
+		}
+
+		if err := deleter.Delete(); err != nil {
+			metrics.SecretReplicationErrors.WithLabelValues(item.ReplicationConf.Id, "delete_file").Inc()
+			return false, err
+		}
+		s.deleteCachedHash(item.ReplicationConf.Id)
+		log.Info().Str(logComponent, componentName).Str("id", item.ReplicationConf.Id).Str("dest", item.ReplicationConf.DestUri).Msg("removed local copy of deleted secret")
+		return true, nil
+	case secret_replication.OnDeleteTombstone:
+		return s.updateFile([]byte{}, item)
+	case secret_replication.OnDeleteKeep, secret_replication.OnDeleteAlert:
+		return false, nil
+	default:
+		return false, fmt.Errorf("unknown on_delete policy %q", policy)
+	}
+}
+
+func (s *Service) emitSecretDeletedEvent(ctx context.Context, item secret_replication.ReplicationItem, policy secret_replication.OnDeletePolicy, reason error) {
+	data := secretDeletedEvent{
+		Id:         item.ReplicationConf.Id,
+		SecretPath: item.ReplicationConf.SecretPath,
+		DestUri:    item.ReplicationConf.DestUri,
+		Policy:     string(policy),
+		Reason:     reason.Error(),
+	}
+
+	if err := events.NewEvent(ctx, item.ReplicationConf.Id, eventTypeSecretDeleted, data); err != nil && !errors.Is(err, events.ErrNoEventSinkConfigured) {
+		log.Warn().Err(err).Str(logComponent, componentName).Str("id", item.ReplicationConf.Id).Msg("could not emit event")
+	}
+}
@@ -0,0 +1,127 @@
+package secret_replication
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/soerenschneider/sc-agent/internal/domain/secret_replication"
+	"github.com/soerenschneider/sc-agent/internal/services/components/secret_replication/formatter"
+	"github.com/soerenschneider/sc-agent/internal/storage"
+)
+
+type deletableStorage struct {
+	storage.InMemory
+	deletions int
+}
+
+func (d *deletableStorage) Delete() error {
+	d.deletions++
+	d.Data = nil
+	return nil
+}
+
+func TestService_ReplicateDeletedSecret(t *testing.T) {
+	tests := []struct {
+		name          string
+		policy        secret_replication.OnDeletePolicy
+		wantUpdated   bool
+		wantErr       bool
+		wantData      string
+		wantDeletions int
+	}{
+		{
+			name:     "keep",
+			policy:   "",
+			wantData: "{\"password\":\"secret\"}\n",
+		},
+		{
+			name:          "remove",
+			policy:        secret_replication.OnDeleteRemove,
+			wantUpdated:   true,
+			wantData:      "",
+			wantDeletions: 1,
+		},
+		{
+			name:        "tombstone",
+			policy:      secret_replication.OnDeleteTombstone,
+			wantUpdated: true,
+			wantData:    "\n",
+		},
+		{
+			name:     "alert",
+			policy:   secret_replication.OnDeleteAlert,
+			wantErr:  true,
+			wantData: "{\"password\":\"secret\"}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeClient{secrets: map[string]*fakeSecret{
+				"prod/db": {version: 1, data: map[string]any{"password": "secret"}},
+			}}
+			dest := &deletableStorage{}
+			item := secret_replication.ReplicationItem{
+				ReplicationConf: secret_replication.ReplicationConf{
+					Id:         "db",
+					SecretPath: "prod/db",
+					OnDelete:   tt.policy,
+				},
+				Formatter:   &formatter.JsonFormatter{},
+				Destination: dest,
+			}
+
+			service, err := NewService(client, []secret_replication.ReplicationItem{item})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := service.Replicate(context.Background(), item); err != nil {
+				t.Fatalf("initial replication failed: %v", err)
+			}
+
+			client.secrets["prod/db"].deleted = true
+			updated, err := service.Replicate(context.Background(), item)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Replicate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, secret_replication.ErrSecretDeleted) {
+				t.Errorf("Replicate() expected ErrSecretDeleted, got %v", err)
+			}
+			if updated != tt.wantUpdated {
+				t.Errorf("Replicate() updated = %v, want %v", updated, tt.wantUpdated)
+			}
+			if string(dest.Data) != tt.wantData {
+				t.Errorf("Replicate() data = %q, want %q", dest.Data, tt.wantData)
+			}
+
+			got, _ := service.GetReplicationItem("db")
+			if got.Status != secret_replication.DeletedStatus {
+				t.Errorf("GetReplicationItem() status = %v, want %v", got.Status, secret_replication.DeletedStatus)
+			}
+
+			// the policy is applied only once
+			updated, err = service.Replicate(context.Background(), item)
+			if err != nil || updated {
+				t.Errorf("Replicate() after deletion = %v, %v, want false, nil", updated, err)
+			}
+			if dest.deletions != tt.wantDeletions {
+				t.Errorf("deletions = %d, want %d", dest.deletions, tt.wantDeletions)
+			}
+
+			// the secret is restored
+			client.secrets["prod/db"] = &fakeSecret{version: 2, data: map[string]any{"password": "restored"}}
+			if _, err := service.Replicate(context.Background(), item); err != nil {
+				t.Fatalf("Replicate() after restore failed: %v", err)
+			}
+			if string(dest.Data) != "{\"password\":\"restored\"}\n" {
+				t.Errorf("Replicate() after restore data = %q", dest.Data)
+			}
+			got, _ = service.GetReplicationItem("db")
+			if got.Status != secret_replication.SynchronizedStatus {
+				t.Errorf("GetReplicationItem() status = %v, want %v", got.Status, secret_replication.SynchronizedStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package secret_replication
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/soerenschneider/sc-agent/internal/domain/secret_replication"
+	"github.com/soerenschneider/sc-agent/internal/metrics"
+	"github.com/soerenschneider/sc-agent/pkg"
+)
+
+const (
+	defaultLeaseIncrement         = time.Hour
+	dynamicSecretRetryInterval    = 30 * time.Second
+	maxDynamicSecretRetryInterval = 15 * time.Minute
+	leaseRevokeTimeout            = 10 * time.Second
+)
+
+type lease struct {
+	id        string
+	renewable bool
+	duration  time.Duration
+	expiry    time.Time
+
+	// maxTtlReached is set as soon as Vault does not extend the lease by the requested increment anymore
+	maxTtlReached bool
+}
+
+// dynamicSecret is a dynamic secret that has been issued but not necessarily been written to its destination yet.
+type dynamicSecret struct {
+	lease   *lease
+	content []byte
+}
+
+// manageDynamicSecret fetches a dynamic secret and manages the lifecycle of its lease until the context is canceled.
+// The lease is revoked before returning.
+func (s *Service) manageDynamicSecret(ctx context.Context, wg *sync.WaitGroup, item secret_replication.ReplicationItem) {
+	defer wg.Done()
+
+	// pending is a secret that has been issued but whose post hooks failed, it is reused for the next attempt instead
+	// of issuing yet another credential
+	var current, pending *lease
+	defer func() {
+		if current != nil {
+			s.revokeLease(item, current)
+		}
+		if pending != nil {
+			s.revokeLease(item, pending)
+		}
+	}()
+
+	retryInterval := s.dynamicSecretRetryInterval
+	var issued *dynamicSecret
+	for {
+		var err error
+		if issued == nil {
+			issued, err = s.issueDynamicSecret(ctx, item)
+			if err == nil {
+				pending = issued.lease
+			}
+		}
+		if err == nil {
+			err = s.applyDynamicSecret(ctx, item, issued)
+		}
+		if err != nil {
+			s.recordResult(item, secret_replication.FailedStatus, err)
+			log.Error().Err(err).Str(logComponent, componentName).Str("id", item.ReplicationConf.Id).Dur("retry_in", retryInterval).Msg("could not replicate dynamic secret")
+
+			// the issued secret can not be used anymore if its lease expires before the next attempt
+			if pending != nil && pending.duration > 0 && time.Until(pending.expiry) <= retryInterval {
+				s.revokeLease(item, pending)
+				pending = nil
+				issued = nil
+			}
+
+			if !sleep(ctx, retryInterval) {
+				return
+			}
+			retryInterval = min(2*retryInterval, maxDynamicSecretRetryInterval)
+			continue
+		}
+
+		// the previous lease is not revoked as the credentials may still be in use, it expires on its own
+		current = pending
+		pending = nil
+		issued = nil
+		retryInterval = s.dynamicSecretRetryInterval
+		s.recordResult(item, secret_replication.SynchronizedStatus, nil)
+		if !s.keepLeaseAlive(ctx, item, current) {
+			return
+		}
+	}
+}
+
+// issueDynamicSecret reads a new dynamic secret and formats it.
+func (s *Service) issueDynamicSecret(ctx context.Context, item secret_replication.ReplicationItem) (*dynamicSecret, error) {
+	secret, err := s.clientFor(item).Read(ctx, item.ReplicationConf.SecretPath)
+	if err != nil {
+		metrics.SecretReplicationErrors.WithLabelValues(item.ReplicationConf.SecretPath, getErrorLabel(err)).Inc()
+		return nil, err
+	}
+
+	ret := &dynamicSecret{
+		lease: &lease{
+			id:        secret.LeaseID,
+			renewable: secret.Renewable,
+			duration:  time.Duration(secret.LeaseDuration) * time.Second,
+		},
+	}
+	ret.lease.expiry = time.Now().Add(ret.lease.duration)
+	log.Info().Str(logComponent, componentName).Str("id", item.ReplicationConf.Id).Str("lease_id", ret.lease.id).Dur("lease_duration", ret.lease.duration).Bool("renewable", ret.lease.renewable).Msg("fetched dynamic secret")
+
+	ret.content, err = s.format(item, secret.Data, newTrackingReader(ctx, s.clientFor(item)))
+	if err != nil {
+		s.revokeLease(item, ret.lease)
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// applyDynamicSecret writes an issued dynamic secret to its destination and runs the post hooks if the content has
+// changed. If the post hooks fail, the previous content is restored.
+func (s *Service) applyDynamicSecret(ctx context.Context, item secret_replication.ReplicationItem, secret *dynamicSecret) error {
+	lock := s.getItemLock(item.ReplicationConf.Id)
+	lock.Lock()
+	defer lock.Unlock()
+
+	updated, err := s.updateFile(secret.content, item)
+	if err != nil {
+		return err
+	}
+	s.setContentHash(item, secret.content)
+	s.setLeaseExpiry(item, secret.lease.expiry)
+
+	if updated {
+		metrics.DynamicSecretsRotations.WithLabelValues(item.ReplicationConf.SecretPath).Inc()
+		if err := pkg.RunPostIssueHooks(item.PostHooks); err != nil {
+			metrics.SecretReplicationErrors.WithLabelValues(item.ReplicationConf.SecretPath, "post_hooks").Inc()
+			log.Error().Err(err).Str(logComponent, componentName).Str("id", item.ReplicationConf.Id).Msg("running post hooks failed")
+			s.rollback(ctx, item, err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// keepLeaseAlive renews the lease until it can not be extended anymore. It returns true if a new secret needs to be
+// fetched and false if the context has been canceled.
+func (s *Service) keepLeaseAlive(ctx context.Context, item secret_replication.ReplicationItem, l *lease) bool {
+	increment := item.ReplicationConf.LeaseIncrement
+	if increment <= 0 {
+		increment = defaultLeaseIncrement
+	}
+
+	for {
+		if l.id == "" || l.duration <= 0 {
+			// secret without lease, treat it like a static secret
+			return sleep(ctx, s.replicationInterval)
+		}
+
+		// act after two thirds of the remaining lease duration have passed
+		if !sleep(ctx, time.Until(l.expiry)*2/3) {
+			return false
+		}
+
+		if !l.renewable || l.maxTtlReached {
+			log.Info().Str(logComponent, componentName).Str("id", item.ReplicationConf.Id).Msg("lease can not be renewed anymore, fetching new dynamic secret")
+			return true
+		}
+
+		renewed, err := s.clientFor(item).RenewLease(ctx, l.id, increment)
+		if err != nil {
+			if ctx.Err() != nil {
+				return false
+			}
+			metrics.SecretReplicationErrors.WithLabelValues(item.ReplicationConf.SecretPath, "lease_renewal").Inc()
+			log.Error().Err(err).Str(logComponent, componentName).Str("id", item.ReplicationConf.Id).Msg("could not renew lease, fetching new dynamic secret")
+			return true
+		}
+
+		metrics.DynamicSecretsLeaseRenewals.WithLabelValues(item.ReplicationConf.SecretPath).Inc()
+		l.duration = time.Duration(renewed.LeaseDuration) * time.Second
+		l.expiry = time.Now().Add(l.duration)
+		// Vault caps the lease duration at the max TTL of the lease, the secret needs to be fetched again before
+		// the lease expires.
+		l.maxTtlReached = l.duration < increment
+		s.setLeaseExpiry(item, l.expiry)
+		log.Debug().Str(logComponent, componentName).Str("id", item.ReplicationConf.Id).Dur("lease_duration", l.duration).Bool("max_ttl_reached", l.maxTtlReached).Msg("renewed lease")
+	}
+}
+
+func (s *Service) revokeLease(item secret_replication.ReplicationItem, l *lease) {
+	if l.id == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), leaseRevokeTimeout)
+	defer cancel()
+
+	if err := s.clientFor(item).RevokeLease(ctx, l.id); err != nil {
+		metrics.SecretReplicationErrors.WithLabelValues(item.ReplicationConf.SecretPath, "lease_revocation").Inc()
+		log.Error().Err(err).Str(logComponent, componentName).Str("id", item.ReplicationConf.Id).Msg("could not revoke lease")
+		return
+	}
+
+	log.Info().Str(logComponent, componentName).Str("id", item.ReplicationConf.Id).Msg("revoked lease")
+}
+
+func (s *Service) setLeaseExpiry(item secret_replication.ReplicationItem, expiry time.Time) {
+	metrics.DynamicSecretsLeaseExpiry.WithLabelValues(item.ReplicationConf.SecretPath).Set(float64(expiry.Unix()))
+
+	s.replicatedLock.Lock()
+	defer s.replicatedLock.Unlock()
+	replicated := s.replicated[item.ReplicationConf.Id]
+	replicated.leaseExpiry = expiry
+	s.replicated[item.ReplicationConf.Id] = replicated
+}
+
+// sleep waits for the given duration and returns false if the context has been canceled in the meantime.
+func sleep(ctx context.Context, duration time.Duration) bool {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func validateDynamicItem(item secret_replication.ReplicationItem) error {
+	if len(item.ReplicationConf.SecretPath) == 0 {
+		return fmt.Errorf("dynamic secret %q has no secret path", item.ReplicationConf.Id)
+	}
+	return nil
+}
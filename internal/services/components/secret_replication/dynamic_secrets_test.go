@@ -0,0 +1,180 @@
+package secret_replication
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	vault "github.com/hashicorp/vault/api"
+	"github.com/soerenschneider/sc-agent/internal/domain"
+	"github.com/soerenschneider/sc-agent/internal/domain/secret_replication"
+	"github.com/soerenschneider/sc-agent/internal/services/components/secret_replication/formatter"
+	"github.com/soerenschneider/sc-agent/internal/storage"
+)
+
+type fakeDynamicClient struct {
+	fakeClient
+
+	mutex    sync.Mutex
+	reads    int
+	renewals int
+	revoked  []string
+}
+
+func (f *fakeDynamicClient) Read(_ context.Context, _ string) (*vault.Secret, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.reads++
+	return &vault.Secret{
+		LeaseID:       fmt.Sprintf("lease-%d", f.reads),
+		LeaseDuration: 1,
+		Renewable:     true,
+		Data:          map[string]any{"username": fmt.Sprintf("user-%d", f.reads)},
+	}, nil
+}
+
+func (f *fakeDynamicClient) RenewLease(_ context.Context, _ string, _ time.Duration) (*vault.Secret, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.renewals++
+	// vault caps the lease at the max ttl
+	return &vault.Secret{LeaseDuration: 1}, nil
+}
+
+func (f *fakeDynamicClient) RevokeLease(_ context.Context, leaseId string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.revoked = append(f.revoked, leaseId)
+	return nil
+}
+
+func (f *fakeDynamicClient) getReads() int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.reads
+}
+
+func TestService_manageDynamicSecret(t *testing.T) {
+	client := &fakeDynamicClient{}
+	dest := &storage.InMemory{}
+	item := secret_replication.ReplicationItem{
+		ReplicationConf: secret_replication.ReplicationConf{
+			Id:             "db",
+			SecretPath:     "database/creds/app",
+			Dynamic:        true,
+			LeaseIncrement: time.Hour,
+		},
+		Formatter:   formatter.NewEnvVarFormatter(false),
+		Destination: dest,
+	}
+
+	service, err := NewService(client, []secret_replication.ReplicationItem{item})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := service.Replicate(context.Background(), item); err != secret_replication.ErrDynamicSecretNotReplicable {
+		t.Errorf("Replicate() error = %v, want %v", err, secret_replication.ErrDynamicSecretNotReplicable)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go service.manageDynamicSecret(ctx, wg, item)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for client.getReads() < 2 && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+	cancel()
+	wg.Wait()
+
+	if client.reads != 2 {
+		t.Fatalf("expected secret to be fetched again after reaching max ttl, got %d reads", client.reads)
+	}
+	if client.renewals != 1 {
+		t.Errorf("expected 1 lease renewal, got %d", client.renewals)
+	}
+	if got := string(dest.Data); got != "username=user-2\n" {
+		t.Errorf("expected rotated credentials to be written, got %q", got)
+	}
+	if len(client.revoked) != 1 || client.revoked[0] != "lease-2" {
+		t.Errorf("expected current lease to be revoked on shutdown, got %v", client.revoked)
+	}
+}
+
+func TestService_manageDynamicSecretFailingPostHooks(t *testing.T) {
+	client := &fakeDynamicClient{}
+	dest := &restorableStorage{InMemory: storage.InMemory{Data: []byte("previous\n")}}
+	dir := t.TempDir()
+	attempts := filepath.Join(dir, "attempts")
+	hook := filepath.Join(dir, "hook.sh")
+	if err := os.WriteFile(hook, []byte("echo attempt >> "+attempts+"\nexit 1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	item := secret_replication.ReplicationItem{
+		ReplicationConf: secret_replication.ReplicationConf{
+			Id:         "db",
+			SecretPath: "database/creds/app",
+			Dynamic:    true,
+		},
+		Formatter:   formatter.NewEnvVarFormatter(false),
+		Destination: dest,
+		PostHooks: []domain.PostHook{
+			{Name: "reject", Cmd: "sh " + hook},
+		},
+	}
+
+	service, err := NewService(client, []secret_replication.ReplicationItem{item})
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.dynamicSecretRetryInterval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go service.manageDynamicSecret(ctx, wg, item)
+
+	countAttempts := func() int {
+		content, _ := os.ReadFile(attempts)
+		return strings.Count(string(content), "attempt")
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for countAttempts() < 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	wg.Wait()
+
+	if got := countAttempts(); got < 3 {
+		t.Fatalf("expected post hooks to be retried, got %d attempts", got)
+	}
+	if client.reads != 1 {
+		t.Errorf("expected issued credential to be reused for retries, got %d reads", client.reads)
+	}
+	if string(dest.Data) != "previous\n" {
+		t.Errorf("expected previous content to be restored, got %q", dest.Data)
+	}
+	if len(client.revoked) != 1 || client.revoked[0] != "lease-1" {
+		t.Errorf("expected lease of rejected secret to be revoked, got %v", client.revoked)
+	}
+}
+
+func TestTrackingReader_ReadSecretRejectsLeasedSecrets(t *testing.T) {
+	client := &fakeDynamicClient{}
+	reader := newTrackingReader(context.Background(), client)
+
+	if _, err := reader.ReadSecret("database/creds/app"); !errors.Is(err, secret_replication.ErrLeasedSecretNotReadable) {
+		t.Fatalf("ReadSecret() error = %v, want %v", err, secret_replication.ErrLeasedSecretNotReadable)
+	}
+	if len(client.revoked) != 1 || client.revoked[0] != "lease-1" {
+		t.Errorf("expected lease of rejected secret to be revoked, got %v", client.revoked)
+	}
+}
@@ -1,28 +1,77 @@
 package formatter
 
 import (
-	"bytes"
 	"fmt"
+	"regexp"
 	"strings"
 )
 
+// envKey matches valid names of environment variables
+var envKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// unquotedEnvValue matches values that can be written to dotenv files without quoting
+var unquotedEnvValue = regexp.MustCompile(`^[A-Za-z0-9_./:@%+,=-]*$`)
+
+var envValueReplacer = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	`$`, `\$`,
+	"`", "\\`",
+	"\n", `\n`,
+	"\r", `\r`,
+)
+
+// EnvVarFormatter writes secrets as dotenv file. Keys are sorted and values that contain characters other than a
+// safe set are double-quoted and escaped. Keys are written as they are unless strict key validation is enabled.
 type EnvVarFormatter struct {
 	uppercaseKeys bool
+	strictKeys    bool
+}
+
+type EnvVarOpt func(*EnvVarFormatter)
+
+func NewEnvVarFormatter(uppercaseKeys bool, opts ...EnvVarOpt) *EnvVarFormatter {
+	ret := &EnvVarFormatter{uppercaseKeys: uppercaseKeys}
+
+	for _, opt := range opts {
+		opt(ret)
+	}
+
+	return ret
 }
 
-func NewEnvVarFormatter(uppercaseKeys bool) *EnvVarFormatter {
-	return &EnvVarFormatter{uppercaseKeys: uppercaseKeys}
+// WithStrictEnvKeys rejects keys that are not valid names of environment variables.
+func WithStrictEnvKeys() EnvVarOpt {
+	return func(f *EnvVarFormatter) {
+		f.strictKeys = true
+	}
 }
 
 func (y *EnvVarFormatter) Format(data map[string]any) ([]byte, error) {
-	var buffer bytes.Buffer
+	var builder strings.Builder
+
+	for _, key := range sortedKeys(data) {
+		value, err := stringify(data[key])
+		if err != nil {
+			return nil, err
+		}
 
-	for key, value := range data {
 		if y.uppercaseKeys {
 			key = strings.ToUpper(key)
 		}
-		_, _ = buffer.WriteString(fmt.Sprintf("%s=%s\n", key, value))
+		if y.strictKeys && !envKey.MatchString(key) {
+			return nil, fmt.Errorf("invalid environment variable name %q", key)
+		}
+		writeLine(&builder, key, "=", quoteEnvValue(value))
+	}
+
+	return []byte(builder.String()), nil
+}
+
+func quoteEnvValue(value string) string {
+	if unquotedEnvValue.MatchString(value) {
+		return value
 	}
 
-	return buffer.Bytes(), nil
+	return `"` + envValueReplacer.Replace(value) + `"`
 }
@@ -0,0 +1,222 @@
+package formatter
+
+import (
+	"testing"
+)
+
+func TestFormatters_Format(t *testing.T) {
+	tests := []struct {
+		name      string
+		formatter interface {
+			Format(map[string]any) ([]byte, error)
+		}
+		data    map[string]any
+		want    string
+		wantErr bool
+	}{
+		{
+			name:      "env - sorted and quoted",
+			formatter: NewEnvVarFormatter(false),
+			data: map[string]any{
+				"user":     "app",
+				"password": "pa$$ \"word\"\nline",
+				"port":     5432,
+			},
+			want: "password=\"pa\\$\\$ \\\"word\\\"\\nline\"\nport=5432\nuser=app\n",
+		},
+		{
+			name:      "env - uppercase keys, nested value",
+			formatter: NewEnvVarFormatter(true),
+			data: map[string]any{
+				"hosts": []any{"a", "b"},
+			},
+			want: "HOSTS=\"[\\\"a\\\",\\\"b\\\"]\"\n",
+		},
+		{
+			name:      "env - keys are not validated",
+			formatter: NewEnvVarFormatter(false),
+			data: map[string]any{
+				"db-password": "secret",
+				"a.b":         "c",
+			},
+			want: "a.b=c\ndb-password=secret\n",
+		},
+		{
+			name:      "dotenv - invalid key",
+			formatter: NewEnvVarFormatter(false, WithStrictEnvKeys()),
+			data: map[string]any{
+				"db-password": "secret",
+			},
+			wantErr: true,
+		},
+		{
+			name:      "dotenv - key injecting variable",
+			formatter: NewEnvVarFormatter(true, WithStrictEnvKeys()),
+			data: map[string]any{
+				"x=1\nLD_PRELOAD": "/tmp/evil.so",
+			},
+			wantErr: true,
+		},
+		{
+			name:      "dotenv - key starting with digit",
+			formatter: NewEnvVarFormatter(false, WithStrictEnvKeys()),
+			data: map[string]any{
+				"1password": "secret",
+			},
+			wantErr: true,
+		},
+		{
+			name:      "properties",
+			formatter: &PropertiesFormatter{},
+			data: map[string]any{
+				"db.url":  "jdbc:postgresql://host:5432/db",
+				"my key":  " leading",
+				"unicode": "grüße",
+			},
+			want: "db.url=jdbc\\:postgresql\\://host\\:5432/db\nmy\\ key=\\ leading\nunicode=gr\\u00fc\\u00dfe\n",
+		},
+		{
+			name:      "ini - global values and sections",
+			formatter: &IniFormatter{},
+			data: map[string]any{
+				"name": "app",
+				"database": map[string]any{
+					"password": "x;y",
+					"user":     "app",
+				},
+			},
+			want: "name = app\n\n[database]\npassword = \"x;y\"\nuser = app\n",
+		},
+		{
+			name:      "ini - nested sections",
+			formatter: &IniFormatter{},
+			data: map[string]any{
+				"a": map[string]any{"b": map[string]any{"c": "d"}},
+			},
+			wantErr: true,
+		},
+		{
+			name:      "toml",
+			formatter: &TomlFormatter{},
+			data: map[string]any{
+				"password": "s3cr\"t",
+			},
+			want: "password = 's3cr\"t'\n",
+		},
+		{
+			name:      "netrc - machine from secret",
+			formatter: NewNetrcFormatter(),
+			data: map[string]any{
+				"machine":  "example.com",
+				"login":    "user",
+				"password": "with space",
+			},
+			want: "machine example.com login user password \"with space\"\n",
+		},
+		{
+			name:      "netrc - static machine and custom keys",
+			formatter: NewNetrcFormatter(WithNetrcMachine("api.example.com"), WithNetrcLoginKey("username"), WithNetrcPasswordKey("token")),
+			data: map[string]any{
+				"username": "user",
+				"token":    "abc",
+			},
+			want: "machine api.example.com login user password abc\n",
+		},
+		{
+			name:      "netrc - missing machine",
+			formatter: NewNetrcFormatter(),
+			data: map[string]any{
+				"password": "abc",
+			},
+			wantErr: true,
+		},
+		{
+			name:      "netrc - password injecting machine",
+			formatter: NewNetrcFormatter(WithNetrcMachine("example.com")),
+			data: map[string]any{
+				"password": "abc\nmachine evil.com login user password abc",
+			},
+			wantErr: true,
+		},
+		{
+			name:      "netrc - login with carriage return",
+			formatter: NewNetrcFormatter(WithNetrcMachine("example.com")),
+			data: map[string]any{
+				"login":    "user\r",
+				"password": "abc",
+			},
+			wantErr: true,
+		},
+		{
+			name:      "netrc - tab in password",
+			formatter: NewNetrcFormatter(WithNetrcMachine("example.com")),
+			data: map[string]any{
+				"password": "a\tb",
+			},
+			wantErr: true,
+		},
+		{
+			name:      "pgpass - wildcards",
+			formatter: NewPgpassFormatter(),
+			data: map[string]any{
+				"username": "app",
+				"password": `a:b\c`,
+			},
+			want: "*:*:*:app:a\\:b\\\\c\n",
+		},
+		{
+			name:      "pgpass - static values",
+			formatter: NewPgpassFormatter(WithPgpassHost("db.local"), WithPgpassPort("5432"), WithPgpassDatabase("app")),
+			data: map[string]any{
+				"username": "app",
+				"password": "secret",
+			},
+			want: "db.local:5432:app:app:secret\n",
+		},
+		{
+			name:      "pgpass - missing password",
+			formatter: NewPgpassFormatter(),
+			data:      map[string]any{},
+			wantErr:   true,
+		},
+		{
+			name:      "pgpass - line break in username",
+			formatter: NewPgpassFormatter(),
+			data: map[string]any{
+				"username": "app\n*:*:*:admin",
+				"password": "secret",
+			},
+			wantErr: true,
+		},
+		{
+			name:      "pgpass - carriage return in static host",
+			formatter: NewPgpassFormatter(WithPgpassHost("db.local\r")),
+			data: map[string]any{
+				"username": "app",
+				"password": "secret",
+			},
+			wantErr: true,
+		},
+		{
+			name:      "pgpass - control character in password",
+			formatter: NewPgpassFormatter(),
+			data: map[string]any{
+				"username": "app",
+				"password": "sec\x00ret",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.formatter.Format(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Format() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Format() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
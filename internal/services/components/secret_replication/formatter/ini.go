@@ -0,0 +1,82 @@
+package formatter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var iniValueReplacer = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	"\n", `\n`,
+	"\r", `\r`,
+)
+
+// IniFormatter writes secrets as INI file. Scalar values are written as global properties and nested maps are written
+// as sections. Values that contain whitespace at their boundaries, comment characters or line breaks are
+// double-quoted and escaped.
+type IniFormatter struct {
+}
+
+func (i *IniFormatter) Format(data map[string]any) ([]byte, error) {
+	var builder strings.Builder
+	var sections []string
+
+	for _, key := range sortedKeys(data) {
+		if _, isSection := data[key].(map[string]any); isSection {
+			sections = append(sections, key)
+			continue
+		}
+
+		if err := writeIniProperty(&builder, key, data[key]); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, section := range sections {
+		if strings.ContainsAny(section, "[]\n\r") {
+			return nil, fmt.Errorf("invalid section name %q", section)
+		}
+
+		if builder.Len() > 0 {
+			builder.WriteByte('\n')
+		}
+		writeLine(&builder, "[", section, "]")
+
+		sectionData := data[section].(map[string]any)
+		for _, key := range sortedKeys(sectionData) {
+			if _, isSection := sectionData[key].(map[string]any); isSection {
+				return nil, errors.New("nested sections are not supported")
+			}
+
+			if err := writeIniProperty(&builder, key, sectionData[key]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return []byte(builder.String()), nil
+}
+
+func writeIniProperty(builder *strings.Builder, key string, value any) error {
+	if len(key) == 0 || strings.ContainsAny(key, "=;#[]\n\r") || strings.TrimSpace(key) != key {
+		return fmt.Errorf("invalid key %q", key)
+	}
+
+	stringValue, err := stringify(value)
+	if err != nil {
+		return err
+	}
+
+	writeLine(builder, key, " = ", quoteIniValue(stringValue))
+	return nil
+}
+
+func quoteIniValue(value string) string {
+	if strings.TrimSpace(value) == value && !strings.ContainsAny(value, "\";#\n\r") {
+		return value
+	}
+
+	return `"` + iniValueReplacer.Replace(value) + `"`
+}
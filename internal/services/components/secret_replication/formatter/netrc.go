@@ -0,0 +1,126 @@
+package formatter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+const (
+	defaultNetrcMachineKey  = "machine"
+	defaultNetrcLoginKey    = "login"
+	defaultNetrcPasswordKey = "password"
+)
+
+var netrcValueReplacer = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+)
+
+// NetrcFormatter writes a secret as single entry of a .netrc file. The machine is either given statically or read
+// from the secret, login and password are read from the configured keys of the secret.
+type NetrcFormatter struct {
+	machine     string
+	machineKey  string
+	loginKey    string
+	passwordKey string
+}
+
+type NetrcOpt func(*NetrcFormatter)
+
+func NewNetrcFormatter(opts ...NetrcOpt) *NetrcFormatter {
+	ret := &NetrcFormatter{
+		machineKey:  defaultNetrcMachineKey,
+		loginKey:    defaultNetrcLoginKey,
+		passwordKey: defaultNetrcPasswordKey,
+	}
+
+	for _, opt := range opts {
+		opt(ret)
+	}
+
+	return ret
+}
+
+// WithNetrcMachine sets a static machine name instead of reading it from the secret.
+func WithNetrcMachine(machine string) NetrcOpt {
+	return func(f *NetrcFormatter) {
+		f.machine = machine
+	}
+}
+
+func WithNetrcLoginKey(key string) NetrcOpt {
+	return func(f *NetrcFormatter) {
+		f.loginKey = key
+	}
+}
+
+func WithNetrcPasswordKey(key string) NetrcOpt {
+	return func(f *NetrcFormatter) {
+		f.passwordKey = key
+	}
+}
+
+func (n *NetrcFormatter) Format(data map[string]any) ([]byte, error) {
+	machine := n.machine
+	if machine == "" {
+		var err error
+		machine, err = lookupString(data, n.machineKey, "")
+		if err != nil {
+			return nil, err
+		}
+	}
+	if machine == "" {
+		return nil, errors.New("no machine for netrc entry found")
+	}
+
+	login, err := lookupString(data, n.loginKey, "")
+	if err != nil {
+		return nil, err
+	}
+
+	password, err := lookupString(data, n.passwordKey, "")
+	if err != nil {
+		return nil, err
+	}
+	if password == "" {
+		return nil, errors.New("no password for netrc entry found")
+	}
+
+	quotedMachine, err := quoteNetrcValue("machine", machine)
+	if err != nil {
+		return nil, err
+	}
+	quotedPassword, err := quoteNetrcValue("password", password)
+	if err != nil {
+		return nil, err
+	}
+
+	var builder strings.Builder
+	builder.WriteString("machine " + quotedMachine)
+	if login != "" {
+		quotedLogin, err := quoteNetrcValue("login", login)
+		if err != nil {
+			return nil, err
+		}
+		builder.WriteString(" login " + quotedLogin)
+	}
+	writeLine(&builder, " password ", quotedPassword)
+
+	return []byte(builder.String()), nil
+}
+
+// quoteNetrcValue quotes the value if necessary. Values that contain control characters are rejected, as netrc
+// parsers do not support escaping them.
+func quoteNetrcValue(field, value string) (string, error) {
+	if strings.ContainsFunc(value, unicode.IsControl) {
+		return "", fmt.Errorf("netrc %s contains control characters", field)
+	}
+
+	if value != "" && !strings.ContainsAny(value, " \"\\#") {
+		return value, nil
+	}
+
+	return `"` + netrcValueReplacer.Replace(value) + `"`, nil
+}
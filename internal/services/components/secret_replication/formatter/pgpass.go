@@ -0,0 +1,120 @@
+package formatter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+const pgpassWildcard = "*"
+
+var pgpassValueReplacer = strings.NewReplacer(
+	`\`, `\\`,
+	`:`, `\:`,
+)
+
+// PgpassFormatter writes a secret as single entry of a PostgreSQL password file. Host, port, database and username
+// are read from the secret and default to a wildcard if they are neither part of the secret nor configured
+// statically.
+type PgpassFormatter struct {
+	host     string
+	port     string
+	database string
+	username string
+}
+
+type PgpassOpt func(*PgpassFormatter)
+
+func NewPgpassFormatter(opts ...PgpassOpt) *PgpassFormatter {
+	ret := &PgpassFormatter{
+		host:     pgpassWildcard,
+		port:     pgpassWildcard,
+		database: pgpassWildcard,
+		username: pgpassWildcard,
+	}
+
+	for _, opt := range opts {
+		opt(ret)
+	}
+
+	return ret
+}
+
+func WithPgpassHost(host string) PgpassOpt {
+	return func(f *PgpassFormatter) {
+		f.host = host
+	}
+}
+
+func WithPgpassPort(port string) PgpassOpt {
+	return func(f *PgpassFormatter) {
+		f.port = port
+	}
+}
+
+func WithPgpassDatabase(database string) PgpassOpt {
+	return func(f *PgpassFormatter) {
+		f.database = database
+	}
+}
+
+func WithPgpassUsername(username string) PgpassOpt {
+	return func(f *PgpassFormatter) {
+		f.username = username
+	}
+}
+
+func (p *PgpassFormatter) Format(data map[string]any) ([]byte, error) {
+	fields := []struct {
+		key          string
+		defaultValue string
+	}{
+		{"host", p.host},
+		{"port", p.port},
+		{"database", p.database},
+		{"username", p.username},
+	}
+
+	values := make([]string, 0, len(fields)+1)
+	for _, field := range fields {
+		value, err := lookupString(data, field.key, field.defaultValue)
+		if err != nil {
+			return nil, err
+		}
+		escaped, err := escapePgpassValue(field.key, value)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, escaped)
+	}
+
+	password, err := lookupString(data, "password", "")
+	if err != nil {
+		return nil, err
+	}
+	if password == "" {
+		return nil, errors.New("no password for pgpass entry found")
+	}
+	escaped, err := escapePgpassValue("password", password)
+	if err != nil {
+		return nil, err
+	}
+	values = append(values, escaped)
+
+	return []byte(strings.Join(values, ":") + "\n"), nil
+}
+
+// escapePgpassValue escapes colons and backslashes. Values that contain control characters are rejected, as line
+// breaks would end the entry and inject further entries.
+func escapePgpassValue(field, value string) (string, error) {
+	if strings.ContainsFunc(value, unicode.IsControl) {
+		return "", fmt.Errorf("pgpass %s contains control characters", field)
+	}
+
+	if value == pgpassWildcard {
+		return value, nil
+	}
+
+	return pgpassValueReplacer.Replace(value), nil
+}
@@ -0,0 +1,71 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PropertiesFormatter writes secrets as Java .properties file. Keys are sorted, special characters are escaped and
+// characters outside of ISO-8859-1 are written as unicode escape sequences.
+type PropertiesFormatter struct {
+}
+
+func (p *PropertiesFormatter) Format(data map[string]any) ([]byte, error) {
+	var builder strings.Builder
+
+	for _, key := range sortedKeys(data) {
+		value, err := stringify(data[key])
+		if err != nil {
+			return nil, err
+		}
+
+		writeLine(&builder, escapeProperty(key, true), "=", escapeProperty(value, false))
+	}
+
+	return []byte(builder.String()), nil
+}
+
+func escapeProperty(value string, isKey bool) string {
+	var builder strings.Builder
+	for idx, r := range value {
+		switch r {
+		case '\\':
+			builder.WriteString(`\\`)
+		case '\n':
+			builder.WriteString(`\n`)
+		case '\r':
+			builder.WriteString(`\r`)
+		case '\t':
+			builder.WriteString(`\t`)
+		case '\f':
+			builder.WriteString(`\f`)
+		case '=', ':', '#', '!':
+			builder.WriteRune('\\')
+			builder.WriteRune(r)
+		case ' ':
+			// spaces within keys and leading spaces of values would otherwise be swallowed
+			if isKey || idx == 0 {
+				builder.WriteRune('\\')
+			}
+			builder.WriteRune(r)
+		default:
+			if r < 0x20 || r > 0x7e {
+				writeUnicodeEscape(&builder, r)
+			} else {
+				builder.WriteRune(r)
+			}
+		}
+	}
+
+	return builder.String()
+}
+
+func writeUnicodeEscape(builder *strings.Builder, r rune) {
+	if r > 0xffff {
+		// characters outside the basic multilingual plane are encoded as UTF-16 surrogate pair
+		r -= 0x10000
+		fmt.Fprintf(builder, `\u%04x\u%04x`, 0xd800+(r>>10), 0xdc00+(r&0x3ff))
+		return
+	}
+	fmt.Fprintf(builder, `\u%04x`, r)
+}
@@ -2,15 +2,28 @@ package formatter
 
 import (
 	"bytes"
+	"path/filepath"
 	"text/template"
+
+	"github.com/soerenschneider/sc-agent/internal/domain/secret_replication"
 )
 
+// TemplateFormatter renders a Go template. Besides the data of the item's secret, the template is able to reference
+// other KV v2 secrets using the "secret" function and arbitrary static Vault paths using the "vault" function, e.g.
+//
+//	password={{ (secret "prod/db").password | quote }}
+//	api_key={{ index (vault "kv1/api") "key" }}
+//
+// Paths that return secrets with a lease, e.g. dynamic database credentials, can not be referenced.
 type TemplateFormatter struct {
 	tmpl *template.Template
 }
 
 func NewTemplateFormatter(file string) (*TemplateFormatter, error) {
-	t, err := template.ParseFiles(file)
+	t, err := template.New(filepath.Base(file)).
+		Funcs(helperFuncs()).
+		Funcs(secretFuncs(nil)).
+		ParseFiles(file)
 	if err != nil {
 		return nil, err
 	}
@@ -21,8 +34,17 @@ func NewTemplateFormatter(file string) (*TemplateFormatter, error) {
 }
 
 func (t *TemplateFormatter) Format(data map[string]any) ([]byte, error) {
+	return t.FormatSecrets(data, nil)
+}
+
+func (t *TemplateFormatter) FormatSecrets(data map[string]any, reader secret_replication.SecretReader) ([]byte, error) {
+	tmpl, err := t.tmpl.Clone()
+	if err != nil {
+		return nil, err
+	}
+
 	var result bytes.Buffer
-	if err := t.tmpl.Execute(&result, data); err != nil {
+	if err := tmpl.Funcs(secretFuncs(reader)).Execute(&result, data); err != nil {
 		return nil, err
 	}
 
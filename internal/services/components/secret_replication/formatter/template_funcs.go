@@ -0,0 +1,117 @@
+package formatter
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/soerenschneider/sc-agent/internal/domain/secret_replication"
+	"gopkg.in/yaml.v3"
+)
+
+var errNoSecretReader = errors.New("referencing secrets is not supported in this context")
+
+// helperFuncs returns a small set of sprig-like helper functions that are available in all templates.
+func helperFuncs() template.FuncMap {
+	return template.FuncMap{
+		"base64enc": func(s string) string {
+			return base64.StdEncoding.EncodeToString([]byte(s))
+		},
+		"base64dec": func(s string) (string, error) {
+			decoded, err := base64.StdEncoding.DecodeString(s)
+			return string(decoded), err
+		},
+		"quote": func(v any) string {
+			return strconv.Quote(toString(v))
+		},
+		"squote": func(v any) string {
+			return "'" + strings.ReplaceAll(toString(v), "'", `'\''`) + "'"
+		},
+		"default": func(def, v any) any {
+			if isEmpty(v) {
+				return def
+			}
+			return v
+		},
+		"required": func(msg string, v any) (any, error) {
+			if isEmpty(v) {
+				return nil, errors.New(msg)
+			}
+			return v, nil
+		},
+		"toJson": func(v any) (string, error) {
+			marshalled, err := json.Marshal(v)
+			return string(marshalled), err
+		},
+		"toYaml": func(v any) (string, error) {
+			marshalled, err := yaml.Marshal(v)
+			return strings.TrimSuffix(string(marshalled), "\n"), err
+		},
+		"trim":  func(v any) string { return strings.TrimSpace(toString(v)) },
+		"upper": func(v any) string { return strings.ToUpper(toString(v)) },
+		"lower": func(v any) string { return strings.ToLower(toString(v)) },
+		"indent": func(spaces int, v any) string {
+			pad := strings.Repeat(" ", spaces)
+			return pad + strings.ReplaceAll(toString(v), "\n", "\n"+pad)
+		},
+		"nindent": func(spaces int, v any) string {
+			pad := strings.Repeat(" ", spaces)
+			return "\n" + pad + strings.ReplaceAll(toString(v), "\n", "\n"+pad)
+		},
+	}
+}
+
+// secretFuncs returns the functions that allow templates to reference secrets. If no reader is given, the functions
+// return an error when being invoked.
+func secretFuncs(reader secret_replication.SecretReader) template.FuncMap {
+	return template.FuncMap{
+		"secret": func(path string) (map[string]any, error) {
+			if reader == nil {
+				return nil, errNoSecretReader
+			}
+			return reader.ReadKv2Secret(path)
+		},
+		"vault": func(path string) (map[string]any, error) {
+			if reader == nil {
+				return nil, errNoSecretReader
+			}
+			return reader.ReadSecret(path)
+		},
+	}
+}
+
+func toString(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case []byte:
+		return string(val)
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+func isEmpty(v any) bool {
+	if v == nil {
+		return true
+	}
+
+	val := reflect.ValueOf(v)
+	switch val.Kind() {
+	case reflect.String, reflect.Array, reflect.Slice, reflect.Map:
+		return val.Len() == 0
+	case reflect.Bool:
+		return !val.Bool()
+	case reflect.Pointer, reflect.Interface:
+		return val.IsNil()
+	default:
+		return val.IsZero()
+	}
+}
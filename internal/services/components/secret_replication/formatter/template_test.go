@@ -0,0 +1,115 @@
+package formatter
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type fakeReader struct {
+	kv2     map[string]map[string]any
+	logical map[string]map[string]any
+}
+
+func (f *fakeReader) ReadKv2Secret(path string) (map[string]any, error) {
+	if data, found := f.kv2[path]; found {
+		return data, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func (f *fakeReader) ReadSecret(path string) (map[string]any, error) {
+	if data, found := f.logical[path]; found {
+		return data, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func TestTemplateFormatter_FormatSecrets(t *testing.T) {
+	reader := &fakeReader{
+		kv2: map[string]map[string]any{
+			"prod/db": {"password": "s3cr3t", "user": "app"},
+		},
+		logical: map[string]map[string]any{
+			"database/creds/app": {"username": "v-app-123"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		data     map[string]any
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "item data",
+			template: `{{ .password }}`,
+			data:     map[string]any{"password": "pw"},
+			want:     "pw",
+		},
+		{
+			name:     "referenced kv2 secret",
+			template: `{{ (secret "prod/db").user }}:{{ (secret "prod/db").password }}`,
+			want:     "app:s3cr3t",
+		},
+		{
+			name:     "other engine",
+			template: `{{ (vault "database/creds/app").username }}`,
+			want:     "v-app-123",
+		},
+		{
+			name:     "helpers",
+			template: `{{ (secret "prod/db").password | base64enc }} {{ .missing | default "fallback" | quote }} {{ secret "prod/db" | toJson }}`,
+			want:     `czNjcjN0 "fallback" {"password":"s3cr3t","user":"app"}`,
+		},
+		{
+			name:     "required",
+			template: `{{ .missing | required "missing is required" }}`,
+			wantErr:  true,
+		},
+		{
+			name:     "unknown secret",
+			template: `{{ (secret "unknown").password }}`,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "test.tmpl")
+			if err := os.WriteFile(file, []byte(tt.template), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			formatter, err := NewTemplateFormatter(file)
+			if err != nil {
+				t.Fatalf("NewTemplateFormatter() error = %v", err)
+			}
+
+			got, err := formatter.FormatSecrets(tt.data, reader)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FormatSecrets() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(got) != tt.want {
+				t.Errorf("FormatSecrets() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplateFormatter_FormatWithoutReader(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "test.tmpl")
+	if err := os.WriteFile(file, []byte(`{{ (secret "prod/db").password }}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	formatter, err := NewTemplateFormatter(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := formatter.Format(nil); err == nil {
+		t.Error("Format() expected error when referencing secrets without reader")
+	}
+}
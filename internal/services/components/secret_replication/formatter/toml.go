@@ -0,0 +1,20 @@
+package formatter
+
+import (
+	"bytes"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+type TomlFormatter struct {
+}
+
+func (t *TomlFormatter) Format(data map[string]any) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := toml.NewEncoder(&buf)
+	if err := encoder.Encode(data); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
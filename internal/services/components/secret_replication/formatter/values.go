@@ -0,0 +1,56 @@
+package formatter
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// sortedKeys returns the keys of the map in lexical order so rendered output is deterministic.
+func sortedKeys[V any](data map[string]V) []string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// stringify converts a secret's value to its string representation. Complex values such as maps and slices are
+// encoded as JSON.
+func stringify(value any) (string, error) {
+	switch val := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return val, nil
+	case []byte:
+		return string(val), nil
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, json.Number:
+		return fmt.Sprintf("%v", val), nil
+	default:
+		marshalled, err := json.Marshal(val)
+		if err != nil {
+			return "", fmt.Errorf("could not convert value to string: %w", err)
+		}
+		return string(marshalled), nil
+	}
+}
+
+// lookupString returns the value of the given key as string, falling back to the default value if it is not found.
+func lookupString(data map[string]any, key, defaultValue string) (string, error) {
+	value, found := data[key]
+	if !found {
+		return defaultValue, nil
+	}
+
+	return stringify(value)
+}
+
+func writeLine(builder *strings.Builder, parts ...string) {
+	for _, part := range parts {
+		builder.WriteString(part)
+	}
+	builder.WriteByte('\n')
+}
@@ -3,25 +3,49 @@ package secret_replication
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	vault "github.com/hashicorp/vault/api"
 )
 
 type Kv2Client interface {
 	Get(ctx context.Context, path string) (*vault.KVSecret, error)
+	GetMetadata(ctx context.Context, path string) (*vault.KVMetadata, error)
+}
+
+type LogicalClient interface {
+	ReadWithContext(ctx context.Context, path string) (*vault.Secret, error)
+}
+
+type LeaseClient interface {
+	RenewWithContext(ctx context.Context, id string, increment int) (*vault.Secret, error)
+	RevokeWithContext(ctx context.Context, id string) error
 }
 
 type VaultKv2Client struct {
-	client Kv2Client
+	client  Kv2Client
+	logical LogicalClient
+	leases  LeaseClient
 }
 
-func NewClient(client Kv2Client) (*VaultKv2Client, error) {
+func NewClient(client Kv2Client, logical LogicalClient, leases LeaseClient) (*VaultKv2Client, error) {
 	if client == nil {
 		return nil, errors.New("empty client passed")
 	}
 
+	if logical == nil {
+		return nil, errors.New("empty logical client passed")
+	}
+
+	if leases == nil {
+		return nil, errors.New("empty lease client passed")
+	}
+
 	ret := &VaultKv2Client{
-		client: client,
+		client:  client,
+		logical: logical,
+		leases:  leases,
 	}
 
 	return ret, nil
@@ -30,3 +54,28 @@ func NewClient(client Kv2Client) (*VaultKv2Client, error) {
 func (s *VaultKv2Client) ReadSecret(ctx context.Context, path string) (*vault.KVSecret, error) {
 	return s.client.Get(ctx, path)
 }
+
+func (s *VaultKv2Client) ReadSecretMetadata(ctx context.Context, path string) (*vault.KVMetadata, error) {
+	return s.client.GetMetadata(ctx, path)
+}
+
+func (s *VaultKv2Client) Read(ctx context.Context, path string) (*vault.Secret, error) {
+	secret, err := s.logical.ReadWithContext(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	if secret == nil {
+		return nil, fmt.Errorf("no secret found at %q", path)
+	}
+
+	return secret, nil
+}
+
+func (s *VaultKv2Client) RenewLease(ctx context.Context, leaseId string, increment time.Duration) (*vault.Secret, error) {
+	return s.leases.RenewWithContext(ctx, leaseId, int(increment.Seconds()))
+}
+
+func (s *VaultKv2Client) RevokeLease(ctx context.Context, leaseId string) error {
+	return s.leases.RevokeWithContext(ctx, leaseId)
+}
@@ -15,3 +15,30 @@ func WithSyncInterval(interval time.Duration) func(syncer *Service) error {
 		return nil
 	}
 }
+
+func WithEventSubscriber(subscriber EventSubscriber) func(syncer *Service) error {
+	return func(s *Service) error {
+		if subscriber == nil {
+			return errors.New("empty event subscriber passed")
+		}
+
+		s.eventSubscriber = subscriber
+		return nil
+	}
+}
+
+// WithBackend registers the client of a named secret backend that items can be read from.
+func WithBackend(name string, client ReplicationClient) func(syncer *Service) error {
+	return func(s *Service) error {
+		if name == "" {
+			return errors.New("empty backend name passed")
+		}
+
+		if client == nil {
+			return errors.New("empty backend client passed")
+		}
+
+		s.backends[name] = client
+		return nil
+	}
+}
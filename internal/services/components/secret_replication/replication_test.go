@@ -0,0 +1,187 @@
+package secret_replication
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/soerenschneider/sc-agent/internal/domain/secret_replication"
+	"github.com/soerenschneider/sc-agent/internal/services/components/secret_replication/formatter"
+	"github.com/soerenschneider/sc-agent/internal/storage"
+	"github.com/soerenschneider/sc-agent/internal/vaulttest"
+)
+
+func TestService_ReplicateFromVault(t *testing.T) {
+	server := vaulttest.NewServer(t)
+	server.EnableKv2("secret")
+	server.PutKv2("secret", "prod/db", map[string]any{"password": "secret"})
+
+	client := server.Client()
+	kv2Client, err := NewClient(client.KVv2("secret"), client.Logical(), client.Sys())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dest := &storage.InMemory{}
+	item := secret_replication.ReplicationItem{
+		ReplicationConf: secret_replication.ReplicationConf{
+			Id:         "db",
+			SecretPath: "prod/db",
+			OnDelete:   secret_replication.OnDeleteTombstone,
+		},
+		Formatter:   &formatter.JsonFormatter{},
+		Destination: dest,
+	}
+
+	service, err := NewService(kv2Client, []secret_replication.ReplicationItem{item})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	countSecretReads := func() int {
+		count := 0
+		for _, req := range server.Requests() {
+			if req.Method == http.MethodGet && req.Path == "secret/data/prod/db" {
+				count++
+			}
+		}
+		return count
+	}
+
+	steps := []struct {
+		name            string
+		prepare         func()
+		wantUpdated     bool
+		wantSecretReads int
+		wantContent     string
+		wantStatus      secret_replication.SecretReplicationStatus
+	}{
+		{
+			name:            "initial replication",
+			wantUpdated:     true,
+			wantSecretReads: 1,
+			wantContent:     "secret",
+			wantStatus:      secret_replication.SynchronizedStatus,
+		},
+		{
+			name:            "unchanged version only reads metadata",
+			wantUpdated:     false,
+			wantSecretReads: 1,
+			wantContent:     "secret",
+			wantStatus:      secret_replication.SynchronizedStatus,
+		},
+		{
+			name: "new version",
+			prepare: func() {
+				server.PutKv2("secret", "prod/db", map[string]any{"password": "rotated"})
+			},
+			wantUpdated:     true,
+			wantSecretReads: 2,
+			wantContent:     "rotated",
+			wantStatus:      secret_replication.SynchronizedStatus,
+		},
+		{
+			name: "deleted secret is tombstoned",
+			prepare: func() {
+				server.DeleteKv2("secret", "prod/db")
+			},
+			wantUpdated:     true,
+			wantSecretReads: 3,
+			wantContent:     "",
+			wantStatus:      secret_replication.DeletedStatus,
+		},
+		{
+			name: "restored secret",
+			prepare: func() {
+				server.PutKv2("secret", "prod/db", map[string]any{"password": "restored"})
+			},
+			wantUpdated:     true,
+			wantSecretReads: 4,
+			wantContent:     "restored",
+			wantStatus:      secret_replication.SynchronizedStatus,
+		},
+	}
+
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			if step.prepare != nil {
+				step.prepare()
+			}
+
+			updated, err := service.Replicate(context.Background(), item)
+			if err != nil {
+				t.Fatalf("Replicate() error = %v", err)
+			}
+			if updated != step.wantUpdated {
+				t.Errorf("Replicate() updated = %v, want %v", updated, step.wantUpdated)
+			}
+			if got := countSecretReads(); got != step.wantSecretReads {
+				t.Errorf("Replicate() secret reads = %d, want %d", got, step.wantSecretReads)
+			}
+
+			if step.wantContent == "" && strings.TrimSpace(string(dest.Data)) != "" {
+				t.Errorf("Replicate() expected empty content, got %q", dest.Data)
+			}
+			if step.wantContent != "" && !strings.Contains(string(dest.Data), step.wantContent) {
+				t.Errorf("Replicate() content = %q, want it to contain %q", dest.Data, step.wantContent)
+			}
+
+			got, _ := service.GetReplicationItem(item.ReplicationConf.Id)
+			if got.Status != step.wantStatus {
+				t.Errorf("GetReplicationItem() status = %v, want %v", got.Status, step.wantStatus)
+			}
+		})
+	}
+}
+
+func TestService_ReplicateConcurrently(t *testing.T) {
+	server := vaulttest.NewServer(t)
+	server.EnableKv2("secret")
+	server.PutKv2("secret", "prod/db", map[string]any{"password": "secret"})
+	server.PutKv2("secret", "prod/api", map[string]any{"token": "token"})
+
+	client := server.Client()
+	kv2Client, err := NewClient(client.KVv2("secret"), client.Logical(), client.Sys())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var items []secret_replication.ReplicationItem
+	for _, id := range []string{"db", "api"} {
+		items = append(items, secret_replication.ReplicationItem{
+			ReplicationConf: secret_replication.ReplicationConf{Id: id, SecretPath: "prod/" + id},
+			Formatter:       &formatter.JsonFormatter{},
+			Destination:     &storage.InMemory{},
+		})
+	}
+
+	service, err := NewService(kv2Client, items)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// manually triggered replications run concurrently to the scheduled replication
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		for _, item := range items {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := service.Replicate(context.Background(), item); err != nil {
+					t.Errorf("Replicate() error = %v", err)
+				}
+			}()
+		}
+	}
+	service.syncAllSecrets(context.Background())
+	wg.Wait()
+
+	for _, item := range items {
+		got, _ := service.GetReplicationItem(item.ReplicationConf.Id)
+		if got.Status != secret_replication.SynchronizedStatus {
+			t.Errorf("GetReplicationItem(%q) status = %v", item.ReplicationConf.Id, got.Status)
+		}
+	}
+}
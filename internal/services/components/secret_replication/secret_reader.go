@@ -0,0 +1,109 @@
+package secret_replication
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	vault "github.com/hashicorp/vault/api"
+	"github.com/soerenschneider/sc-agent/internal/domain/secret_replication"
+	"github.com/soerenschneider/sc-agent/internal/metrics"
+)
+
+// trackingReader reads secrets for a single rendering of an item and keeps track of the versions of all KV v2
+// secrets that have been read, so subsequent replications can detect changes by only reading the metadata.
+type trackingReader struct {
+	ctx    context.Context
+	client ReplicationClient
+
+	secrets map[string]map[string]any
+	logical map[string]map[string]any
+	// versions holds the KV v2 versions of all read secrets, keyed by their path
+	versions map[string]int
+	// untracked is set if secrets have been read whose changes can not be detected by their metadata
+	untracked bool
+}
+
+func newTrackingReader(ctx context.Context, client ReplicationClient) *trackingReader {
+	return &trackingReader{
+		ctx:      ctx,
+		client:   client,
+		secrets:  map[string]map[string]any{},
+		logical:  map[string]map[string]any{},
+		versions: map[string]int{},
+	}
+}
+
+func (r *trackingReader) ReadKv2Secret(path string) (map[string]any, error) {
+	if data, found := r.secrets[path]; found {
+		return data, nil
+	}
+
+	read, err := r.client.ReadSecret(r.ctx, path)
+	if err != nil {
+		if errors.Is(err, vault.ErrSecretNotFound) {
+			return nil, fmt.Errorf("%w: %q", secret_replication.ErrSecretDeleted, path)
+		}
+		metrics.SecretReplicationErrors.WithLabelValues(path, getErrorLabel(err)).Inc()
+		return nil, err
+	}
+
+	if isDeleted(read) {
+		return nil, fmt.Errorf("%w: %q", secret_replication.ErrSecretDeleted, path)
+	}
+
+	if read.VersionMetadata != nil {
+		r.versions[path] = read.VersionMetadata.Version
+		metrics.SecretsVersion.WithLabelValues(path).Set(float64(read.VersionMetadata.Version))
+	} else {
+		r.untracked = true
+	}
+
+	r.secrets[path] = read.Data
+	return read.Data, nil
+}
+
+func (r *trackingReader) ReadSecret(path string) (map[string]any, error) {
+	r.untracked = true
+	if data, found := r.logical[path]; found {
+		return data, nil
+	}
+
+	read, err := r.client.Read(r.ctx, path)
+	if err != nil {
+		metrics.SecretReplicationErrors.WithLabelValues(path, getErrorLabel(err)).Inc()
+		return nil, fmt.Errorf("could not read %q: %w", path, err)
+	}
+	if read == nil {
+		return nil, fmt.Errorf("could not read %q: %w", path, vault.ErrSecretNotFound)
+	}
+
+	// every read of a dynamic secret issues new credentials whose lease would neither be renewed nor revoked
+	if read.LeaseID != "" {
+		if err := r.client.RevokeLease(r.ctx, read.LeaseID); err != nil {
+			metrics.SecretReplicationErrors.WithLabelValues(path, "lease_revocation").Inc()
+		}
+		return nil, fmt.Errorf("%w: %q", secret_replication.ErrLeasedSecretNotReadable, path)
+	}
+
+	r.logical[path] = read.Data
+	return read.Data, nil
+}
+
+// isDeleted returns whether the read version of a secret has been soft-deleted or destroyed. Vault returns the
+// version's metadata but no data in this case.
+func isDeleted(secret *vault.KVSecret) bool {
+	if secret.VersionMetadata == nil || secret.Data != nil {
+		return false
+	}
+
+	return secret.VersionMetadata.Destroyed || !secret.VersionMetadata.DeletionTime.IsZero()
+}
+
+func getErrorLabel(err error) string {
+	var respErr *vault.ResponseError
+	if errors.As(err, &respErr) {
+		return fmt.Sprintf("vault_%d", respErr.StatusCode)
+	}
+	return "vault_unknown"
+}
@@ -6,7 +6,9 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"maps"
 	"math/rand/v2"
+	"strconv"
 	"sync"
 	"time"
 
@@ -14,6 +16,8 @@ import (
 	"github.com/rs/zerolog/log"
 	"github.com/soerenschneider/sc-agent/internal/domain/secret_replication"
 	"github.com/soerenschneider/sc-agent/internal/metrics"
+	"github.com/soerenschneider/sc-agent/internal/storage"
+	"github.com/soerenschneider/sc-agent/pkg"
 	"go.uber.org/multierr"
 )
 
@@ -25,26 +29,71 @@ const (
 
 type ReplicationClient interface {
 	ReadSecret(ctx context.Context, path string) (*vault.KVSecret, error)
+	ReadSecretMetadata(ctx context.Context, path string) (*vault.KVMetadata, error)
+	Read(ctx context.Context, path string) (*vault.Secret, error)
+	RenewLease(ctx context.Context, leaseId string, increment time.Duration) (*vault.Secret, error)
+	RevokeLease(ctx context.Context, leaseId string) error
+}
+
+type EventSubscriber interface {
+	Subscribe(ctx context.Context, changedPaths chan<- string) error
 }
 
 type SecretsReplicationOpts func(syncer *Service) error
 
 type Service struct {
 	client ReplicationClient
+	// backends holds the clients of the named secret backends items can be read from instead of the default client
+	backends map[string]ReplicationClient
 
 	replicationItems    map[string]secret_replication.ReplicationItem
 	once                sync.Once
 	mutex               sync.Mutex
 	replicationInterval time.Duration
+	// dynamicSecretRetryInterval is the initial interval between failed attempts to replicate a dynamic secret, it
+	// is doubled after each failed attempt
+	dynamicSecretRetryInterval time.Duration
+	eventSubscriber            EventSubscriber
+
+	// cache holds the hash of the content that has been written for each item, keyed by the item's id
+	cache     map[string]string
+	cacheLock sync.Mutex
+
+	// itemLocks make sure scheduled, event-driven and manually triggered replications of an item never run at the same
+	// time
+	itemLocks     map[string]*sync.Mutex
+	itemLocksLock sync.Mutex
+
+	// replicated holds the secrets each item has been rendered from, keyed by the item's id
+	replicated     map[string]replicatedSecrets
+	replicatedLock sync.RWMutex
+
+	// metadataUnreadable holds the paths whose metadata can not be read, changes of these secrets are detected by
+	// reading their data instead
+	metadataUnreadable     map[string]bool
+	metadataUnreadableLock sync.Mutex
+}
 
-	cache map[string]string
+type replicatedSecrets struct {
+	// versions holds the KV v2 versions of all secrets the item has been rendered from, keyed by their path
+	versions map[string]int
+	// untracked is set if the item references secrets whose changes can not be detected using metadata
+	untracked bool
+	// leaseExpiry is the point in time the lease of a dynamic secret expires
+	leaseExpiry time.Time
+	// status is the status of the latest replication attempt
+	status secret_replication.SecretReplicationStatus
+	// contentHash is the hash of the content that has been written to the destination
+	contentHash string
+
+	lastAttempt time.Time
+	lastSuccess time.Time
+	lastError   string
 }
 
+// NewService returns a new service. The client may be nil if all items are read from named backends that are passed
+// using WithBackend.
 func NewService(client ReplicationClient, syncItems []secret_replication.ReplicationItem, opts ...SecretsReplicationOpts) (*Service, error) {
-	if client == nil {
-		return nil, errors.New("empty kv2client passed")
-	}
-
 	if syncItems == nil {
 		return nil, errors.New("no syncitems passed")
 	}
@@ -52,14 +101,24 @@ func NewService(client ReplicationClient, syncItems []secret_replication.Replica
 	// convert to map
 	syncItemsMap := map[string]secret_replication.ReplicationItem{}
 	for _, req := range syncItems {
+		if req.ReplicationConf.Dynamic {
+			if err := validateDynamicItem(req); err != nil {
+				return nil, err
+			}
+		}
 		syncItemsMap[req.ReplicationConf.Id] = req
 	}
 
 	ret := &Service{
-		client:              client,
-		replicationItems:    syncItemsMap,
-		cache:               map[string]string{},
-		replicationInterval: defaultTickerInterval,
+		client:                     client,
+		backends:                   map[string]ReplicationClient{},
+		replicationItems:           syncItemsMap,
+		cache:                      map[string]string{},
+		itemLocks:                  map[string]*sync.Mutex{},
+		replicated:                 map[string]replicatedSecrets{},
+		metadataUnreadable:         map[string]bool{},
+		replicationInterval:        defaultTickerInterval,
+		dynamicSecretRetryInterval: dynamicSecretRetryInterval,
 	}
 
 	var errs error
@@ -69,22 +128,50 @@ func NewService(client ReplicationClient, syncItems []secret_replication.Replica
 		}
 	}
 
+	for _, item := range syncItems {
+		client := ret.clientFor(item)
+		if client == nil {
+			errs = multierr.Append(errs, fmt.Errorf("no client for backend %q of item %q", item.ReplicationConf.Backend, item.ReplicationConf.Id))
+		} else if _, isBackend := client.(*BackendClient); isBackend && item.ReplicationConf.Dynamic {
+			errs = multierr.Append(errs, fmt.Errorf("backend %q of item %q does not support dynamic secrets", item.ReplicationConf.Backend, item.ReplicationConf.Id))
+		}
+	}
+
 	return ret, errs
 }
 
-func (s *Service) StartContinuousReplication(ctx context.Context) {
+// clientFor returns the client of the backend the item is read from, nil if the backend is unknown.
+func (s *Service) clientFor(item secret_replication.ReplicationItem) ReplicationClient {
+	if item.ReplicationConf.Backend == "" {
+		return s.client
+	}
+	return s.backends[item.ReplicationConf.Backend]
+}
+
+func (s *Service) StartContinuousReplication(ctx context.Context, wg *sync.WaitGroup) {
 	s.once.Do(func() {
 		if len(s.replicationItems) == 0 {
 			log.Warn().Str(logComponent, componentName).Msg("no items defined, not scheduling auto-renewals")
 			return
 		}
 
+		for _, item := range s.replicationItems {
+			if item.ReplicationConf.Dynamic {
+				wg.Add(1)
+				go s.manageDynamicSecret(ctx, wg, item)
+			}
+		}
+
 		log.Info().Str(logComponent, componentName).Msgf("start replication of %d secrets", len(s.replicationItems))
 		jitter := 5 * time.Minute
 		checkInterval := s.replicationInterval - (jitter / 2)
 		ticker := time.NewTicker(checkInterval)
 		s.syncAllSecrets(ctx)
 
+		if s.eventSubscriber != nil {
+			go s.handleVaultEvents(ctx)
+		}
+
 		for {
 			select {
 			case <-ctx.Done():
@@ -104,6 +191,10 @@ func (s *Service) syncAllSecrets(ctx context.Context) {
 
 	var errs error
 	for _, req := range s.replicationItems {
+		if req.ReplicationConf.Dynamic {
+			continue
+		}
+
 		select {
 		case <-ctx.Done():
 			return
@@ -119,16 +210,58 @@ func (s *Service) syncAllSecrets(ctx context.Context) {
 	}
 }
 
+func (s *Service) handleVaultEvents(ctx context.Context) {
+	changedPaths := make(chan string, 16)
+	go func() {
+		if err := s.eventSubscriber.Subscribe(ctx, changedPaths); err != nil {
+			log.Warn().Err(err).Str(logComponent, componentName).Msg("not receiving vault events, relying on periodic replication")
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case path := <-changedPaths:
+			s.syncSecretPath(ctx, path)
+		}
+	}
+}
+
+// syncSecretPath replicates all items that reference the given secret path.
+func (s *Service) syncSecretPath(ctx context.Context, path string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, req := range s.replicationItems {
+		// events are only received from the default client
+		if req.ReplicationConf.Dynamic || req.ReplicationConf.Backend != "" || !s.referencesPath(req, path) {
+			continue
+		}
+
+		log.Debug().Str(logComponent, componentName).Str("id", req.ReplicationConf.Id).Msg("received vault event for item")
+		if _, err := s.Replicate(ctx, req); err != nil {
+			log.Error().Err(err).Str(logComponent, componentName).Str("id", req.ReplicationConf.Id).Msg("could not replicate item after vault event")
+		}
+	}
+}
+
 func (s *Service) GetReplicationItem(id string) (secret_replication.ReplicationItem, error) {
 	item, found := s.replicationItems[id]
 	if !found {
 		return secret_replication.ReplicationItem{}, secret_replication.ErrSecretsReplicationItemNotFound
 	}
 
-	item.Status = secret_replication.FailedStatus
-	if found {
-		item.Status = secret_replication.SynchronizedStatus
-	}
+	s.replicatedLock.RLock()
+	item.Status = s.replicated[id].status
+	item.ContentHash = s.replicated[id].contentHash
+	item.LastAttempt = s.replicated[id].lastAttempt
+	item.LastSuccess = s.replicated[id].lastSuccess
+	item.LastError = s.replicated[id].lastError
+	item.ReplicatedVersion = s.replicated[id].versions[item.ReplicationConf.SecretPath]
+	item.ReferencedSecrets = maps.Clone(s.replicated[id].versions)
+	item.LeaseExpiry = s.replicated[id].leaseExpiry
+	s.replicatedLock.RUnlock()
 
 	return item, nil
 }
@@ -146,30 +279,177 @@ func (s *Service) GetReplicationItems() []secret_replication.ReplicationItem {
 }
 
 func (s *Service) Replicate(ctx context.Context, item secret_replication.ReplicationItem) (bool, error) {
-	read, err := s.client.ReadSecret(ctx, item.ReplicationConf.SecretPath)
+	if item.ReplicationConf.Dynamic {
+		return false, secret_replication.ErrDynamicSecretNotReplicable
+	}
+
+	lock := s.getItemLock(item.ReplicationConf.Id)
+	lock.Lock()
+	defer lock.Unlock()
+
+	updated, err := s.replicate(ctx, item)
+	if errors.Is(err, secret_replication.ErrSecretDeleted) {
+		return s.handleDeletedSecret(ctx, item, err)
+	}
+
 	if err != nil {
-		errorLabel := "vault_unknown"
-		var respErr *vault.ResponseError
-		if errors.As(err, &respErr) {
-			errorLabel = fmt.Sprintf("vault_%d", respErr.StatusCode)
+		s.recordResult(item, secret_replication.FailedStatus, err)
+	} else {
+		s.recordResult(item, secret_replication.SynchronizedStatus, nil)
+	}
+
+	return updated, err
+}
+
+func (s *Service) getItemLock(id string) *sync.Mutex {
+	s.itemLocksLock.Lock()
+	defer s.itemLocksLock.Unlock()
+
+	lock, found := s.itemLocks[id]
+	if !found {
+		lock = &sync.Mutex{}
+		s.itemLocks[id] = lock
+	}
+	return lock
+}
+
+func (s *Service) replicate(ctx context.Context, item secret_replication.ReplicationItem) (bool, error) {
+	if s.isUpToDate(ctx, item) {
+		metrics.SecretsCacheHit.WithLabelValues(item.ReplicationConf.SecretPath).Inc()
+		return false, nil
+	}
+
+	reader := newTrackingReader(ctx, s.clientFor(item))
+	data := map[string]any{}
+	if len(item.ReplicationConf.SecretPath) > 0 {
+		var err error
+		data, err = reader.ReadKv2Secret(item.ReplicationConf.SecretPath)
+		if err != nil {
+			return false, err
 		}
-		metrics.SecretReplicationErrors.WithLabelValues(item.ReplicationConf.SecretPath, errorLabel).Inc()
+	}
+
+	formatted, err := s.format(item, data, reader)
+	if err != nil {
 		return false, err
 	}
 
-	formatted, err := item.Formatter.Format(read.Data)
+	updated, err := s.updateFile(formatted, item)
 	if err != nil {
-		metrics.SecretReplicationErrors.WithLabelValues(item.ReplicationConf.SecretPath, "formatter").Inc()
 		return false, err
 	}
 
-	return true, s.updateFile(formatted, item)
+	s.replicatedLock.Lock()
+	replicated := s.replicated[item.ReplicationConf.Id]
+	replicated.versions = reader.versions
+	replicated.untracked = reader.untracked
+	replicated.contentHash = hashContent(formatted)
+	s.replicated[item.ReplicationConf.Id] = replicated
+	s.replicatedLock.Unlock()
+
+	if updated {
+		if err := pkg.RunPostIssueHooks(item.PostHooks); err != nil {
+			metrics.SecretReplicationErrors.WithLabelValues(item.ReplicationConf.SecretPath, "post_hooks").Inc()
+			s.rollback(ctx, item, err)
+			return true, err
+		}
+	}
+
+	return updated, nil
+}
+
+// rollback restores the previous content of the item's destination after its post hooks failed.
+func (s *Service) rollback(ctx context.Context, item secret_replication.ReplicationItem, reason error) {
+	if err := storage.Rollback(ctx, componentName, item.ReplicationConf.Id, reason, item.Destination); err != nil {
+		metrics.SecretReplicationErrors.WithLabelValues(item.ReplicationConf.SecretPath, "rollback").Inc()
+	}
+}
+
+func (s *Service) format(item secret_replication.ReplicationItem, data map[string]any, reader secret_replication.SecretReader) ([]byte, error) {
+	var formatted []byte
+	var err error
+	if formatter, ok := item.Formatter.(secret_replication.MultiSecretFormatter); ok {
+		formatted, err = formatter.FormatSecrets(data, reader)
+	} else {
+		formatted, err = item.Formatter.Format(data)
+	}
+	if err != nil {
+		metrics.SecretReplicationErrors.WithLabelValues(item.ReplicationConf.SecretPath, "formatter").Inc()
+		return nil, err
+	}
+
+	return formatted, nil
+}
+
+// isUpToDate checks whether the versions of all secrets an item has been rendered from match their current versions
+// and whether the local file has not been altered since it has been written.
+func (s *Service) isUpToDate(ctx context.Context, item secret_replication.ReplicationItem) bool {
+	s.replicatedLock.RLock()
+	replicated, found := s.replicated[item.ReplicationConf.Id]
+	s.replicatedLock.RUnlock()
+	if !found || replicated.untracked || replicated.status == secret_replication.DeletedStatus {
+		return false
+	}
+
+	for path, replicatedVersion := range replicated.versions {
+		if s.isMetadataUnreadable(path) {
+			return false
+		}
+
+		metadata, err := s.clientFor(item).ReadSecretMetadata(ctx, path)
+		if err != nil {
+			// reading metadata requires a dedicated capability that not every policy grants, fall back to reading the data
+			s.setMetadataUnreadable(path)
+			log.Warn().Err(err).Str(logComponent, componentName).Str("id", item.ReplicationConf.Id).Str("path", path).Msg("could not read secret metadata, detecting changes by reading the secret from now on")
+			return false
+		}
+
+		if replicatedVersion != metadata.CurrentVersion {
+			log.Debug().Str(logComponent, componentName).Str("id", item.ReplicationConf.Id).Str("path", path).Int("replicated_version", replicatedVersion).Int("current_version", metadata.CurrentVersion).Time("updated_time", metadata.UpdatedTime).Msg("secret version differs")
+			return false
+		}
+
+		// deleting a secret does not create a new version, the deletion is only visible in the version's metadata
+		if version, found := metadata.Versions[strconv.Itoa(metadata.CurrentVersion)]; found && (version.Destroyed || !version.DeletionTime.IsZero()) {
+			log.Debug().Str(logComponent, componentName).Str("id", item.ReplicationConf.Id).Str("path", path).Int("current_version", metadata.CurrentVersion).Msg("current version of secret has been deleted")
+			return false
+		}
+	}
+
+	cachedHash, found := s.getCachedHash(item.ReplicationConf.Id)
+	if !found {
+		return false
+	}
+
+	diskContent, err := item.Destination.Read()
+	if err != nil {
+		return false
+	}
+
+	if !storage.ContentMatches(diskContent, cachedHash) {
+		log.Info().Str(logComponent, componentName).Str("id", item.ReplicationConf.Id).Msg("noticed file has changed on disk")
+		return false
+	}
+
+	return true
 }
 
-func (s *Service) updateFile(data []byte, conf secret_replication.ReplicationItem) error {
+// referencesPath returns whether an item has been rendered using the secret at the given path.
+func (s *Service) referencesPath(item secret_replication.ReplicationItem, path string) bool {
+	if item.ReplicationConf.SecretPath == path {
+		return true
+	}
+
+	s.replicatedLock.RLock()
+	defer s.replicatedLock.RUnlock()
+	_, found := s.replicated[item.ReplicationConf.Id].versions[path]
+	return found
+}
+
+func (s *Service) updateFile(data []byte, conf secret_replication.ReplicationItem) (bool, error) {
 	hash := hashContent(data)
 
-	oldHash, itemAlreadyCached := s.cache[conf.ReplicationConf.SecretPath]
+	oldHash, itemAlreadyCached := s.getCachedHash(conf.ReplicationConf.Id)
 	log.Debug().Str(logComponent, componentName).Str("hash", hash).Str("oldHash", oldHash).Bool("item_in_cache", itemAlreadyCached).Msg("Cache check #1")
 	if itemAlreadyCached && oldHash == hash {
 		// item is already downloaded. let's check if the item on disk has been changed by a 3rd party since our last check.
@@ -177,9 +457,9 @@ func (s *Service) updateFile(data []byte, conf secret_replication.ReplicationIte
 		if err == nil {
 			diskHash := hashContent(diskContent)
 			log.Debug().Str(logComponent, componentName).Str("hash", hash).Str("diskHash", diskHash).Msg("Cache check #2")
-			if diskHash == hash {
+			if storage.ContentMatches(diskContent, hash) {
 				// file exists locally and is identical to the item we downloaded, we're done
-				return nil
+				return false, nil
 			}
 			log.Info().Str(logComponent, componentName).Str("id", conf.ReplicationConf.Id).Msg("noticed file has changed on disk, proceeding to overwrite")
 		} else {
@@ -187,26 +467,57 @@ func (s *Service) updateFile(data []byte, conf secret_replication.ReplicationIte
 		}
 	}
 
-	s.cache[conf.ReplicationConf.SecretPath] = hash
+	s.setCachedHash(conf.ReplicationConf.Id, hash)
 
 	if !itemAlreadyCached {
 		read, err := conf.Destination.Read()
-		if err == nil && hash == hashContent(read) {
+		if err == nil && storage.ContentMatches(read, hash) {
 			log.Debug().Str(logComponent, componentName).Str("id", conf.ReplicationConf.Id).Msg("file already exists locally")
-			return nil
+			return false, nil
 		}
 	}
 
 	log.Info().Str(logComponent, componentName).Str("id", conf.ReplicationConf.Id).Msg("writing item to disk")
 	if err := conf.Destination.Write(data); err != nil {
 		metrics.SecretReplicationErrors.WithLabelValues(conf.ReplicationConf.Id, "write_file").Inc()
-		return err
+		return false, err
 	}
 
 	metrics.SecretsRead.WithLabelValues(conf.ReplicationConf.SecretPath).Inc()
 	log.Info().Str(logComponent, componentName).Str("secret_path", conf.ReplicationConf.SecretPath).Str("dest", conf.ReplicationConf.DestUri).Msg("successfully synced secret")
 
-	return nil
+	return true, nil
+}
+
+func (s *Service) getCachedHash(id string) (string, bool) {
+	s.cacheLock.Lock()
+	defer s.cacheLock.Unlock()
+	hash, found := s.cache[id]
+	return hash, found
+}
+
+func (s *Service) isMetadataUnreadable(path string) bool {
+	s.metadataUnreadableLock.Lock()
+	defer s.metadataUnreadableLock.Unlock()
+	return s.metadataUnreadable[path]
+}
+
+func (s *Service) setMetadataUnreadable(path string) {
+	s.metadataUnreadableLock.Lock()
+	defer s.metadataUnreadableLock.Unlock()
+	s.metadataUnreadable[path] = true
+}
+
+func (s *Service) setCachedHash(id, hash string) {
+	s.cacheLock.Lock()
+	defer s.cacheLock.Unlock()
+	s.cache[id] = hash
+}
+
+func (s *Service) deleteCachedHash(id string) {
+	s.cacheLock.Lock()
+	defer s.cacheLock.Unlock()
+	delete(s.cache, id)
 }
 
 func hashContent(data []byte) string {
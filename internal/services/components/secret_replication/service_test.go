@@ -0,0 +1,419 @@
+package secret_replication
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	vault "github.com/hashicorp/vault/api"
+	"github.com/soerenschneider/sc-agent/internal/domain"
+	"github.com/soerenschneider/sc-agent/internal/domain/secret_replication"
+	"github.com/soerenschneider/sc-agent/internal/services/components/secret_replication/formatter"
+	"github.com/soerenschneider/sc-agent/internal/storage"
+)
+
+type fakeSecret struct {
+	version int
+	data    map[string]any
+	deleted bool
+}
+
+type fakeClient struct {
+	secrets     map[string]*fakeSecret
+	secretReads int
+	// metadataErr is returned when reading metadata, e.g. if the policy lacks the capability
+	metadataErr   error
+	metadataReads int
+}
+
+func (f *fakeClient) ReadSecret(_ context.Context, path string) (*vault.KVSecret, error) {
+	f.secretReads++
+	secret, found := f.secrets[path]
+	if !found {
+		return nil, errors.New("not found")
+	}
+	if secret.deleted {
+		return &vault.KVSecret{
+			VersionMetadata: &vault.KVVersionMetadata{Version: secret.version, DeletionTime: time.Now()},
+		}, nil
+	}
+	return &vault.KVSecret{
+		Data:            secret.data,
+		VersionMetadata: &vault.KVVersionMetadata{Version: secret.version},
+	}, nil
+}
+
+func (f *fakeClient) ReadSecretMetadata(_ context.Context, path string) (*vault.KVMetadata, error) {
+	f.metadataReads++
+	if f.metadataErr != nil {
+		return nil, f.metadataErr
+	}
+	secret, found := f.secrets[path]
+	if !found {
+		return nil, errors.New("not found")
+	}
+	metadata := &vault.KVMetadata{CurrentVersion: secret.version}
+	if secret.deleted {
+		metadata.Versions = map[string]vault.KVVersionMetadata{
+			strconv.Itoa(secret.version): {Version: secret.version, DeletionTime: time.Now()},
+		}
+	}
+	return metadata, nil
+}
+
+func (f *fakeClient) Read(_ context.Context, path string) (*vault.Secret, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakeClient) RenewLease(_ context.Context, _ string, _ time.Duration) (*vault.Secret, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakeClient) RevokeLease(_ context.Context, _ string) error {
+	return errors.New("not implemented")
+}
+
+func TestService_Replicate(t *testing.T) {
+	client := &fakeClient{secrets: map[string]*fakeSecret{
+		"prod/db": {version: 1, data: map[string]any{"password": "secret"}},
+	}}
+	dest := &storage.InMemory{}
+	item := secret_replication.ReplicationItem{
+		ReplicationConf: secret_replication.ReplicationConf{
+			Id:         "db",
+			SecretPath: "prod/db",
+		},
+		Formatter:   &formatter.JsonFormatter{},
+		Destination: dest,
+	}
+
+	service, err := NewService(client, []secret_replication.ReplicationItem{item})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		name            string
+		prepare         func()
+		wantUpdated     bool
+		wantSecretReads int
+		wantVersion     int
+	}{
+		{
+			name:            "initial replication",
+			wantUpdated:     true,
+			wantSecretReads: 1,
+			wantVersion:     1,
+		},
+		{
+			name:            "unchanged version does not read secret",
+			wantUpdated:     false,
+			wantSecretReads: 1,
+			wantVersion:     1,
+		},
+		{
+			name: "file changed on disk",
+			prepare: func() {
+				dest.Data = []byte("tampered\n")
+			},
+			wantUpdated:     true,
+			wantSecretReads: 2,
+			wantVersion:     1,
+		},
+		{
+			name: "new version",
+			prepare: func() {
+				client.secrets["prod/db"] = &fakeSecret{version: 2, data: map[string]any{"password": "rotated"}}
+			},
+			wantUpdated:     true,
+			wantSecretReads: 3,
+			wantVersion:     2,
+		},
+	}
+
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			if step.prepare != nil {
+				step.prepare()
+			}
+
+			updated, err := service.Replicate(context.Background(), item)
+			if err != nil {
+				t.Fatalf("Replicate() error = %v", err)
+			}
+			if updated != step.wantUpdated {
+				t.Errorf("Replicate() updated = %v, want %v", updated, step.wantUpdated)
+			}
+			if client.secretReads != step.wantSecretReads {
+				t.Errorf("Replicate() secretReads = %d, want %d", client.secretReads, step.wantSecretReads)
+			}
+
+			got, _ := service.GetReplicationItem(item.ReplicationConf.Id)
+			if got.ReplicatedVersion != step.wantVersion {
+				t.Errorf("GetReplicationItem() version = %d, want %d", got.ReplicatedVersion, step.wantVersion)
+			}
+		})
+	}
+}
+
+func TestService_ReplicateWithoutMetadataCapability(t *testing.T) {
+	client := &fakeClient{
+		secrets: map[string]*fakeSecret{
+			"prod/db": {version: 1, data: map[string]any{"password": "secret"}},
+		},
+		metadataErr: errors.New("permission denied"),
+	}
+	dest := &storage.InMemory{}
+	item := secret_replication.ReplicationItem{
+		ReplicationConf: secret_replication.ReplicationConf{
+			Id:         "db",
+			SecretPath: "prod/db",
+		},
+		Formatter:   &formatter.JsonFormatter{},
+		Destination: dest,
+	}
+
+	service, err := NewService(client, []secret_replication.ReplicationItem{item})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := service.Replicate(context.Background(), item); err != nil {
+			t.Fatalf("Replicate() error = %v", err)
+		}
+	}
+
+	if client.secretReads != 3 {
+		t.Errorf("Replicate() secretReads = %d, want 3", client.secretReads)
+	}
+	if client.metadataReads != 1 {
+		t.Errorf("Replicate() metadataReads = %d, want metadata to be read only once", client.metadataReads)
+	}
+
+	// changes must still be detected by reading the secret
+	client.secrets["prod/db"] = &fakeSecret{version: 2, data: map[string]any{"password": "rotated"}}
+	if updated, err := service.Replicate(context.Background(), item); !updated || err != nil {
+		t.Errorf("Replicate() updated = %v, err = %v, want update", updated, err)
+	}
+}
+
+func TestService_ReplicateMultiSecretTemplate(t *testing.T) {
+	client := &fakeClient{secrets: map[string]*fakeSecret{
+		"prod/db":  {version: 1, data: map[string]any{"password": "secret"}},
+		"prod/api": {version: 4, data: map[string]any{"key": "abc"}},
+	}}
+
+	templateFile := filepath.Join(t.TempDir(), "app.conf.tmpl")
+	content := `db={{ (secret "prod/db").password }} api={{ (secret "prod/api").key }}`
+	if err := os.WriteFile(templateFile, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	templateFormatter, err := formatter.NewTemplateFormatter(templateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dest := &storage.InMemory{}
+	item := secret_replication.ReplicationItem{
+		ReplicationConf: secret_replication.ReplicationConf{Id: "app"},
+		Formatter:       templateFormatter,
+		Destination:     dest,
+	}
+
+	service, err := NewService(client, []secret_replication.ReplicationItem{item})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := service.Replicate(context.Background(), item); err != nil {
+		t.Fatalf("Replicate() error = %v", err)
+	}
+	if got := string(dest.Data); got != "db=secret api=abc\n" {
+		t.Errorf("Replicate() wrote %q", got)
+	}
+
+	got, _ := service.GetReplicationItem("app")
+	wantReferenced := map[string]int{"prod/db": 1, "prod/api": 4}
+	if !reflect.DeepEqual(got.ReferencedSecrets, wantReferenced) {
+		t.Errorf("GetReplicationItem() referenced = %v, want %v", got.ReferencedSecrets, wantReferenced)
+	}
+
+	// unchanged referenced secrets must not lead to reading secrets
+	reads := client.secretReads
+	if updated, _ := service.Replicate(context.Background(), item); updated || client.secretReads != reads {
+		t.Errorf("Replicate() updated = %v, secretReads = %d, want no update and %d reads", updated, client.secretReads, reads)
+	}
+
+	// a change of any referenced secret must trigger rendering the template again
+	client.secrets["prod/api"] = &fakeSecret{version: 5, data: map[string]any{"key": "def"}}
+	if updated, err := service.Replicate(context.Background(), item); !updated || err != nil {
+		t.Errorf("Replicate() updated = %v, err = %v, want update", updated, err)
+	}
+	if got := string(dest.Data); got != "db=secret api=def\n" {
+		t.Errorf("Replicate() wrote %q", got)
+	}
+}
+
+func TestService_ReplicatePostHooks(t *testing.T) {
+	client := &fakeClient{secrets: map[string]*fakeSecret{
+		"prod/db": {version: 1, data: map[string]any{"password": "secret"}},
+	}}
+
+	marker := filepath.Join(t.TempDir(), "hook-ran")
+	item := secret_replication.ReplicationItem{
+		ReplicationConf: secret_replication.ReplicationConf{
+			Id:         "db",
+			SecretPath: "prod/db",
+		},
+		Formatter:   &formatter.JsonFormatter{},
+		Destination: &storage.InMemory{},
+		PostHooks: []domain.PostHook{
+			{Name: "marker", Cmd: "touch " + marker},
+		},
+	}
+
+	service, err := NewService(client, []secret_replication.ReplicationItem{item})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		name        string
+		prepare     func()
+		wantHookRan bool
+	}{
+		{
+			name:        "content written",
+			wantHookRan: true,
+		},
+		{
+			name:        "content unchanged",
+			wantHookRan: false,
+		},
+		{
+			name: "new version with identical content",
+			prepare: func() {
+				client.secrets["prod/db"].version = 2
+			},
+			wantHookRan: false,
+		},
+		{
+			name: "content changed",
+			prepare: func() {
+				client.secrets["prod/db"] = &fakeSecret{version: 3, data: map[string]any{"password": "rotated"}}
+			},
+			wantHookRan: true,
+		},
+	}
+
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			_ = os.Remove(marker)
+			if step.prepare != nil {
+				step.prepare()
+			}
+
+			if _, err := service.Replicate(context.Background(), item); err != nil {
+				t.Fatalf("Replicate() error = %v", err)
+			}
+
+			_, err := os.Stat(marker)
+			if hookRan := err == nil; hookRan != step.wantHookRan {
+				t.Errorf("Replicate() hook ran = %v, want %v", hookRan, step.wantHookRan)
+			}
+		})
+	}
+}
+
+func TestService_GetReplicationItemStatus(t *testing.T) {
+	client := &fakeClient{secrets: map[string]*fakeSecret{}}
+	item := secret_replication.ReplicationItem{
+		ReplicationConf: secret_replication.ReplicationConf{
+			Id:         "db",
+			SecretPath: "prod/db",
+		},
+		Formatter:   &formatter.JsonFormatter{},
+		Destination: &storage.InMemory{},
+	}
+
+	service, err := NewService(client, []secret_replication.ReplicationItem{item})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, _ := service.GetReplicationItem("db")
+	if got.Status != secret_replication.UnknownStatus {
+		t.Errorf("Status = %v, want %v", got.Status, secret_replication.UnknownStatus)
+	}
+
+	if _, err := service.Replicate(context.Background(), item); err == nil {
+		t.Fatal("Replicate() expected error for missing secret")
+	}
+	got, _ = service.GetReplicationItem("db")
+	if got.Status != secret_replication.FailedStatus || got.LastError == "" || got.LastAttempt.IsZero() || !got.LastSuccess.IsZero() {
+		t.Errorf("unexpected state after failed attempt: %+v", got)
+	}
+
+	client.secrets["prod/db"] = &fakeSecret{version: 1, data: map[string]any{"password": "secret"}}
+	if _, err := service.Replicate(context.Background(), item); err != nil {
+		t.Fatalf("Replicate() unexpected error: %v", err)
+	}
+	got, _ = service.GetReplicationItem("db")
+	if got.Status != secret_replication.SynchronizedStatus || got.LastError != "" || got.LastSuccess.IsZero() || got.ContentHash == "" {
+		t.Errorf("unexpected state after successful attempt: %+v", got)
+	}
+}
+
+type restorableStorage struct {
+	storage.InMemory
+	previous []byte
+}
+
+func (r *restorableStorage) Write(data []byte) error {
+	r.previous = r.Data
+	return r.InMemory.Write(data)
+}
+
+func (r *restorableStorage) Rollback() error {
+	r.Data = r.previous
+	return nil
+}
+
+func TestService_ReplicateRollbackOnFailedPostHooks(t *testing.T) {
+	client := &fakeClient{secrets: map[string]*fakeSecret{
+		"prod/db": {version: 1, data: map[string]any{"password": "secret"}},
+	}}
+
+	dest := &restorableStorage{InMemory: storage.InMemory{Data: []byte("previous\n")}}
+	item := secret_replication.ReplicationItem{
+		ReplicationConf: secret_replication.ReplicationConf{
+			Id:         "db",
+			SecretPath: "prod/db",
+		},
+		Formatter:   &formatter.JsonFormatter{},
+		Destination: dest,
+		PostHooks: []domain.PostHook{
+			{Name: "reject", Cmd: "false"},
+		},
+	}
+
+	service, err := NewService(client, []secret_replication.ReplicationItem{item})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := service.Replicate(context.Background(), item); err == nil {
+		t.Fatal("Replicate() expected error of failing post hook")
+	}
+
+	if string(dest.Data) != "previous\n" {
+		t.Errorf("expected previous content to be restored, got %q", dest.Data)
+	}
+}
@@ -0,0 +1,37 @@
+package secret_replication
+
+import (
+	"time"
+
+	"github.com/soerenschneider/sc-agent/internal/domain/secret_replication"
+	"github.com/soerenschneider/sc-agent/internal/metrics"
+)
+
+// recordResult stores the outcome of a replication attempt of an item.
+func (s *Service) recordResult(item secret_replication.ReplicationItem, status secret_replication.SecretReplicationStatus, err error) {
+	metrics.SecretsStatus.WithLabelValues(item.ReplicationConf.Id).Set(float64(status))
+
+	now := time.Now()
+	s.replicatedLock.Lock()
+	defer s.replicatedLock.Unlock()
+
+	replicated := s.replicated[item.ReplicationConf.Id]
+	replicated.status = status
+	replicated.lastAttempt = now
+	if err != nil {
+		replicated.lastError = err.Error()
+	} else {
+		replicated.lastError = ""
+		replicated.lastSuccess = now
+	}
+	s.replicated[item.ReplicationConf.Id] = replicated
+}
+
+func (s *Service) setContentHash(item secret_replication.ReplicationItem, content []byte) {
+	s.replicatedLock.Lock()
+	defer s.replicatedLock.Unlock()
+
+	replicated := s.replicated[item.ReplicationConf.Id]
+	replicated.contentHash = hashContent(content)
+	s.replicated[item.ReplicationConf.Id] = replicated
+}
@@ -0,0 +1,203 @@
+package secret_replication
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/gorilla/websocket"
+	vault "github.com/hashicorp/vault/api"
+	"github.com/rs/zerolog/log"
+	"github.com/soerenschneider/sc-agent/internal/metrics"
+)
+
+const (
+	vaultEventsSubscribePath = "/v1/sys/events/subscribe/kv-v2/data-*"
+)
+
+var ErrVaultEventsUnsupported = errors.New("vault does not support event notifications")
+
+// VaultEventSubscriber subscribes to Vault's event notification websocket and emits the secret paths of KV v2 secrets
+// that have been written, patched, deleted or undeleted.
+type VaultEventSubscriber struct {
+	client *vault.Client
+	mount  string
+}
+
+func NewVaultEventSubscriber(client *vault.Client, kv2Mount string) (*VaultEventSubscriber, error) {
+	if client == nil {
+		return nil, errors.New("empty client passed")
+	}
+
+	if len(kv2Mount) == 0 {
+		return nil, errors.New("empty kv2 mount passed")
+	}
+
+	return &VaultEventSubscriber{
+		client: client,
+		mount:  strings.Trim(kv2Mount, "/"),
+	}, nil
+}
+
+// Subscribe connects to Vault's event websocket and writes the secret paths of changed secrets to the given channel.
+// It reconnects on errors and only returns if the context is canceled or Vault does not support events.
+func (v *VaultEventSubscriber) Subscribe(ctx context.Context, changedPaths chan<- string) error {
+	backoffImpl := backoff.NewExponentialBackOff()
+	backoffImpl.InitialInterval = 5 * time.Second
+	backoffImpl.MaxInterval = 5 * time.Minute
+	backoffImpl.MaxElapsedTime = 0
+
+	op := func() error {
+		err := v.subscribe(ctx, changedPaths)
+		if errors.Is(err, ErrVaultEventsUnsupported) || ctx.Err() != nil {
+			return backoff.Permanent(err)
+		}
+
+		metrics.SecretReplicationErrors.WithLabelValues("", "vault_events").Inc()
+		log.Warn().Err(err).Str(logComponent, componentName).Msg("vault event subscription failed, reconnecting")
+		return err
+	}
+
+	err := backoff.Retry(op, backoff.WithContext(backoffImpl, ctx))
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+func (v *VaultEventSubscriber) subscribe(ctx context.Context, changedPaths chan<- string) error {
+	endpoint, err := v.getEndpoint()
+	if err != nil {
+		return err
+	}
+
+	headers := http.Header{}
+	headers.Set("X-Vault-Token", v.client.Token())
+	if ns := v.client.Namespace(); ns != "" {
+		headers.Set("X-Vault-Namespace", ns)
+	}
+
+	dialer := websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: 30 * time.Second,
+		TLSClientConfig:  v.getTlsConfig(),
+	}
+
+	conn, resp, err := dialer.DialContext(ctx, endpoint, headers)
+	if resp != nil && resp.Body != nil {
+		_ = resp.Body.Close()
+	}
+	if err != nil {
+		if resp != nil && (resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusMethodNotAllowed) {
+			return ErrVaultEventsUnsupported
+		}
+		return fmt.Errorf("could not connect to vault events endpoint: %w", err)
+	}
+
+	// unblock ReadMessage when the context is canceled
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.Close()
+	})
+	defer func() {
+		stop()
+		_ = conn.Close()
+	}()
+
+	log.Info().Str(logComponent, componentName).Str("mount", v.mount).Msg("subscribed to vault kv-v2 events")
+	for {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+
+		path, ok := v.parseEvent(msg)
+		if !ok {
+			continue
+		}
+
+		metrics.SecretsEventsReceived.WithLabelValues(path).Inc()
+		select {
+		case changedPaths <- path:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (v *VaultEventSubscriber) getEndpoint() (string, error) {
+	parsed, err := url.Parse(v.client.Address())
+	if err != nil {
+		return "", err
+	}
+
+	switch parsed.Scheme {
+	case "https":
+		parsed.Scheme = "wss"
+	case "http":
+		parsed.Scheme = "ws"
+	default:
+		return "", fmt.Errorf("unsupported scheme %q", parsed.Scheme)
+	}
+
+	parsed.Path = strings.TrimSuffix(parsed.Path, "/") + vaultEventsSubscribePath
+	parsed.RawQuery = "json=true"
+	return parsed.String(), nil
+}
+
+func (v *VaultEventSubscriber) getTlsConfig() *tls.Config {
+	conf := v.client.CloneConfig()
+	if conf == nil || conf.HttpClient == nil {
+		return nil
+	}
+
+	transport, ok := conf.HttpClient.Transport.(*http.Transport)
+	if !ok || transport.TLSClientConfig == nil {
+		return nil
+	}
+
+	return transport.TLSClientConfig.Clone()
+}
+
+type vaultEvent struct {
+	Data struct {
+		Event struct {
+			Metadata struct {
+				Path string `json:"path"`
+			} `json:"metadata"`
+		} `json:"event"`
+		PluginInfo struct {
+			MountPath string `json:"mount_path"`
+		} `json:"plugin_info"`
+	} `json:"data"`
+}
+
+// parseEvent extracts the secret path relative to the kv2 mount from a Vault event, e.g. "secret/data/prod/db" is
+// translated to "prod/db".
+func (v *VaultEventSubscriber) parseEvent(msg []byte) (string, bool) {
+	var event vaultEvent
+	if err := json.Unmarshal(msg, &event); err != nil {
+		log.Warn().Err(err).Str(logComponent, componentName).Msg("could not parse vault event")
+		return "", false
+	}
+
+	mount := strings.Trim(event.Data.PluginInfo.MountPath, "/")
+	if mount != "" && mount != v.mount {
+		return "", false
+	}
+
+	path := strings.TrimPrefix(event.Data.Event.Metadata.Path, v.mount+"/")
+	for _, prefix := range []string{"data/", "metadata/", "delete/", "undelete/", "destroy/"} {
+		if strings.HasPrefix(path, prefix) {
+			return strings.TrimPrefix(path, prefix), true
+		}
+	}
+
+	return "", false
+}
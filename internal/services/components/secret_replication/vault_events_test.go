@@ -0,0 +1,47 @@
+package secret_replication
+
+import "testing"
+
+func TestVaultEventSubscriber_parseEvent(t *testing.T) {
+	tests := []struct {
+		name   string
+		msg    string
+		want   string
+		wantOk bool
+	}{
+		{
+			name:   "data write",
+			msg:    `{"data":{"event":{"metadata":{"path":"secret/data/prod/db"}},"plugin_info":{"mount_path":"secret/"}}}`,
+			want:   "prod/db",
+			wantOk: true,
+		},
+		{
+			name:   "metadata event",
+			msg:    `{"data":{"event":{"metadata":{"path":"secret/metadata/prod/db"}},"plugin_info":{"mount_path":"secret/"}}}`,
+			want:   "prod/db",
+			wantOk: true,
+		},
+		{
+			name:   "different mount",
+			msg:    `{"data":{"event":{"metadata":{"path":"other/data/prod/db"}},"plugin_info":{"mount_path":"other/"}}}`,
+			wantOk: false,
+		},
+		{
+			name:   "invalid json",
+			msg:    `{"data":`,
+			wantOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &VaultEventSubscriber{mount: "secret"}
+			got, ok := v.parseEvent([]byte(tt.msg))
+			if ok != tt.wantOk {
+				t.Errorf("parseEvent() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if got != tt.want {
+				t.Errorf("parseEvent() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package ssh
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	vault "github.com/hashicorp/vault/api"
+	"github.com/soerenschneider/sc-agent/internal/domain/ssh"
+	"github.com/soerenschneider/sc-agent/internal/vaulttest"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+type sshTestClient struct {
+	client    *vault.Client
+	mountPath string
+}
+
+func (c *sshTestClient) SignKeyWithContext(ctx context.Context, role string, reqData map[string]any) (*vault.Secret, error) {
+	return c.client.SSHWithMountPoint(c.mountPath).SignKeyWithContext(ctx, role, reqData)
+}
+
+func (c *sshTestClient) ReadRawWithContext(ctx context.Context, path string) (*vault.Response, error) {
+	return c.client.Logical().ReadRawWithContext(ctx, path)
+}
+
+func TestService_SignAndUpdateCert(t *testing.T) {
+	server := vaulttest.NewServer(t)
+	server.EnableSsh("ssh")
+
+	client, err := NewVaultClient(&sshTestClient{client: server.Client(), mountPath: "ssh"}, "ssh")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sshPub, err := gossh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	publicKeyFile := filepath.Join(dir, "id_ed25519.pub")
+	if err := os.WriteFile(publicKeyFile, gossh.MarshalAuthorizedKey(sshPub), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	managed := ssh.ManagedCertificateConfig{
+		CertificateConfig: &ssh.CertificateConfig{
+			Id:         "host",
+			Role:       "hosts",
+			Principals: []string{"host.example.com", "host"},
+			CertType:   "host",
+			Ttl:        "24h",
+		},
+		StorageConfig: &ssh.CertificateStorage{
+			PublicKeyFile:   publicKeyFile,
+			CertificateFile: filepath.Join(dir, "id_ed25519-cert.pub"),
+		},
+	}
+
+	service, err := NewService(client, map[string]ssh.ManagedCertificateConfig{"host": managed})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		name       string
+		force      bool
+		wantAction string
+	}{
+		{
+			name:       "initial signature",
+			wantAction: ssh.ActionNewCertificate,
+		},
+		{
+			name:       "valid certificate is kept",
+			wantAction: ssh.ActionNotUpdate,
+		},
+		{
+			name:       "forced signature",
+			force:      true,
+			wantAction: ssh.ActionNewCertificate,
+		},
+	}
+
+	var serial uint64
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			result, err := service.SignAndUpdateCert(context.Background(), managed, step.force)
+			if err != nil {
+				t.Fatalf("SignAndUpdateCert() error = %v", err)
+			}
+			if result.Action != step.wantAction {
+				t.Errorf("SignAndUpdateCert() action = %v, want %v", result.Action, step.wantAction)
+			}
+
+			certData, err := os.ReadFile(managed.StorageConfig.CertificateFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			parsed, _, _, _, err := gossh.ParseAuthorizedKey(certData)
+			if err != nil {
+				t.Fatalf("SignAndUpdateCert() wrote invalid certificate: %v", err)
+			}
+			cert, ok := parsed.(*gossh.Certificate)
+			if !ok {
+				t.Fatalf("SignAndUpdateCert() wrote %T, want certificate", parsed)
+			}
+
+			checker := &gossh.CertChecker{
+				IsHostAuthority: func(auth gossh.PublicKey, _ string) bool {
+					return string(auth.Marshal()) == string(server.SshCaPublicKey("ssh").Marshal())
+				},
+			}
+			if err := checker.CheckCert("host.example.com", cert); err != nil {
+				t.Errorf("SignAndUpdateCert() wrote certificate that can not be verified: %v", err)
+			}
+			if cert.CertType != gossh.HostCert || !reflect.DeepEqual(cert.ValidPrincipals, managed.CertificateConfig.Principals) {
+				t.Errorf("SignAndUpdateCert() wrote certificate of type %d for %v", cert.CertType, cert.ValidPrincipals)
+			}
+
+			if step.wantAction == ssh.ActionNewCertificate && cert.Serial == serial {
+				t.Errorf("SignAndUpdateCert() expected new certificate, got serial %d", cert.Serial)
+			}
+			serial = cert.Serial
+		})
+	}
+
+	ca, err := service.ReadCaData(context.Background())
+	if err != nil {
+		t.Fatalf("ReadCaData() error = %v", err)
+	}
+	if strings.TrimSpace(ca) != strings.TrimSpace(string(gossh.MarshalAuthorizedKey(server.SshCaPublicKey("ssh")))) {
+		t.Errorf("ReadCaData() = %q", ca)
+	}
+}
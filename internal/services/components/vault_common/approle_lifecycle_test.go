@@ -0,0 +1,181 @@
+package vault_common
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	vault_config "github.com/soerenschneider/sc-agent/internal/config/vault"
+	"github.com/soerenschneider/sc-agent/internal/services/components/vault_common/auth"
+	"github.com/soerenschneider/sc-agent/internal/vaulttest"
+	"github.com/soerenschneider/sc-agent/pkg/vault"
+)
+
+func TestApproleLifecycle(t *testing.T) {
+	tests := []struct {
+		name    string
+		wrapTtl string
+	}{
+		{
+			name: "plain secret_id",
+		},
+		{
+			name:    "wrapped secret_id",
+			wrapTtl: "30m",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := vaulttest.NewServer(t)
+			server.EnableApprole("approle")
+			server.AddApproleRole("approle", "sc-agent", "sc-agent", 24*time.Hour)
+			server.AddSecretId("approle", "sc-agent", "initial", time.Now().Add(-20*time.Hour))
+
+			secretIdFile := filepath.Join(t.TempDir(), "secret_id")
+			if err := os.WriteFile(secretIdFile, []byte("initial"), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			client := server.Client()
+			client.ClearToken()
+			if tt.wrapTtl != "" {
+				client.SetWrappingLookupFunc(SecretIdWrappingLookup("approle", tt.wrapTtl))
+			}
+
+			approleAuth, err := auth.NewAppRoleAuth("sc-agent", &auth.SecretID{FromFile: secretIdFile}, auth.WithWrappingToken())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			renewer, err := NewTokenRenewer(client, approleAuth, "test")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go renewer.StartTokenRenewal(ctx)
+
+			select {
+			case <-renewer.Ready():
+			case <-time.After(5 * time.Second):
+				t.Fatalf("StartTokenRenewal() did not log in, status = %+v", renewer.Status())
+			}
+			if !renewer.Status().IsAuthenticated() {
+				t.Fatalf("Status() not authenticated: %+v", renewer.Status())
+			}
+
+			conf := vault_config.Vault{
+				RoleId:          "sc-agent",
+				SecretIdFile:    secretIdFile,
+				MountApprole:    "approle",
+				SecretIdWrapTtl: tt.wrapTtl,
+			}
+			approleClient, err := NewClient(client.Logical(), conf.MountApprole)
+			if err != nil {
+				t.Fatal(err)
+			}
+			unwrap := func() (string, error) {
+				return approleAuth.SecretID(ctx, client)
+			}
+			rotator, err := NewApproleUpdater(approleClient, "test", &conf, WithSecretIdUnwrapper(unwrap))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := rotator.ConditionallyRotateSecretId(conf, false); err != nil {
+				t.Fatalf("ConditionallyRotateSecretId() error = %v", err)
+			}
+
+			secretIds := server.SecretIds("approle", "sc-agent")
+			if len(secretIds) != 1 || secretIds[0] == "initial" {
+				t.Fatalf("ConditionallyRotateSecretId() expected old secret_id to be replaced, got %v", secretIds)
+			}
+
+			content, err := os.ReadFile(secretIdFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := vault.IsWrappedToken(string(content)); got != (tt.wrapTtl != "") {
+				t.Errorf("ConditionallyRotateSecretId() wrote wrapped secret_id = %t", got)
+			}
+
+			status := rotator.Status()
+			if status.LastRotation.IsZero() || status.Accessor == "" {
+				t.Errorf("Status() = %+v, expected rotation to be recorded", status)
+			}
+
+			// the next check uses the secret_id unwrapped by the login and must neither rotate nor consume the
+			// wrapping token a second time
+			if err := rotator.ConditionallyRotateSecretId(conf, false); err != nil {
+				t.Fatalf("ConditionallyRotateSecretId() error = %v", err)
+			}
+			if got := server.SecretIds("approle", "sc-agent"); len(got) != 1 || got[0] != secretIds[0] {
+				t.Fatalf("ConditionallyRotateSecretId() expected secret_id to be kept, got %v", got)
+			}
+
+			// a fresh client must be able to log in using the rotated secret_id
+			loginClient := server.Client()
+			loginClient.ClearToken()
+			secret, err := approleAuth.Login(ctx, loginClient)
+			if err != nil {
+				t.Fatalf("Login() with rotated secret_id error = %v", err)
+			}
+			if secret == nil || secret.Auth == nil || !strings.HasPrefix(secret.Auth.ClientToken, "hvs.") {
+				t.Fatalf("Login() returned unexpected secret %+v", secret)
+			}
+
+			content, err = os.ReadFile(secretIdFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != secretIds[0] {
+				t.Errorf("Login() expected secret_id file to contain unwrapped secret_id, got %q", content)
+			}
+		})
+	}
+}
+
+func TestTokenRenewer_RenewsToken(t *testing.T) {
+	server := vaulttest.NewServer(t, vaulttest.WithTokenTtl(2*time.Second))
+	server.EnableApprole("approle")
+	server.AddApproleRole("approle", "sc-agent", "sc-agent", 0)
+	server.AddSecretId("approle", "sc-agent", "secret", time.Now())
+
+	client := server.Client()
+	client.ClearToken()
+	approleAuth, err := auth.NewAppRoleAuth("sc-agent", &auth.SecretID{FromString: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	renewer, err := NewTokenRenewer(client, approleAuth, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go renewer.StartTokenRenewal(ctx)
+
+	select {
+	case <-renewer.Ready():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("StartTokenRenewal() did not log in, status = %+v", renewer.Status())
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for renewer.Status().LastRenewal.IsZero() {
+		if time.Now().After(deadline) {
+			t.Fatalf("StartTokenRenewal() did not renew token, status = %+v", renewer.Status())
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	if !renewer.Status().IsAuthenticated() {
+		t.Errorf("Status() not authenticated after renewal: %+v", renewer.Status())
+	}
+}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/rs/zerolog/log"
 	vault_config "github.com/soerenschneider/sc-agent/internal/config/vault"
+	domain "github.com/soerenschneider/sc-agent/internal/domain/vault"
 	"github.com/soerenschneider/sc-agent/internal/metrics"
 	"github.com/soerenschneider/sc-agent/pkg/vault"
 	"github.com/spf13/afero"
@@ -35,7 +36,7 @@ type ApproleClient interface {
 	DestroySecretId(roleName, secretId string, isAccessor bool) error
 	Lookup(roleName, secretId string, isAccessor bool) (*vault.SecretIdInfo, error)
 	GetSecretIdAccessors(roleName string) ([]string, error)
-	GenerateSecretId(roleName string) (string, error)
+	GenerateSecretId(roleName string) (string, string, error)
 	ReadRoleId(roleName string) (string, error)
 }
 
@@ -48,10 +49,34 @@ type ApproleSecretIdRotatorService struct {
 
 	once   sync.Once
 	fsImpl afero.Fs
+
+	// unwrapSecretId unwraps a wrapped secret_id and replaces it in the secret_id file. It is provided by the AppRole
+	// login, so a wrapping token is only ever consumed by a single party.
+	unwrapSecretId func() (string, error)
+
+	// rotationMutex serializes scheduled and forced rotations
+	rotationMutex sync.Mutex
+	// wrappedAccessor is the accessor of the wrapped secret_id that has been written by the last rotation, it is used
+	// to destroy the secret_id if it is rotated again before it has been unwrapped.
+	wrappedAccessor string
+	status          domain.SecretIdStatus
+	statusMutex     sync.Mutex
 }
 
 type ApproleSecretIdRotationOption func(a *ApproleSecretIdRotatorService) error
 
+// WithSecretIdUnwrapper sets the function that returns the unwrapped secret_id if the secret_id file contains a
+// wrapping token. Without it, wrapped secret_ids are rotated immediately.
+func WithSecretIdUnwrapper(unwrap func() (string, error)) ApproleSecretIdRotationOption {
+	return func(a *ApproleSecretIdRotatorService) error {
+		if unwrap == nil {
+			return errors.New("empty unwrap func provided")
+		}
+		a.unwrapSecretId = unwrap
+		return nil
+	}
+}
+
 func NewApproleUpdater(client ApproleClient, approleIdentifier string, config *vault_config.Vault, opts ...ApproleSecretIdRotationOption) (*ApproleSecretIdRotatorService, error) {
 	ret := &ApproleSecretIdRotatorService{
 		client:            client,
@@ -69,6 +94,17 @@ func NewApproleUpdater(client ApproleClient, approleIdentifier string, config *v
 		}
 	}
 
+	// a wrapped secret_id has to be unwrapped by the next check, otherwise the wrapping token expires and the secret_id
+	// is rotated again
+	if config != nil && config.SecretIdWrapTtl != "" {
+		wrapTtl, err := time.ParseDuration(config.SecretIdWrapTtl)
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("can not parse 'secret_id_wrap_ttl' duration string '%s'", config.SecretIdWrapTtl))
+		} else if wrapTtl <= ret.checkInterval {
+			errs = multierr.Append(errs, fmt.Errorf("'secret_id_wrap_ttl' must be greater than the check interval of %s", ret.checkInterval))
+		}
+	}
+
 	return ret, errs
 }
 
@@ -95,7 +131,87 @@ func (a *ApproleSecretIdRotatorService) StartSecretIdRotation(ctx context.Contex
 	})
 }
 
+// Status returns the state of the secret_id rotation.
+func (a *ApproleSecretIdRotatorService) Status() domain.SecretIdStatus {
+	a.statusMutex.Lock()
+	defer a.statusMutex.Unlock()
+	return a.status
+}
+
+func (a *ApproleSecretIdRotatorService) recordSecretIdInfo(info *vault.SecretIdInfo) {
+	a.statusMutex.Lock()
+	defer a.statusMutex.Unlock()
+
+	a.status.LastCheck = time.Now()
+	if info == nil {
+		a.status.Accessor = ""
+		a.status.Expiration = time.Time{}
+		a.status.NextRotation = time.Time{}
+		return
+	}
+
+	a.status.Accessor = info.Accessor
+	a.status.Expiration = info.Expiration
+	a.status.NextRotation = time.Time{}
+	if !info.Expiration.IsZero() {
+		lifetime := info.Expiration.Sub(info.CreationTime)
+		a.status.NextRotation = info.CreationTime.Add(time.Duration(float64(lifetime) * (1 - a.minPercentage/100)))
+	}
+}
+
+func (a *ApproleSecretIdRotatorService) recordRotation() {
+	a.statusMutex.Lock()
+	defer a.statusMutex.Unlock()
+	a.status.LastRotation = time.Now()
+}
+
+func (a *ApproleSecretIdRotatorService) recordError(err error) {
+	a.statusMutex.Lock()
+	defer a.statusMutex.Unlock()
+	a.status.LastError = err.Error()
+	a.status.LastErrorTime = time.Now()
+}
+
+// refreshStatus looks up the rotated secret_id to update the status. Wrapped secret_ids can not be looked up without
+// consuming the wrapping token, so they are looked up using their accessor.
+func (a *ApproleSecretIdRotatorService) refreshStatus(cnf vault_config.Vault, secretId, accessor string, isAccessor bool) {
+	if vault.IsWrappedToken(secretId) {
+		if accessor == "" {
+			a.recordSecretIdInfo(nil)
+			return
+		}
+		secretId = accessor
+		isAccessor = true
+	}
+
+	secretIdInfo, err := a.client.Lookup(cnf.RoleId, secretId, isAccessor)
+	if err != nil {
+		log.Debug().Str(logComponent, "vault").Str(logSubComponent, approleComponentName).Str("id", a.approleIdentifier).Err(err).Msg("could not lookup rotated secret_id")
+	}
+	a.recordSecretIdInfo(secretIdInfo)
+}
+
 func (a *ApproleSecretIdRotatorService) ConditionallyRotateSecretId(cnf vault_config.Vault, isAccessor bool) error {
+	return a.rotateSecretId(cnf, isAccessor, false)
+}
+
+// RotateSecretId rotates the secret_id immediately, regardless of its remaining lifetime.
+func (a *ApproleSecretIdRotatorService) RotateSecretId() error {
+	return a.rotateSecretId(*a.vaultConfig, false, true)
+}
+
+func (a *ApproleSecretIdRotatorService) rotateSecretId(cnf vault_config.Vault, isAccessor bool, force bool) error {
+	a.rotationMutex.Lock()
+	defer a.rotationMutex.Unlock()
+
+	err := a.doRotateSecretId(cnf, isAccessor, force)
+	if err != nil {
+		a.recordError(err)
+	}
+	return err
+}
+
+func (a *ApproleSecretIdRotatorService) doRotateSecretId(cnf vault_config.Vault, isAccessor bool, force bool) error {
 	secretId, err := a.readSecretId(cnf.SecretIdFile)
 	if err != nil {
 		log.Error().Str(logComponent, "vault").Str(logSubComponent, approleComponentName).Str(logSecretIdFile, cnf.SecretIdFile).Str("id", a.approleIdentifier).Err(err).Msg("could not read secret_id")
@@ -103,23 +219,40 @@ func (a *ApproleSecretIdRotatorService) ConditionallyRotateSecretId(cnf vault_co
 		return err
 	}
 
+	// a wrapped secret_id is either delivered on first boot or the result of a rotation that requested a wrapped
+	// secret_id. It is unwrapped by the AppRole login, which replaces the file's content with the unwrapped secret_id.
+	// If it can not be unwrapped anymore, it is rotated immediately.
 	isWrapped := vault.IsWrappedToken(secretId)
-	if isWrapped {
-		log.Warn().Str(logComponent, "vault").Str(logSubComponent, approleComponentName).Str(logSecretIdFile, cnf.SecretIdFile).Str("id", a.approleIdentifier).Msg("Detected a wrapped secret_id, trying to rotate immediately")
-	} else {
+	if isWrapped && a.unwrapSecretId != nil {
+		unwrapped, err := a.unwrapSecretId()
+		switch {
+		case err != nil:
+			log.Warn().Str(logComponent, "vault").Str(logSubComponent, approleComponentName).Str(logSecretIdFile, cnf.SecretIdFile).Str("id", a.approleIdentifier).Err(err).Msg("Could not unwrap secret_id, trying to rotate immediately")
+		case vault.IsWrappedToken(unwrapped):
+			log.Warn().Str(logComponent, "vault").Str(logSubComponent, approleComponentName).Str(logSecretIdFile, cnf.SecretIdFile).Str("id", a.approleIdentifier).Msg("secret_id has not been unwrapped, trying to rotate immediately")
+		default:
+			log.Info().Str(logComponent, "vault").Str(logSubComponent, approleComponentName).Str(logSecretIdFile, cnf.SecretIdFile).Str("id", a.approleIdentifier).Msg("Using unwrapped secret_id")
+			secretId = unwrapped
+			isWrapped = false
+			a.wrappedAccessor = ""
+		}
+	}
+
+	if !isWrapped {
 		secretIdInfo, err := a.client.Lookup(cnf.RoleId, secretId, isAccessor)
 		if err != nil && !errors.Is(err, errAlreadyExpired) {
 			log.Error().Str(logComponent, "vault").Str(logSubComponent, approleComponentName).Str("id", a.approleIdentifier).Str(logSecretIdFile, cnf.SecretIdFile).Err(err).Msg("could not lookup secret_id")
 			return err
 		}
 
+		a.recordSecretIdInfo(secretIdInfo)
 		if secretIdInfo == nil {
 			log.Warn().Str(logComponent, "vault").Str(logSubComponent, approleComponentName).Str("id", a.approleIdentifier).Msg("empty response for looking up secret_id, this indicates an expired secret_id")
 		} else {
 			secretIdPercentage := secretIdInfo.GetPercentage()
 			metrics.SecretIdPercentage.WithLabelValues(cnf.SecretIdFile).Set(secretIdPercentage)
 			metrics.SecretIdTtl.WithLabelValues(cnf.SecretIdFile).Set(float64(secretIdInfo.Ttl))
-			if secretIdPercentage >= a.minPercentage {
+			if secretIdPercentage >= a.minPercentage && !force {
 				log.Debug().Str(logComponent, "vault").Str(logSubComponent, approleComponentName).Str("id", a.approleIdentifier).Str(logSecretIdFile, cnf.SecretIdFile).Str("expiration", secretIdInfo.Expiration.String()).Float64("lifetime", secretIdPercentage).Msg("not renewing secret_id")
 				return nil
 			}
@@ -128,7 +261,7 @@ func (a *ApproleSecretIdRotatorService) ConditionallyRotateSecretId(cnf vault_co
 	}
 
 	log.Info().Str(logComponent, "vault").Str(logSubComponent, approleComponentName).Str("id", a.approleIdentifier).Str(logSecretIdFile, cnf.SecretIdFile).Msg("generating new secret_id")
-	newSecretId, err := a.client.GenerateSecretId(cnf.RoleId)
+	newSecretId, newAccessor, err := a.client.GenerateSecretId(cnf.RoleId)
 	if err != nil {
 		log.Error().Str(logComponent, "vault").Str(logSubComponent, approleComponentName).Str(logSecretIdFile, cnf.SecretIdFile).Err(err).Msg("could not generate new secret_id")
 		metrics.SecretIdRotationErrors.WithLabelValues(cnf.SecretIdFile, "generate_secret_id").Inc()
@@ -140,6 +273,25 @@ func (a *ApproleSecretIdRotatorService) ConditionallyRotateSecretId(cnf vault_co
 		metrics.SecretIdRotationErrors.WithLabelValues(cnf.SecretIdFile, "write_file").Inc()
 		return err
 	}
+	a.recordRotation()
+
+	// the secret_id behind a wrapping token that can not be unwrapped anymore can only be destroyed using the accessor
+	// that has been returned when it was generated
+	previousAccessor := a.wrappedAccessor
+	a.wrappedAccessor = ""
+	if vault.IsWrappedToken(newSecretId) {
+		a.wrappedAccessor = newAccessor
+	}
+	a.refreshStatus(cnf, newSecretId, newAccessor, isAccessor)
+
+	if isWrapped {
+		if previousAccessor == "" {
+			log.Warn().Str(logComponent, "vault").Str(logSubComponent, approleComponentName).Str("id", a.approleIdentifier).Str(logSecretIdFile, cnf.SecretIdFile).Msg("rotated secret_id, but the accessor of the wrapped secret_id is unknown and it can not be destroyed")
+			return nil
+		}
+		secretId = previousAccessor
+		isAccessor = true
+	}
 
 	err = a.client.DestroySecretId(cnf.RoleId, secretId, isAccessor)
 	if err != nil {
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 
 	"github.com/hashicorp/vault/api"
@@ -138,24 +139,45 @@ func (a *ApproleSecretIdRotatorClient) GetSecretIdAccessors(roleName string) ([]
 	return stringSlice, nil
 }
 
-func (a *ApproleSecretIdRotatorClient) GenerateSecretId(roleName string) (string, error) {
+// GenerateSecretId generates a new secret_id and returns it together with its accessor. If the secret_id has been
+// response-wrapped, the wrapping token is returned instead of the secret_id.
+func (a *ApproleSecretIdRotatorClient) GenerateSecretId(roleName string) (string, string, error) {
 	path := fmt.Sprintf("auth/%s/role/%s/secret-id", a.mountPath, roleName)
 	resp, err := a.client.Write(path, a.generateSecretIdRotationData())
 	if err != nil {
-		return "", fmt.Errorf("unable to create new secret_id: %w", err)
+		return "", "", fmt.Errorf("unable to create new secret_id: %w", err)
+	}
+
+	// the secret_id has been response-wrapped, see SecretIdWrappingLookup
+	if resp.WrapInfo != nil && resp.WrapInfo.Token != "" {
+		return resp.WrapInfo.Token, resp.WrapInfo.WrappedAccessor, nil
 	}
 
 	respData, found := resp.Data["secret_id"]
 	if !found {
-		return "", errors.New("no field 'secret_id' in response")
+		return "", "", errors.New("no field 'secret_id' in response")
 	}
 
 	converted, conversionOk := respData.(string)
 	if !conversionOk {
-		return "", errors.New("could not convert 'secret_id' to string")
+		return "", "", errors.New("could not convert 'secret_id' to string")
 	}
 
-	return converted, nil
+	accessor, _ := resp.Data["secret_id_accessor"].(string)
+	return converted, accessor, nil
+}
+
+// SecretIdWrappingLookup returns a wrapping lookup function for a Vault client that response-wraps secret_ids that are
+// generated for roles of the given AppRole mount with the given TTL. All other requests use the default behaviour.
+func SecretIdWrappingLookup(mountPath, ttl string) api.WrappingLookupFunc {
+	prefix := fmt.Sprintf("auth/%s/role/", strings.Trim(mountPath, "/"))
+	return func(operation, path string) string {
+		path = strings.TrimPrefix(path, "/")
+		if (operation == http.MethodPut || operation == http.MethodPost) && strings.HasPrefix(path, prefix) && strings.HasSuffix(path, "/secret-id") {
+			return ttl
+		}
+		return api.DefaultWrappingLookupFunc(operation, path)
+	}
 }
 
 func (a *ApproleSecretIdRotatorClient) ReadRoleId(roleName string) (string, error) {
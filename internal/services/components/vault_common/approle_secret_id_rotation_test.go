@@ -0,0 +1,262 @@
+package vault_common
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	vault_config "github.com/soerenschneider/sc-agent/internal/config/vault"
+	"github.com/soerenschneider/sc-agent/pkg/vault"
+	"github.com/spf13/afero"
+)
+
+type fakeApproleClient struct {
+	secretIds map[string]*vault.SecretIdInfo
+	wrapped   map[string]string
+	generate  string
+	// generateAccessor is the accessor of the generated secret_id
+	generateAccessor string
+	destroyed        []string
+}
+
+func (f *fakeApproleClient) DestroySecretId(_, secretId string, _ bool) error {
+	f.destroyed = append(f.destroyed, secretId)
+	return nil
+}
+
+func (f *fakeApproleClient) Lookup(_, secretId string, _ bool) (*vault.SecretIdInfo, error) {
+	info, found := f.secretIds[secretId]
+	if !found {
+		return nil, errAlreadyExpired
+	}
+	return info, nil
+}
+
+func (f *fakeApproleClient) GetSecretIdAccessors(_ string) ([]string, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakeApproleClient) GenerateSecretId(_ string) (string, string, error) {
+	return f.generate, f.generateAccessor, nil
+}
+
+func (f *fakeApproleClient) ReadRoleId(_ string) (string, error) {
+	return "", errors.New("not implemented")
+}
+
+func TestApproleSecretIdRotatorService_ConditionallyRotateWrappedSecretId(t *testing.T) {
+	fresh := &vault.SecretIdInfo{CreationTime: time.Now(), Expiration: time.Now().Add(24 * time.Hour)}
+
+	tests := []struct {
+		name            string
+		file            string
+		wrappedAccessor string
+		client          *fakeApproleClient
+		wantFile        string
+		wantDestroyed   []string
+	}{
+		{
+			name: "wrapped secret_id is replaced",
+			file: "hvs.wrapped\n",
+			client: &fakeApproleClient{
+				secretIds: map[string]*vault.SecretIdInfo{"unwrapped": fresh},
+				wrapped:   map[string]string{"hvs.wrapped": "unwrapped"},
+				generate:  "new",
+			},
+			wantFile: "unwrapped",
+		},
+		{
+			name: "expired wrapping token is rotated",
+			file: "hvs.expired",
+			client: &fakeApproleClient{
+				wrapped:  map[string]string{},
+				generate: "hvs.new",
+			},
+			wantFile: "hvs.new",
+		},
+		{
+			name:            "expired wrapping token of previous rotation is destroyed using its accessor",
+			file:            "hvs.expired",
+			wrappedAccessor: "expired-accessor",
+			client: &fakeApproleClient{
+				wrapped:          map[string]string{},
+				generate:         "hvs.new",
+				generateAccessor: "new-accessor",
+			},
+			wantFile:      "hvs.new",
+			wantDestroyed: []string{"expired-accessor"},
+		},
+		{
+			name: "unwrapped expiring secret_id is rotated to wrapped secret_id",
+			file: "unwrapped",
+			client: &fakeApproleClient{
+				secretIds: map[string]*vault.SecretIdInfo{"unwrapped": {CreationTime: time.Now().Add(-time.Hour), Expiration: time.Now().Add(time.Minute)}},
+				generate:  "hvs.new",
+			},
+			wantFile:      "hvs.new",
+			wantDestroyed: []string{"unwrapped"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := &vault_config.Vault{RoleId: "role", SecretIdFile: "/secret_id"}
+			fs := afero.NewMemMapFs()
+			// mimics the AppRole login that replaces the wrapping token with the unwrapped secret_id
+			unwrap := func() (string, error) {
+				content, err := afero.ReadFile(fs, conf.SecretIdFile)
+				if err != nil {
+					return "", err
+				}
+				secretId, found := tt.client.wrapped[strings.TrimSpace(string(content))]
+				if !found {
+					return "", errors.New("wrapping token is not valid or does not exist")
+				}
+				delete(tt.client.wrapped, strings.TrimSpace(string(content)))
+				return secretId, afero.WriteFile(fs, conf.SecretIdFile, []byte(secretId), 0600)
+			}
+			rotator, err := NewApproleUpdater(tt.client, "test", conf, WithSecretIdUnwrapper(unwrap))
+			if err != nil {
+				t.Fatal(err)
+			}
+			rotator.fsImpl = fs
+			rotator.wrappedAccessor = tt.wrappedAccessor
+			if err := afero.WriteFile(rotator.fsImpl, conf.SecretIdFile, []byte(tt.file), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := rotator.ConditionallyRotateSecretId(*conf, false); err != nil {
+				t.Fatalf("ConditionallyRotateSecretId() error = %v", err)
+			}
+
+			got, _ := afero.ReadFile(rotator.fsImpl, conf.SecretIdFile)
+			if string(got) != tt.wantFile {
+				t.Errorf("ConditionallyRotateSecretId() file = %q, want %q", got, tt.wantFile)
+			}
+			if len(tt.client.destroyed) != len(tt.wantDestroyed) || (len(tt.wantDestroyed) > 0 && tt.client.destroyed[0] != tt.wantDestroyed[0]) {
+				t.Errorf("ConditionallyRotateSecretId() destroyed = %v, want %v", tt.client.destroyed, tt.wantDestroyed)
+			}
+		})
+	}
+}
+
+func TestNewApproleUpdater_WrapTtl(t *testing.T) {
+	tests := []struct {
+		wrapTtl string
+		wantErr bool
+	}{
+		{wrapTtl: "", wantErr: false},
+		{wrapTtl: "30m", wantErr: false},
+		{wrapTtl: "10m", wantErr: true},
+		{wrapTtl: "5m", wantErr: true},
+		{wrapTtl: "invalid", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.wrapTtl, func(t *testing.T) {
+			conf := &vault_config.Vault{RoleId: "role", SecretIdFile: "/secret_id", SecretIdWrapTtl: tt.wrapTtl}
+			if _, err := NewApproleUpdater(&fakeApproleClient{}, "test", conf); (err != nil) != tt.wantErr {
+				t.Errorf("NewApproleUpdater() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSecretIdWrappingLookup(t *testing.T) {
+	lookup := SecretIdWrappingLookup("approle", "5m")
+
+	tests := []struct {
+		operation string
+		path      string
+		want      string
+	}{
+		{operation: http.MethodPut, path: "auth/approle/role/my-role/secret-id", want: "5m"},
+		{operation: http.MethodPost, path: "/auth/approle/role/my-role/secret-id", want: "5m"},
+		{operation: http.MethodPut, path: "auth/approle/role/my-role/secret-id/lookup", want: ""},
+		{operation: http.MethodGet, path: "auth/approle/role/my-role/secret-id", want: ""},
+		{operation: http.MethodPut, path: "auth/other/role/my-role/secret-id", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.operation+" "+tt.path, func(t *testing.T) {
+			t.Setenv("VAULT_WRAP_TTL", "")
+			if got := lookup(tt.operation, tt.path); got != tt.want {
+				t.Errorf("SecretIdWrappingLookup() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApproleSecretIdRotatorService_Status(t *testing.T) {
+	created := time.Now().Add(-time.Hour)
+	current := &vault.SecretIdInfo{Accessor: "current-accessor", CreationTime: created, Expiration: created.Add(10 * time.Hour)}
+	rotated := &vault.SecretIdInfo{Accessor: "new-accessor", CreationTime: time.Now(), Expiration: time.Now().Add(10 * time.Hour)}
+
+	tests := []struct {
+		name             string
+		force            bool
+		wantFile         string
+		wantAccessor     string
+		wantNextRotation time.Time
+		wantRotated      bool
+	}{
+		{
+			name:             "fresh secret_id is not rotated",
+			wantFile:         "current",
+			wantAccessor:     "current-accessor",
+			wantNextRotation: created.Add(5 * time.Hour),
+		},
+		{
+			name:             "forced rotation",
+			force:            true,
+			wantFile:         "new",
+			wantAccessor:     "new-accessor",
+			wantNextRotation: rotated.CreationTime.Add(5 * time.Hour),
+			wantRotated:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeApproleClient{
+				secretIds: map[string]*vault.SecretIdInfo{"current": current, "new": rotated},
+				generate:  "new",
+			}
+			conf := &vault_config.Vault{RoleId: "role", SecretIdFile: "/secret_id"}
+			rotator, err := NewApproleUpdater(client, "test", conf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rotator.fsImpl = afero.NewMemMapFs()
+			if err := afero.WriteFile(rotator.fsImpl, conf.SecretIdFile, []byte("current"), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.force {
+				err = rotator.RotateSecretId()
+			} else {
+				err = rotator.ConditionallyRotateSecretId(*conf, false)
+			}
+			if err != nil {
+				t.Fatalf("rotation error = %v", err)
+			}
+
+			got, _ := afero.ReadFile(rotator.fsImpl, conf.SecretIdFile)
+			if string(got) != tt.wantFile {
+				t.Errorf("rotation file = %q, want %q", got, tt.wantFile)
+			}
+
+			status := rotator.Status()
+			if status.Accessor != tt.wantAccessor {
+				t.Errorf("Status() accessor = %q, want %q", status.Accessor, tt.wantAccessor)
+			}
+			if diff := status.NextRotation.Sub(tt.wantNextRotation).Abs(); diff > time.Second {
+				t.Errorf("Status() next rotation = %v, want %v", status.NextRotation, tt.wantNextRotation)
+			}
+			if status.LastRotation.IsZero() == tt.wantRotated {
+				t.Errorf("Status() last rotation = %v, want rotated = %t", status.LastRotation, tt.wantRotated)
+			}
+			if status.LastCheck.IsZero() {
+				t.Error("Status() last check is not set")
+			}
+		})
+	}
+}
@@ -5,11 +5,14 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
+	"syscall"
 
 	"github.com/hashicorp/vault/api"
 	"github.com/rs/zerolog/log"
+	"github.com/soerenschneider/sc-agent/pkg/vault"
 )
 
 type AppRoleAuth struct {
@@ -20,7 +23,6 @@ type AppRoleAuth struct {
 	secretIDEnv  string
 	unwrap       bool
 	mutex        sync.Mutex
-	once         sync.Once
 }
 
 var _ api.AuthMethod = (*AppRoleAuth)(nil)
@@ -111,6 +113,35 @@ func (a *AppRoleAuth) Login(ctx context.Context, client *api.Client) (*api.Secre
 		ctx = context.Background()
 	}
 
+	secretIDValue, err := a.readSecretID(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	loginData := map[string]interface{}{
+		"role_id": a.roleID,
+		"secret_id": secretIDValue,
+	}
+
+	path := fmt.Sprintf("auth/%s/login", a.mountPath)
+	resp, err := client.Logical().WriteWithContext(ctx, path, loginData)
+	if err != nil {
+		return nil, fmt.Errorf("unable to log in with app role auth: %w", err)
+	}
+
+	return resp, nil
+}
+
+// SecretID returns the secret ID that is used to log in. A wrapped secret ID is unwrapped and replaced the same way it
+// is at login, so other consumers, e.g. the secret_id rotation, do not consume the wrapping token themselves.
+func (a *AppRoleAuth) SecretID(ctx context.Context, client *api.Client) (string, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	return a.readSecretID(ctx, client)
+}
+
+func (a *AppRoleAuth) readSecretID(ctx context.Context, client *api.Client) (string, error) {
 	var secretIDValue string
 	switch {
 	case a.secretID != "":
@@ -118,66 +149,88 @@ func (a *AppRoleAuth) Login(ctx context.Context, client *api.Client) (*api.Secre
 	case a.secretIDFile != "":
 		s, err := a.readSecretIDFromFile()
 		if err != nil {
-			return nil, fmt.Errorf("error reading secret ID: %w", err)
+			return "", fmt.Errorf("error reading secret ID: %w", err)
 		}
 		secretIDValue = s
 	case a.secretIDEnv != "":
 		s := os.Getenv(a.secretIDEnv)
 		if s == "" {
-			return nil, fmt.Errorf("secret ID was specified with an environment variable %q with an empty value", a.secretIDEnv)
+			return "", fmt.Errorf("secret ID was specified with an environment variable %q with an empty value", a.secretIDEnv)
 		}
 		secretIDValue = s
 	}
 
-	var unwrapErr error
-	a.once.Do(func() {
-		// if the caller indicated that the value was actually a wrapping token, unwrap it first
-		if a.unwrap {
-			log.Info().Str("subcomponent", "auth-approle").Str("component", "vault").Msg("attempting to unwrap secret ID")
-			unwrappedToken, err := client.Logical().UnwrapWithContext(ctx, secretIDValue)
-			if err != nil {
-				unwrapErr = fmt.Errorf("unable to unwrap response wrapping token: %w", err)
-				return
-			}
-			log.Info().Str("subcomponent", "auth-approle").Str("component", "vault").Msg("secret_id unwrapped successfully")
-
-			data, ok := unwrappedToken.Data["secret_id"]
-			if !ok {
-				unwrapErr = fmt.Errorf("unable to unwrap response wrapping token: secret_id not found in response")
-				return
-			}
-
-			secretIDValue = data.(string)
-			// write back unwrapped secret_id to file so it can be consumed by multiple clients
-			if a.secretIDFile != "" {
-				log.Info().Str("component", "vault").Str("secret_id_file", a.secretIDFile).Msg("Writing unwrapped secret_id to file")
-				data := []byte(secretIDValue)
-				if err := os.WriteFile(a.secretIDFile, data, 0600); err != nil {
-					log.Error().Str("component", "vault").Err(err).Msg("could not write unwrapped secret_id to file")
-				}
-			} else if a.secretIDEnv != "" {
-				// store back unwrapped secret_id so it can be consumed by multiple clients
-				a.secretID = secretIDValue
-			}
+	// the secret ID is unwrapped whenever a wrapping token is found, e.g. one that has been delivered on first boot or
+	// one that has been requested by the secret ID rotation
+	if a.unwrap && vault.IsWrappedToken(secretIDValue) {
+		unwrapped, err := a.unwrapSecretID(ctx, client, secretIDValue)
+		if err != nil {
+			return "", err
 		}
-	})
+		secretIDValue = unwrapped
+	}
 
-	if unwrapErr != nil {
-		return nil, unwrapErr
+	return secretIDValue, nil
+}
+
+func (a *AppRoleAuth) unwrapSecretID(ctx context.Context, client *api.Client, wrappingToken string) (string, error) {
+	log.Info().Str("subcomponent", "auth-approle").Str("component", "vault").Msg("attempting to unwrap secret ID")
+	unwrappedToken, err := client.Logical().UnwrapWithContext(ctx, wrappingToken)
+	if err != nil {
+		return "", fmt.Errorf("unable to unwrap response wrapping token: %w", err)
+	}
+	if unwrappedToken == nil {
+		return "", fmt.Errorf("unable to unwrap response wrapping token: empty response")
 	}
+	log.Info().Str("subcomponent", "auth-approle").Str("component", "vault").Msg("secret_id unwrapped successfully")
 
-	loginData := map[string]interface{}{
-		"role_id": a.roleID,
-		"secret_id": secretIDValue,
+	secretID, ok := unwrappedToken.Data["secret_id"].(string)
+	if !ok {
+		return "", fmt.Errorf("unable to unwrap response wrapping token: secret_id not found in response")
 	}
 
-	path := fmt.Sprintf("auth/%s/login", a.mountPath)
-	resp, err := client.Logical().WriteWithContext(ctx, path, loginData)
+	// write back unwrapped secret_id so it can be consumed by multiple clients
+	if a.secretIDFile != "" {
+		log.Info().Str("component", "vault").Str("secret_id_file", a.secretIDFile).Msg("Writing unwrapped secret_id to file")
+		if err := replaceFile(a.secretIDFile, []byte(secretID)); err != nil {
+			log.Error().Str("component", "vault").Err(err).Msg("could not write unwrapped secret_id to file")
+		}
+	} else {
+		a.secretID = secretID
+	}
+
+	return secretID, nil
+}
+
+// replaceFile atomically replaces the content of the file, keeping its owner.
+func replaceFile(file string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(file), "."+filepath.Base(file)+".tmp")
 	if err != nil {
-		return nil, fmt.Errorf("unable to log in with app role auth: %w", err)
+		return err
 	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
 
-	return resp, nil
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if info, err := os.Stat(file); err == nil {
+		if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+			_ = os.Chown(tmp.Name(), int(stat.Uid), int(stat.Gid))
+		}
+	}
+
+	return os.Rename(tmp.Name(), file)
 }
 
 func WithMountPath(mountPath string) LoginOption {
@@ -187,6 +240,8 @@ func WithMountPath(mountPath string) LoginOption {
 	}
 }
 
+// WithWrappingToken unwraps the secret ID before logging in if it is a response-wrapping token. If the secret ID is
+// read from a file, the file is replaced with the unwrapped secret ID.
 func WithWrappingToken() LoginOption {
 	return func(a *AppRoleAuth) error {
 		a.unwrap = true
@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/vault/api"
+)
+
+func TestAppRoleAuth_LoginUnwrap(t *testing.T) {
+	var unwraps int
+	var gotSecretIds []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/sys/wrapping/unwrap":
+			unwraps++
+			if r.Header.Get("X-Vault-Token") != "hvs.wrapped" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write([]byte(`{"data": {"secret_id": "unwrapped"}}`))
+		case "/v1/auth/approle/login":
+			var body map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			gotSecretIds = append(gotSecretIds, body["secret_id"])
+			_, _ = w.Write([]byte(`{"auth": {"client_token": "token"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	conf := api.DefaultConfig()
+	conf.Address = server.URL
+	client, err := api.NewClient(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secretIdFile := filepath.Join(t.TempDir(), "secret_id")
+	if err := os.WriteFile(secretIdFile, []byte("hvs.wrapped\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	auth, err := NewAppRoleAuth("role", &SecretID{FromFile: secretIdFile}, WithWrappingToken())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := auth.Login(context.Background(), client); err != nil {
+			t.Fatalf("Login() error = %v", err)
+		}
+	}
+
+	if unwraps != 1 {
+		t.Errorf("Login() unwraps = %d, want 1", unwraps)
+	}
+	if len(gotSecretIds) != 2 || gotSecretIds[0] != "unwrapped" || gotSecretIds[1] != "unwrapped" {
+		t.Errorf("Login() secret_ids = %v", gotSecretIds)
+	}
+
+	data, err := os.ReadFile(secretIdFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "unwrapped" {
+		t.Errorf("Login() secret_id file = %q, want %q", data, "unwrapped")
+	}
+}
@@ -0,0 +1,206 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/rs/zerolog/log"
+)
+
+const defaultCertMountPath = "cert"
+
+// CertAuth logs in using the TLS certificate auth method. The certificate and key are read from disk for every login,
+// so a certificate that is renewed by the pki component is picked up automatically.
+type CertAuth struct {
+	mountPath string
+	role      string
+	certFile  string
+	keyFile   string
+
+	// bootstrap is used to log in as long as no valid certificate is available, e.g. before the pki component has
+	// issued the first certificate.
+	bootstrap api.AuthMethod
+
+	// loggedInCert is the certificate used for the latest successful login, nil if the bootstrap auth method was used
+	loggedInCert []byte
+	mutex        sync.Mutex
+}
+
+var _ api.AuthMethod = (*CertAuth)(nil)
+
+type CertLoginOption func(a *CertAuth) error
+
+// NewCertAuth initializes a new TLS certificate auth method that logs in using the given certificate and key files.
+//
+// Supported options: WithCertMountPath, WithCertRole, WithBootstrapAuth
+func NewCertAuth(certFile, keyFile string, opts ...CertLoginOption) (*CertAuth, error) {
+	if certFile == "" {
+		return nil, errors.New("no certificate file provided for login")
+	}
+
+	if keyFile == "" {
+		return nil, errors.New("no key file provided for login")
+	}
+
+	a := &CertAuth{
+		mountPath: defaultCertMountPath,
+		certFile:  certFile,
+		keyFile:   keyFile,
+	}
+
+	for _, opt := range opts {
+		if err := opt(a); err != nil {
+			return nil, fmt.Errorf("error with login option: %w", err)
+		}
+	}
+
+	return a, nil
+}
+
+func WithCertMountPath(mountPath string) CertLoginOption {
+	return func(a *CertAuth) error {
+		a.mountPath = mountPath
+		return nil
+	}
+}
+
+// WithCertRole sets the name of the certificate role to authenticate against. If it is not set, Vault tries all
+// roles of the mount.
+func WithCertRole(role string) CertLoginOption {
+	return func(a *CertAuth) error {
+		a.role = role
+		return nil
+	}
+}
+
+// WithBootstrapAuth sets the auth method that is used as long as the certificate does not exist or has expired.
+func WithBootstrapAuth(bootstrap api.AuthMethod) CertLoginOption {
+	return func(a *CertAuth) error {
+		if bootstrap == nil {
+			return errors.New("empty bootstrap auth method passed")
+		}
+		a.bootstrap = bootstrap
+		return nil
+	}
+}
+
+func (a *CertAuth) Login(ctx context.Context, client *api.Client) (*api.Secret, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	certPem, cert, err := a.readCertificate()
+	if err != nil {
+		if a.bootstrap == nil {
+			return nil, err
+		}
+		log.Warn().Str("subcomponent", "auth-cert").Str("component", "vault").Err(err).Msg("No valid certificate available, logging in using bootstrap auth method")
+		a.loggedInCert = nil
+		return a.bootstrap.Login(ctx, client)
+	}
+
+	loginClient, transport, err := newCertClient(client, cert)
+	if err != nil {
+		return nil, err
+	}
+	defer transport.CloseIdleConnections()
+
+	loginData := map[string]interface{}{}
+	if a.role != "" {
+		loginData["name"] = a.role
+	}
+
+	path := fmt.Sprintf("auth/%s/login", a.mountPath)
+	resp, err := loginClient.Logical().WriteWithContext(ctx, path, loginData)
+	if err != nil {
+		return nil, fmt.Errorf("unable to log in with cert auth: %w", err)
+	}
+
+	a.loggedInCert = certPem
+	return resp, nil
+}
+
+// CredentialsRotated returns true if a valid certificate is available that differs from the one used for the latest
+// login, i.e. it has been renewed or issued for the first time after logging in using the bootstrap auth method.
+func (a *CertAuth) CredentialsRotated() bool {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	certPem, _, err := a.readCertificate()
+	if err != nil {
+		return false
+	}
+
+	return !bytes.Equal(certPem, a.loggedInCert)
+}
+
+// readCertificate reads the certificate and key and returns an error if the certificate is not valid at this point
+// in time.
+func (a *CertAuth) readCertificate() ([]byte, tls.Certificate, error) {
+	certPem, err := os.ReadFile(a.certFile)
+	if err != nil {
+		return nil, tls.Certificate{}, fmt.Errorf("unable to read certificate: %w", err)
+	}
+
+	keyPem, err := os.ReadFile(a.keyFile)
+	if err != nil {
+		return nil, tls.Certificate{}, fmt.Errorf("unable to read key: %w", err)
+	}
+
+	cert, err := tls.X509KeyPair(certPem, keyPem)
+	if err != nil {
+		return nil, tls.Certificate{}, fmt.Errorf("unable to parse certificate: %w", err)
+	}
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return nil, tls.Certificate{}, fmt.Errorf("unable to parse certificate: %w", err)
+	}
+
+	now := time.Now()
+	if now.Before(leaf.NotBefore) || now.After(leaf.NotAfter) {
+		return nil, tls.Certificate{}, fmt.Errorf("certificate %q is not valid at this time", a.certFile)
+	}
+
+	return certPem, cert, nil
+}
+
+// newCertClient returns a copy of the client that presents the given certificate. A dedicated transport is used, so
+// the certificate is not presented by the original client.
+func newCertClient(client *api.Client, cert tls.Certificate) (*api.Client, *http.Transport, error) {
+	conf := client.CloneConfig()
+	transport, ok := conf.HttpClient.Transport.(*http.Transport)
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported transport type %T", conf.HttpClient.Transport)
+	}
+
+	transport = transport.Clone()
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	transport.TLSClientConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+		return &cert, nil
+	}
+	conf.HttpClient.Transport = transport
+
+	loginClient, err := api.NewClient(conf)
+	if err != nil {
+		return nil, nil, err
+	}
+	loginClient.SetHeaders(client.Headers())
+	loginClient.ClearToken()
+
+	return loginClient, transport, nil
+}
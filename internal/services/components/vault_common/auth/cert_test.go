@@ -0,0 +1,164 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/vault/api"
+)
+
+func writeCertificate(t *testing.T, dir, commonName string, notAfter time.Time) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+type staticAuth struct {
+	logins int
+}
+
+func (s *staticAuth) Login(_ context.Context, _ *api.Client) (*api.Secret, error) {
+	s.logins++
+	return &api.Secret{Auth: &api.SecretAuth{ClientToken: "bootstrap"}}, nil
+}
+
+func TestCertAuth_Login(t *testing.T) {
+	var gotCommonName, gotRole string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/auth/cert/login" || len(r.TLS.PeerCertificates) == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		gotCommonName = r.TLS.PeerCertificates[0].Subject.CommonName
+
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		gotRole, _ = body["name"].(string)
+
+		_, _ = w.Write([]byte(`{"auth": {"client_token": "cert-token"}}`))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert, MinVersion: tls.VersionTLS12}
+	server.StartTLS()
+	defer server.Close()
+
+	conf := api.DefaultConfig()
+	conf.Address = server.URL
+	conf.HttpClient = server.Client()
+	client, err := api.NewClient(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	bootstrap := &staticAuth{}
+
+	auth, err := NewCertAuth(certFile, keyFile, WithCertRole("web"), WithBootstrapAuth(bootstrap))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("bootstrap without certificate", func(t *testing.T) {
+		resp, err := auth.Login(context.Background(), client)
+		if err != nil {
+			t.Fatalf("Login() error = %v", err)
+		}
+		if resp.Auth.ClientToken != "bootstrap" || bootstrap.logins != 1 {
+			t.Errorf("Login() expected bootstrap login, got token %q", resp.Auth.ClientToken)
+		}
+		if auth.CredentialsRotated() {
+			t.Error("CredentialsRotated() expected false without certificate")
+		}
+	})
+
+	t.Run("bootstrap with expired certificate", func(t *testing.T) {
+		writeCertificate(t, dir, "expired", time.Now().Add(-time.Minute))
+		if auth.CredentialsRotated() {
+			t.Error("CredentialsRotated() expected false for expired certificate")
+		}
+		if _, err := auth.Login(context.Background(), client); err != nil {
+			t.Fatalf("Login() error = %v", err)
+		}
+		if bootstrap.logins != 2 {
+			t.Errorf("Login() expected bootstrap login")
+		}
+	})
+
+	t.Run("certificate issued", func(t *testing.T) {
+		writeCertificate(t, dir, "first", time.Now().Add(time.Hour))
+		if !auth.CredentialsRotated() {
+			t.Error("CredentialsRotated() expected true after certificate has been issued")
+		}
+		resp, err := auth.Login(context.Background(), client)
+		if err != nil {
+			t.Fatalf("Login() error = %v", err)
+		}
+		if resp.Auth.ClientToken != "cert-token" || gotCommonName != "first" || gotRole != "web" {
+			t.Errorf("Login() got token %q, common name %q, role %q", resp.Auth.ClientToken, gotCommonName, gotRole)
+		}
+		if auth.CredentialsRotated() {
+			t.Error("CredentialsRotated() expected false after login")
+		}
+		if client.Token() == "cert-token" {
+			t.Error("Login() must not modify the original client")
+		}
+	})
+
+	t.Run("certificate rotated", func(t *testing.T) {
+		writeCertificate(t, dir, "second", time.Now().Add(time.Hour))
+		if !auth.CredentialsRotated() {
+			t.Error("CredentialsRotated() expected true after certificate has been rotated")
+		}
+		if _, err := auth.Login(context.Background(), client); err != nil {
+			t.Fatalf("Login() error = %v", err)
+		}
+		if gotCommonName != "second" {
+			t.Errorf("Login() got common name %q, want %q", gotCommonName, "second")
+		}
+	})
+}
@@ -0,0 +1,207 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/vault/api"
+)
+
+const (
+	defaultJwtMountPath = "jwt"
+
+	maxJwtBytes       = 16 * 1024
+	jwtFetchTimeout   = 10 * time.Second
+	jwtAuthMethodName = "jwt"
+)
+
+// JwtSource returns the JWT that is used to log in. It is invoked for every login, as JWTs are usually short-lived and
+// rotated by their issuer.
+type JwtSource interface {
+	Jwt(ctx context.Context) (string, error)
+}
+
+// JwtAuth logs in using the JWT/OIDC auth method or, with a different mount path, the Kubernetes auth method, which
+// both accept a role and a JWT.
+type JwtAuth struct {
+	name      string
+	mountPath string
+	role      string
+	source    JwtSource
+	mutex     sync.Mutex
+}
+
+var _ api.AuthMethod = (*JwtAuth)(nil)
+
+type JwtLoginOption func(a *JwtAuth) error
+
+// NewJwtAuth initializes a new JWT auth method that logs in using the given role and the JWT returned by source.
+//
+// Supported options: WithJwtMountPath
+func NewJwtAuth(role string, source JwtSource, opts ...JwtLoginOption) (*JwtAuth, error) {
+	return newJwtAuth(jwtAuthMethodName, defaultJwtMountPath, role, source, opts...)
+}
+
+func newJwtAuth(name, mountPath, role string, source JwtSource, opts ...JwtLoginOption) (*JwtAuth, error) {
+	if role == "" {
+		return nil, errors.New("no role provided for login")
+	}
+
+	if source == nil {
+		return nil, errors.New("no jwt source provided for login")
+	}
+
+	a := &JwtAuth{
+		name:      name,
+		mountPath: mountPath,
+		role:      role,
+		source:    source,
+	}
+
+	for _, opt := range opts {
+		if err := opt(a); err != nil {
+			return nil, fmt.Errorf("error with login option: %w", err)
+		}
+	}
+
+	return a, nil
+}
+
+func WithJwtMountPath(mountPath string) JwtLoginOption {
+	return func(a *JwtAuth) error {
+		a.mountPath = mountPath
+		return nil
+	}
+}
+
+func (a *JwtAuth) Login(ctx context.Context, client *api.Client) (*api.Secret, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	jwt, err := a.source.Jwt(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error reading jwt: %w", err)
+	}
+
+	loginData := map[string]interface{}{
+		"role": a.role,
+		"jwt":  jwt,
+	}
+
+	path := fmt.Sprintf("auth/%s/login", a.mountPath)
+	resp, err := client.Logical().WriteWithContext(ctx, path, loginData)
+	if err != nil {
+		return nil, fmt.Errorf("unable to log in with %s auth: %w", a.name, err)
+	}
+
+	return resp, nil
+}
+
+// JwtFile reads the JWT from a file, e.g. a projected service account token.
+type JwtFile struct {
+	file string
+}
+
+func NewJwtFile(file string) (*JwtFile, error) {
+	if file == "" {
+		return nil, errors.New("no jwt file provided")
+	}
+
+	return &JwtFile{file: file}, nil
+}
+
+func (s *JwtFile) Jwt(_ context.Context) (string, error) {
+	file, err := os.Open(s.file)
+	if err != nil {
+		return "", fmt.Errorf("unable to open file containing jwt: %w", err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxJwtBytes))
+	if err != nil {
+		return "", fmt.Errorf("unable to read jwt: %w", err)
+	}
+
+	jwt := strings.TrimSpace(string(data))
+	if jwt == "" {
+		return "", fmt.Errorf("file %q does not contain a jwt", s.file)
+	}
+	return jwt, nil
+}
+
+// JwtEndpoint fetches the JWT from a local identity endpoint, e.g. the metadata server of a cloud provider. The
+// response body is either the JWT itself or a JSON object containing it as "token" or "access_token".
+type JwtEndpoint struct {
+	client  *http.Client
+	url     string
+	headers map[string]string
+}
+
+func NewJwtEndpoint(url string, headers map[string]string) (*JwtEndpoint, error) {
+	if url == "" {
+		return nil, errors.New("no jwt url provided")
+	}
+
+	return &JwtEndpoint{
+		client:  &http.Client{Timeout: jwtFetchTimeout},
+		url:     url,
+		headers: headers,
+	}, nil
+}
+
+func (s *JwtEndpoint) Jwt(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return "", err
+	}
+	for key, val := range s.headers {
+		req.Header.Set(key, val)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("unable to fetch jwt: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unable to fetch jwt: wrong status code, expected 200 got %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxJwtBytes))
+	if err != nil {
+		return "", fmt.Errorf("unable to read jwt: %w", err)
+	}
+
+	jwt := strings.TrimSpace(string(data))
+	if strings.HasPrefix(jwt, "{") {
+		var body struct {
+			Token       string `json:"token"`
+			AccessToken string `json:"access_token"`
+		}
+		if err := json.Unmarshal(data, &body); err != nil {
+			return "", fmt.Errorf("unable to parse jwt response: %w", err)
+		}
+		jwt = body.Token
+		if jwt == "" {
+			jwt = body.AccessToken
+		}
+	}
+
+	if jwt == "" {
+		return "", errors.New("empty jwt received")
+	}
+	return jwt, nil
+}
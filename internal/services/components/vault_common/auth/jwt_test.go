@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/vault/api"
+)
+
+func TestJwtEndpoint_Jwt(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		status  int
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "plain jwt",
+			body:   "eyJhbGciOi.payload.signature\n",
+			status: http.StatusOK,
+			want:   "eyJhbGciOi.payload.signature",
+		},
+		{
+			name:   "json token",
+			body:   `{"token": "eyJhbGciOi.payload.signature"}`,
+			status: http.StatusOK,
+			want:   "eyJhbGciOi.payload.signature",
+		},
+		{
+			name:   "json access_token",
+			body:   `{"access_token": "eyJhbGciOi.payload.signature", "expires_in": "3599"}`,
+			status: http.StatusOK,
+			want:   "eyJhbGciOi.payload.signature",
+		},
+		{
+			name:    "empty json",
+			body:    `{}`,
+			status:  http.StatusOK,
+			wantErr: true,
+		},
+		{
+			name:    "error status",
+			body:    "forbidden",
+			status:  http.StatusForbidden,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Metadata-Flavor") != "Google" {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			source, err := NewJwtEndpoint(server.URL, map[string]string{"Metadata-Flavor": "Google"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := source.Jwt(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Jwt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Jwt() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKubernetesAuth_Login(t *testing.T) {
+	var gotPath string
+	var gotBody map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		_, _ = w.Write([]byte(`{"auth": {"client_token": "k8s-token"}}`))
+	}))
+	defer server.Close()
+
+	conf := api.DefaultConfig()
+	conf.Address = server.URL
+	client, err := api.NewClient(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	auth, err := NewKubernetesAuth("sc-agent", tokenFile, WithJwtMountPath("k0s"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := auth.Login(context.Background(), client); err == nil {
+		t.Fatal("Login() expected error for missing token file")
+	}
+
+	// the token is re-read for every login
+	for _, token := range []string{"first", "rotated"} {
+		if err := os.WriteFile(tokenFile, []byte(token+"\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := auth.Login(context.Background(), client)
+		if err != nil {
+			t.Fatalf("Login() error = %v", err)
+		}
+		if resp.Auth.ClientToken != "k8s-token" {
+			t.Errorf("Login() got token %q", resp.Auth.ClientToken)
+		}
+		if gotPath != "/v1/auth/k0s/login" {
+			t.Errorf("Login() got path %q", gotPath)
+		}
+		if gotBody["role"] != "sc-agent" || gotBody["jwt"] != token {
+			t.Errorf("Login() got body %v", gotBody)
+		}
+	}
+}
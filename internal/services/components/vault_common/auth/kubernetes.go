@@ -0,0 +1,26 @@
+package auth
+
+const (
+	defaultKubernetesMountPath = "kubernetes"
+	kubernetesAuthMethodName   = "kubernetes"
+
+	// DefaultServiceAccountTokenFile is the file the service account token is mounted at in a pod.
+	DefaultServiceAccountTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token" // #nosec G101
+)
+
+// NewKubernetesAuth initializes a new Kubernetes auth method that logs in using the given role and the service account
+// token read from tokenFile. The token is re-read for every login, so rotated projected tokens are picked up.
+//
+// Supported options: WithJwtMountPath
+func NewKubernetesAuth(role, tokenFile string, opts ...JwtLoginOption) (*JwtAuth, error) {
+	if tokenFile == "" {
+		tokenFile = DefaultServiceAccountTokenFile
+	}
+
+	source, err := NewJwtFile(tokenFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return newJwtAuth(kubernetesAuthMethodName, defaultKubernetesMountPath, role, source, opts...)
+}
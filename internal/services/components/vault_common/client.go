@@ -3,22 +3,57 @@ package vault_common
 import (
 	"context"
 	"errors"
-	"sync"
 
 	"github.com/hashicorp/vault/api"
 	"github.com/rs/zerolog/log"
+	domain "github.com/soerenschneider/sc-agent/internal/domain/vault"
 )
 
 type VaultCommon struct {
 	client *api.Client
 	auth   api.AuthMethod
 	name   string
+	// authMethod is the name of the configured auth method, it is only used to report the status of the client
+	authMethod string
 
 	tokenRenewer           *TokenRenewer
 	approleSecretIdRotator *ApproleSecretIdRotatorService
+	endpointSelector       *EndpointSelector
 }
 
-func NewVaultClient(auth api.AuthMethod, client *api.Client, renewer *TokenRenewer, approleSecretIdRotator *ApproleSecretIdRotatorService) (*VaultCommon, error) {
+type VaultCommonOpt func(v *VaultCommon) error
+
+// WithEndpointSelector enables failover between multiple addresses of a Vault cluster.
+func WithEndpointSelector(selector *EndpointSelector) VaultCommonOpt {
+	return func(v *VaultCommon) error {
+		if selector == nil {
+			return errors.New("empty endpoint selector passed")
+		}
+		v.endpointSelector = selector
+		return nil
+	}
+}
+
+// WithName sets the name the client is identified with.
+func WithName(name string) VaultCommonOpt {
+	return func(v *VaultCommon) error {
+		if name == "" {
+			return errors.New("empty name passed")
+		}
+		v.name = name
+		return nil
+	}
+}
+
+// WithAuthMethodName sets the name of the auth method that is reported in the status of the client.
+func WithAuthMethodName(authMethod string) VaultCommonOpt {
+	return func(v *VaultCommon) error {
+		v.authMethod = authMethod
+		return nil
+	}
+}
+
+func NewVaultClient(auth api.AuthMethod, client *api.Client, renewer *TokenRenewer, approleSecretIdRotator *ApproleSecretIdRotatorService, opts ...VaultCommonOpt) (*VaultCommon, error) {
 	if auth == nil {
 		return nil, errors.New("empty authmethod passed")
 	}
@@ -27,13 +62,21 @@ func NewVaultClient(auth api.AuthMethod, client *api.Client, renewer *TokenRenew
 		return nil, errors.New("empty client passed")
 	}
 
-	return &VaultCommon{
+	ret := &VaultCommon{
 		client: client,
 		auth:   auth,
 
 		tokenRenewer:           renewer,
 		approleSecretIdRotator: approleSecretIdRotator,
-	}, nil
+	}
+
+	for _, opt := range opts {
+		if err := opt(ret); err != nil {
+			return nil, err
+		}
+	}
+
+	return ret, nil
 }
 
 func (v *VaultCommon) Client() *api.Client {
@@ -44,20 +87,78 @@ func (v *VaultCommon) Auth() api.AuthMethod {
 	return v.auth
 }
 
-func (v *VaultCommon) StartTokenRenewer(ctx context.Context, wg *sync.WaitGroup, vaultFatalError chan error) {
+// StartEndpointSelection selects the healthiest endpoint of the Vault cluster and keeps monitoring all endpoints. It
+// returns after the initial selection.
+func (v *VaultCommon) StartEndpointSelection(ctx context.Context) {
+	if v.endpointSelector == nil {
+		return
+	}
+
+	v.endpointSelector.Start(ctx)
+}
+
+func (v *VaultCommon) StartTokenRenewer(ctx context.Context) {
 	if v.tokenRenewer == nil {
 		log.Warn().Str(logComponent, "vault").Str("name", v.name).Msg("Token renewal not enabled on this client")
 		return
 	}
 
-	v.tokenRenewer.StartTokenRenewal(ctx, wg, vaultFatalError)
+	v.tokenRenewer.StartTokenRenewal(ctx)
+}
+
+// Ready returns a channel that is closed after the first successful login. Clients without token renewal are always
+// considered ready.
+func (v *VaultCommon) Ready() <-chan struct{} {
+	if v.tokenRenewer == nil {
+		ready := make(chan struct{})
+		close(ready)
+		return ready
+	}
+
+	return v.tokenRenewer.Ready()
 }
 
+// StartApproleSecretIdRotation starts rotating the secret_id as soon as the client has logged in.
 func (v *VaultCommon) StartApproleSecretIdRotation(ctx context.Context) {
 	if v.approleSecretIdRotator == nil {
 		log.Warn().Str(logComponent, "vault").Str("name", v.name).Msg("ApproleSecretIdRotation not enabled on this client")
 		return
 	}
 
+	select {
+	case <-ctx.Done():
+		return
+	case <-v.Ready():
+	}
+
 	v.approleSecretIdRotator.StartSecretIdRotation(ctx)
 }
+
+// Status returns the health of the client's token and, if enabled, the state of the AppRole secret_id rotation.
+func (v *VaultCommon) Status() domain.ClientStatus {
+	ret := domain.ClientStatus{
+		Id:         v.name,
+		AuthMethod: v.authMethod,
+		Address:    v.client.Address(),
+	}
+
+	if v.tokenRenewer != nil {
+		ret.Token = v.tokenRenewer.Status()
+	}
+
+	if v.approleSecretIdRotator != nil {
+		status := v.approleSecretIdRotator.Status()
+		ret.SecretId = &status
+	}
+
+	return ret
+}
+
+// RotateSecretId rotates the AppRole secret_id immediately.
+func (v *VaultCommon) RotateSecretId() error {
+	if v.approleSecretIdRotator == nil {
+		return domain.ErrSecretIdRotationDisabled
+	}
+
+	return v.approleSecretIdRotator.RotateSecretId()
+}
@@ -0,0 +1,60 @@
+package vault_common
+
+import (
+	"errors"
+	"sort"
+
+	domain "github.com/soerenschneider/sc-agent/internal/domain/vault"
+)
+
+// Clients reports the status of all configured Vault clients.
+type Clients struct {
+	clients map[string]*VaultCommon
+}
+
+func NewClients(clients map[string]*VaultCommon) (*Clients, error) {
+	if len(clients) == 0 {
+		return nil, errors.New("no clients passed")
+	}
+
+	return &Clients{
+		clients: clients,
+	}, nil
+}
+
+func (c *Clients) GetClients() ([]domain.ClientStatus, error) {
+	ids := make([]string, 0, len(c.clients))
+	for id := range c.clients {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	ret := make([]domain.ClientStatus, 0, len(ids))
+	for _, id := range ids {
+		ret = append(ret, c.clients[id].Status())
+	}
+
+	return ret, nil
+}
+
+func (c *Clients) GetClient(id string) (domain.ClientStatus, error) {
+	client, found := c.clients[id]
+	if !found {
+		return domain.ClientStatus{}, domain.ErrVaultClientNotFound
+	}
+
+	return client.Status(), nil
+}
+
+func (c *Clients) RotateSecretId(id string) (domain.ClientStatus, error) {
+	client, found := c.clients[id]
+	if !found {
+		return domain.ClientStatus{}, domain.ErrVaultClientNotFound
+	}
+
+	if err := client.RotateSecretId(); err != nil {
+		return domain.ClientStatus{}, err
+	}
+
+	return client.Status(), nil
+}
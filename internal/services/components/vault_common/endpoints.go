@@ -0,0 +1,234 @@
+package vault_common
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/rs/zerolog/log"
+	"github.com/soerenschneider/sc-agent/internal/metrics"
+)
+
+const (
+	endpointSelectorComponent  = "endpoint-selector"
+	defaultHealthCheckInterval = 30 * time.Second
+	healthCheckTimeout         = 5 * time.Second
+)
+
+const (
+	endpointUnavailable endpointState = iota
+	endpointStandby
+	endpointPerformanceStandby
+	endpointActive
+)
+
+// endpointState ranks the health of a Vault node, higher values are preferred.
+type endpointState int
+
+func (s endpointState) String() string {
+	switch s {
+	case endpointActive:
+		return "active"
+	case endpointPerformanceStandby:
+		return "performance_standby"
+	case endpointStandby:
+		return "standby"
+	default:
+		return "unavailable"
+	}
+}
+
+type healthCheckFunc func(ctx context.Context, address string) (endpointState, error)
+
+// EndpointSelector checks the health of all addresses of a Vault cluster and points the client to the healthiest one.
+// Active nodes are preferred over performance standby nodes, which are preferred over standby nodes, which forward all
+// requests to the active node. Among nodes in the same state, the order of the configured addresses is respected.
+type EndpointSelector struct {
+	client      *api.Client
+	clientName  string
+	addresses   []string
+	interval    time.Duration
+	healthCheck healthCheckFunc
+
+	active string
+	mutex  sync.Mutex
+	once   sync.Once
+}
+
+type EndpointSelectorOpt func(e *EndpointSelector) error
+
+func NewEndpointSelector(client *api.Client, clientName string, addresses []string, opts ...EndpointSelectorOpt) (*EndpointSelector, error) {
+	if client == nil {
+		return nil, errors.New("empty client passed")
+	}
+
+	if len(addresses) == 0 {
+		return nil, errors.New("no addresses passed")
+	}
+
+	normalized := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		normalized = append(normalized, strings.TrimSuffix(address, "/"))
+	}
+
+	ret := &EndpointSelector{
+		client:     client,
+		clientName: clientName,
+		addresses:  normalized,
+		interval:   defaultHealthCheckInterval,
+		active:     strings.TrimSuffix(client.Address(), "/"),
+	}
+	ret.healthCheck = ret.checkHealth
+
+	for _, opt := range opts {
+		if err := opt(ret); err != nil {
+			return nil, err
+		}
+	}
+
+	return ret, nil
+}
+
+func WithHealthCheckInterval(interval time.Duration) EndpointSelectorOpt {
+	return func(e *EndpointSelector) error {
+		if interval < time.Second {
+			return errors.New("health check interval must be at least one second")
+		}
+		e.interval = interval
+		return nil
+	}
+}
+
+// Active returns the address the client currently uses.
+func (e *EndpointSelector) Active() string {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return e.active
+}
+
+// Start selects an endpoint and keeps checking the health of all endpoints periodically until the context is
+// canceled. The initial selection is done synchronously, so logins use a healthy endpoint.
+func (e *EndpointSelector) Start(ctx context.Context) {
+	e.once.Do(func() {
+		e.SelectEndpoint(ctx)
+
+		go func() {
+			ticker := time.NewTicker(e.interval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					e.SelectEndpoint(ctx)
+				}
+			}
+		}()
+	})
+}
+
+// SelectEndpoint checks the health of all endpoints and switches the client to the healthiest one. If no endpoint is
+// healthy, the client keeps using its current endpoint.
+func (e *EndpointSelector) SelectEndpoint(ctx context.Context) string {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	best := ""
+	bestState := endpointUnavailable
+	for _, address := range e.addresses {
+		state, err := e.healthCheck(ctx, address)
+		if err != nil {
+			log.Warn().Str(logComponent, "vault").Str(logSubComponent, endpointSelectorComponent).Str("client", e.clientName).Str("address", address).Err(err).Msg("Health check failed")
+		}
+
+		healthy := 0.
+		if state != endpointUnavailable {
+			healthy = 1
+		}
+		metrics.VaultEndpointHealthy.WithLabelValues(e.clientName, address).Set(healthy)
+
+		if state > bestState {
+			best = address
+			bestState = state
+		}
+	}
+
+	if best == "" {
+		log.Error().Str(logComponent, "vault").Str(logSubComponent, endpointSelectorComponent).Str("client", e.clientName).Str("address", e.active).Msg("No healthy endpoint available, keeping current endpoint")
+		e.updateActiveMetric()
+		return e.active
+	}
+
+	if best != e.active {
+		if err := e.client.SetAddress(best); err != nil {
+			log.Error().Str(logComponent, "vault").Str(logSubComponent, endpointSelectorComponent).Str("client", e.clientName).Str("address", best).Err(err).Msg("Could not switch endpoint")
+			return e.active
+		}
+		log.Warn().Str(logComponent, "vault").Str(logSubComponent, endpointSelectorComponent).Str("client", e.clientName).Str("address", best).Str("previous", e.active).Str("state", bestState.String()).Msg("Switched to different endpoint")
+		metrics.VaultEndpointFailovers.WithLabelValues(e.clientName).Inc()
+		e.active = best
+	}
+
+	e.updateActiveMetric()
+	return e.active
+}
+
+func (e *EndpointSelector) updateActiveMetric() {
+	for _, address := range e.addresses {
+		active := 0.
+		if address == e.active {
+			active = 1
+		}
+		metrics.VaultEndpointActive.WithLabelValues(e.clientName, address).Set(active)
+	}
+}
+
+func (e *EndpointSelector) checkHealth(ctx context.Context, address string) (endpointState, error) {
+	client, err := api.NewClient(e.client.CloneConfig())
+	if err != nil {
+		return endpointUnavailable, err
+	}
+	client.ClearToken()
+	client.SetMaxRetries(0)
+	if err := client.SetAddress(address); err != nil {
+		return endpointUnavailable, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	health, err := client.Sys().HealthWithContext(ctx)
+	if err != nil {
+		return endpointUnavailable, err
+	}
+
+	return evaluateHealth(health), nil
+}
+
+func evaluateHealth(health *api.HealthResponse) endpointState {
+	if health == nil || !health.Initialized || health.Sealed {
+		return endpointUnavailable
+	}
+
+	if health.RemovedFromCluster != nil && *health.RemovedFromCluster {
+		return endpointUnavailable
+	}
+
+	if health.PerformanceStandby {
+		return endpointPerformanceStandby
+	}
+
+	if health.Standby {
+		// standby nodes forward requests to the active node, which is not possible without a healthy connection
+		if health.HAConnectionHealthy != nil && !*health.HAConnectionHealthy {
+			return endpointUnavailable
+		}
+		return endpointStandby
+	}
+
+	return endpointActive
+}
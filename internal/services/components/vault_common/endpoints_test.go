@@ -0,0 +1,150 @@
+package vault_common
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/vault/api"
+)
+
+func TestEndpointSelector_SelectEndpoint(t *testing.T) {
+	addresses := []string{"https://vault-1:8200", "https://vault-2:8200", "https://vault-3:8200"}
+
+	tests := []struct {
+		name   string
+		states map[string]endpointState
+		want   string
+	}{
+		{
+			name: "prefer active node",
+			states: map[string]endpointState{
+				"https://vault-1:8200": endpointStandby,
+				"https://vault-2:8200": endpointPerformanceStandby,
+				"https://vault-3:8200": endpointActive,
+			},
+			want: "https://vault-3:8200",
+		},
+		{
+			name: "prefer configured order",
+			states: map[string]endpointState{
+				"https://vault-1:8200": endpointStandby,
+				"https://vault-2:8200": endpointStandby,
+			},
+			want: "https://vault-1:8200",
+		},
+		{
+			name: "fail over to standby",
+			states: map[string]endpointState{
+				"https://vault-2:8200": endpointStandby,
+			},
+			want: "https://vault-2:8200",
+		},
+		{
+			name:   "keep current endpoint if none is healthy",
+			states: map[string]endpointState{},
+			want:   "https://vault-1:8200",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := api.DefaultConfig()
+			conf.Address = addresses[0]
+			client, err := api.NewClient(conf)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			selector, err := NewEndpointSelector(client, "test", addresses)
+			if err != nil {
+				t.Fatal(err)
+			}
+			selector.healthCheck = func(_ context.Context, address string) (endpointState, error) {
+				state, found := tt.states[address]
+				if !found {
+					return endpointUnavailable, errors.New("connection refused")
+				}
+				return state, nil
+			}
+
+			if got := selector.SelectEndpoint(context.Background()); got != tt.want {
+				t.Errorf("SelectEndpoint() = %q, want %q", got, tt.want)
+			}
+			if client.Address() != tt.want {
+				t.Errorf("SelectEndpoint() client address = %q, want %q", client.Address(), tt.want)
+			}
+		})
+	}
+}
+
+func TestEndpointSelector_checkHealth(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want endpointState
+	}{
+		{
+			name: "active",
+			body: `{"initialized": true, "sealed": false, "standby": false}`,
+			want: endpointActive,
+		},
+		{
+			name: "performance standby",
+			body: `{"initialized": true, "sealed": false, "standby": true, "performance_standby": true}`,
+			want: endpointPerformanceStandby,
+		},
+		{
+			name: "standby",
+			body: `{"initialized": true, "sealed": false, "standby": true}`,
+			want: endpointStandby,
+		},
+		{
+			name: "standby without connection to active node",
+			body: `{"initialized": true, "sealed": false, "standby": true, "ha_connection_healthy": false}`,
+			want: endpointUnavailable,
+		},
+		{
+			name: "sealed",
+			body: `{"initialized": true, "sealed": true}`,
+			want: endpointUnavailable,
+		},
+		{
+			name: "not initialized",
+			body: `{"initialized": false, "sealed": true}`,
+			want: endpointUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/sys/health" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				w.WriteHeader(299)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client, err := api.NewClient(api.DefaultConfig())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			selector, err := NewEndpointSelector(client, "test", []string{server.URL})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := selector.checkHealth(context.Background(), server.URL)
+			if err != nil {
+				t.Fatalf("checkHealth() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("checkHealth() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,26 +4,86 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/cenkalti/backoff/v4"
 	vault "github.com/hashicorp/vault/api"
 	"github.com/rs/zerolog/log"
+	domain "github.com/soerenschneider/sc-agent/internal/domain/vault"
 	"github.com/soerenschneider/sc-agent/internal/metrics"
 )
 
 const (
 	vaultTokenRenewerComponent = "token-renewer"
+
+	credentialsRotationCheckInterval = time.Minute
+
+	// tokenRenewIncrement is the TTL in seconds that is requested when renewing the token
+	tokenRenewIncrement = 3600
+
+	loginRetryInitialInterval = 5 * time.Second
+	loginRetryMaxInterval     = 5 * time.Minute
 )
 
+// rotatableCredentials is implemented by auth methods whose credentials can be rotated while a token is valid, e.g.
+// the certificate used by the cert auth method. A new login is performed as soon as rotated credentials are detected.
+type rotatableCredentials interface {
+	CredentialsRotated() bool
+}
+
 type TokenRenewer struct {
 	client     *vault.Client
 	clientName string
 	auth       vault.AuthMethod
 	once       sync.Once
+
+	// ready is closed after the first successful login
+	ready     chan struct{}
+	readyOnce sync.Once
+
+	// sinks are written after each login and each renewal of the token
+	sinks []*TokenSink
+	// cache persists the token after each login, so it can be used again after a restart
+	cache TokenCacheStorage
+
+	status      domain.TokenStatus
+	statusMutex sync.Mutex
+}
+
+type TokenCacheStorage interface {
+	Read() ([]byte, error)
+	Write([]byte) error
+}
+
+type TokenRenewerOpt func(t *TokenRenewer) error
+
+// WithTokenCache persists the token after each login. On startup, the cached token is renewed instead of performing a
+// new login, as long as it is still valid. The cache should be encrypted or stored in the kernel keyring.
+func WithTokenCache(cache TokenCacheStorage) TokenRenewerOpt {
+	return func(t *TokenRenewer) error {
+		if cache == nil {
+			return errors.New("empty token cache passed")
+		}
+		t.cache = cache
+		return nil
+	}
+}
+
+func WithTokenSinks(sinks ...*TokenSink) TokenRenewerOpt {
+	return func(t *TokenRenewer) error {
+		for _, sink := range sinks {
+			if sink == nil {
+				return errors.New("empty token sink passed")
+			}
+		}
+		t.sinks = append(t.sinks, sinks...)
+		return nil
+	}
 }
 
-func NewTokenRenewer(client *vault.Client, auth vault.AuthMethod, clientName string) (*TokenRenewer, error) {
+func NewTokenRenewer(client *vault.Client, auth vault.AuthMethod, clientName string, opts ...TokenRenewerOpt) (*TokenRenewer, error) {
 	if client == nil {
 		return nil, errors.New("empty client passed")
 	}
@@ -32,64 +92,230 @@ func NewTokenRenewer(client *vault.Client, auth vault.AuthMethod, clientName str
 		return nil, errors.New("empty authmethod passed")
 	}
 
-	return &TokenRenewer{
+	ret := &TokenRenewer{
 		client:     client,
 		auth:       auth,
 		clientName: clientName,
-	}, nil
+		ready:      make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		if err := opt(ret); err != nil {
+			return nil, err
+		}
+	}
+
+	return ret, nil
+}
+
+// writeSinks writes the current token to all configured sinks. Errors are only logged, as they must not interfere
+// with the token lifecycle.
+func (t *TokenRenewer) writeSinks(ctx context.Context) {
+	for _, sink := range t.sinks {
+		if err := sink.Write(ctx, t.client); err != nil {
+			metrics.VaultTokenSinkErrors.WithLabelValues(t.clientName, sink.Name()).Inc()
+			log.Error().Str(logComponent, "vault").Str(logSubComponent, vaultTokenRenewerComponent).Str("client", t.clientName).Str("sink", sink.Name()).Err(err).Msg("could not write token to sink")
+			continue
+		}
+		metrics.VaultTokenSinkWrites.WithLabelValues(t.clientName, sink.Name()).Inc()
+	}
+}
+
+// Status returns the health of the token managed by the renewer.
+func (t *TokenRenewer) Status() domain.TokenStatus {
+	t.statusMutex.Lock()
+	defer t.statusMutex.Unlock()
+	return t.status
+}
+
+func (t *TokenRenewer) recordToken(auth *vault.SecretAuth, isLogin bool) {
+	t.statusMutex.Lock()
+	defer t.statusMutex.Unlock()
+
+	now := time.Now()
+	if isLogin {
+		t.status.LastLogin = now
+	} else {
+		t.status.LastRenewal = now
+	}
+
+	if auth == nil {
+		return
+	}
+	t.status.Ttl = time.Duration(auth.LeaseDuration) * time.Second
+	t.status.Expiration = time.Time{}
+	if t.status.Ttl > 0 {
+		t.status.Expiration = now.Add(t.status.Ttl)
+	}
+	t.status.Renewable = auth.Renewable
+}
+
+func (t *TokenRenewer) recordError(err error) {
+	t.statusMutex.Lock()
+	defer t.statusMutex.Unlock()
+
+	t.status.LastError = err.Error()
+	t.status.LastErrorTime = time.Now()
+}
+
+// restoreCachedToken tries to continue using the token that has been cached by a previous run. The cached token is
+// only used if it is still valid and can be renewed, otherwise nil is returned and a new login is required.
+func (t *TokenRenewer) restoreCachedToken(ctx context.Context) *vault.Secret {
+	if t.cache == nil {
+		return nil
+	}
+
+	data, err := t.cache.Read()
+	if err != nil {
+		log.Debug().Str(logComponent, "vault").Str(logSubComponent, vaultTokenRenewerComponent).Str("client", t.clientName).Err(err).Msg("No cached token available")
+		return nil
+	}
+
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return nil
+	}
+
+	t.client.SetToken(token)
+	secret, err := t.renewCachedToken(ctx)
+	if err != nil {
+		t.client.ClearToken()
+		log.Info().Str(logComponent, "vault").Str(logSubComponent, vaultTokenRenewerComponent).Str("client", t.clientName).Err(err).Msg("Can not use cached token")
+		return nil
+	}
+
+	log.Info().Str(logComponent, "vault").Str(logSubComponent, vaultTokenRenewerComponent).Str("client", t.clientName).Int("token_ttl", secret.Auth.LeaseDuration).Msg("Renewed cached token, skipping login")
+	metrics.VaultTokenCacheRestores.WithLabelValues(t.clientName).Inc()
+	return secret
+}
+
+func (t *TokenRenewer) renewCachedToken(ctx context.Context) (*vault.Secret, error) {
+	lookup, err := t.client.Auth().Token().LookupSelfWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not lookup token: %w", err)
+	}
+
+	renewable, err := lookup.TokenIsRenewable()
+	if err != nil {
+		return nil, err
+	}
+	if !renewable {
+		return nil, errors.New("token is not renewable")
+	}
+
+	secret, err := t.client.Auth().Token().RenewSelfWithContext(ctx, tokenRenewIncrement)
+	if err != nil {
+		return nil, fmt.Errorf("could not renew token: %w", err)
+	}
+
+	if secret == nil || secret.Auth == nil {
+		return nil, errors.New("empty response")
+	}
+
+	return secret, nil
+}
+
+// writeCache persists the current token. Errors are only logged, a new login is performed on the next start instead.
+func (t *TokenRenewer) writeCache() {
+	if t.cache == nil {
+		return
+	}
+
+	if err := t.cache.Write([]byte(t.client.Token())); err != nil {
+		log.Error().Str(logComponent, "vault").Str(logSubComponent, vaultTokenRenewerComponent).Str("client", t.clientName).Err(err).Msg("could not write token to cache")
+	}
+}
+
+// Ready returns a channel that is closed after the first successful login.
+func (t *TokenRenewer) Ready() <-chan struct{} {
+	return t.ready
 }
 
-func (t *TokenRenewer) StartTokenRenewal(ctx context.Context, wg *sync.WaitGroup, vaultAuthError chan error) {
+// StartTokenRenewal logs in to Vault and keeps the token renewed until the context is canceled. Failed logins are
+// retried with an exponential backoff, they never stop the renewer.
+func (t *TokenRenewer) StartTokenRenewal(ctx context.Context) {
 	t.once.Do(func() {
-		successfulLogin := false
+		backoffImpl := backoff.NewExponentialBackOff()
+		backoffImpl.InitialInterval = loginRetryInitialInterval
+		backoffImpl.MaxInterval = loginRetryMaxInterval
+		backoffImpl.MaxElapsedTime = 0
 
-		log.Info().Str("component", vaultTokenRenewerComponent).Str("client", t.clientName).Msg("Logging in to Vault")
+		restored := t.restoreCachedToken(ctx)
+		if restored == nil {
+			log.Info().Str(logComponent, "vault").Str(logSubComponent, vaultTokenRenewerComponent).Str("client", t.clientName).Msg("Logging in to Vault")
+		}
 		for {
-			vaultLoginResp, err := t.client.Auth().Login(ctx, t.auth)
+			vaultLoginResp := restored
+			var err error
+			if restored == nil {
+				vaultLoginResp, err = t.client.Auth().Login(ctx, t.auth)
+			}
+			if ctx.Err() != nil {
+				return
+			}
 
 			if err != nil {
+				t.recordError(err)
+				metrics.VaultAuthenticated.WithLabelValues(t.clientName).Set(0)
+				metrics.VaultLoginErrors.WithLabelValues(t.clientName).Inc()
+
+				retryIn := backoffImpl.NextBackOff()
+				logEvent := log.Error().Str(logComponent, "vault").Str(logSubComponent, vaultTokenRenewerComponent).Str("client", t.clientName).Err(err).Str("retry_in", retryIn.String())
 				var respErr *vault.ResponseError
 				if errors.As(err, &respErr) {
-					log.Error().Str("component", vaultTokenRenewerComponent).Err(err).Int("status_code", respErr.StatusCode).Msgf("unable to authenticate to VaultId")
-					if respErr.StatusCode >= 400 && respErr.StatusCode <= 500 && !successfulLogin {
-						vaultAuthError <- respErr
-						successfulLogin = true
-					}
+					logEvent = logEvent.Int("status_code", respErr.StatusCode)
+				}
+				logEvent.Msg("Unable to authenticate to Vault")
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(retryIn):
+					continue
 				}
-				metrics.VaultLoginErrors.WithLabelValues(t.clientName).Inc()
-				time.Sleep(15 * time.Second)
-				continue
 			}
-			if !successfulLogin {
-				// Only write to the channel once and close it afterwards
-				wg.Done()
-				successfulLogin = true
+
+			backoffImpl.Reset()
+			if restored == nil {
+				metrics.VaultLogins.WithLabelValues(t.clientName).Inc()
+				t.writeCache()
 			}
-			metrics.VaultLogins.WithLabelValues(t.clientName).Inc()
+			restored = nil
+			metrics.VaultAuthenticated.WithLabelValues(t.clientName).Set(1)
+			t.recordToken(vaultLoginResp.Auth, true)
+			t.readyOnce.Do(func() {
+				close(t.ready)
+			})
+			t.writeSinks(ctx)
 
-			tokenErr := manageTokenLifecycle(ctx, t.client, vaultLoginResp, t.clientName)
+			tokenErr := t.manageTokenLifecycle(ctx, vaultLoginResp)
 			if tokenErr != nil {
+				t.recordError(tokenErr)
 				metrics.VaultTokenRenewErrors.WithLabelValues(t.clientName).Inc()
-				log.Error().Str(logComponent, "vault").Str(logSubComponent, vaultTokenRenewerComponent).Err(err).Msgf("unable to start managing token lifecycle")
+				log.Error().Str(logComponent, "vault").Str(logSubComponent, vaultTokenRenewerComponent).Err(tokenErr).Msgf("unable to start managing token lifecycle")
 			} else {
 				metrics.VaultTokenRenewals.WithLabelValues(t.clientName).Inc()
 			}
+
+			if ctx.Err() != nil {
+				return
+			}
 		}
 	})
 }
 
 // Starts token lifecycle management. Returns only fatal errors as errors,
 // otherwise returns nil so we can attempt login again.
-func manageTokenLifecycle(ctx context.Context, client *vault.Client, token *vault.Secret, clientName string) error {
+func (t *TokenRenewer) manageTokenLifecycle(ctx context.Context, token *vault.Secret) error {
 	renew := token.Auth.Renewable // You may notice a different top-level field called Renewable. That one is used for dynamic secrets renewal, not token renewal.
 	if !renew {
 		log.Warn().Msg("Token is not configured to be renewable. Re-attempting login.")
 		return nil
 	}
 
-	watcher, err := client.NewLifetimeWatcher(&vault.LifetimeWatcherInput{
+	watcher, err := t.client.NewLifetimeWatcher(&vault.LifetimeWatcherInput{
 		Secret:    token,
-		Increment: 3600, // Learn more about this optional value in https://www.vaultproject.io/docs/concepts/lease#lease-durations-and-renewal
+		Increment: tokenRenewIncrement, // Learn more about this optional value in https://www.vaultproject.io/docs/concepts/lease#lease-durations-and-renewal
 	})
 	if err != nil {
 		return fmt.Errorf("unable to initialize new lifetime watcher for renewing auth token: %w", err)
@@ -98,16 +324,30 @@ func manageTokenLifecycle(ctx context.Context, client *vault.Client, token *vaul
 	go watcher.Start()
 	defer watcher.Stop()
 
+	var rotationCheck <-chan time.Time
+	rotatable, isRotatable := t.auth.(rotatableCredentials)
+	if isRotatable {
+		ticker := time.NewTicker(credentialsRotationCheckInterval)
+		defer ticker.Stop()
+		rotationCheck = ticker.C
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
 			return nil
+		case <-rotationCheck:
+			if rotatable.CredentialsRotated() {
+				log.Info().Str(logComponent, "vault").Str(logSubComponent, vaultTokenRenewerComponent).Msg("Credentials have been rotated, re-attempting login.")
+				return nil
+			}
 		// `DoneCh` will return if renewal fails, or if the remaining lease
 		// duration is under a built-in threshold and either renewing is not
 		// extending it or renewing is disabled. In any case, the caller
 		// needs to attempt to log in again.
 		case err := <-watcher.DoneCh():
 			if err != nil {
+				t.recordError(err)
 				log.Error().Str(logComponent, "vault").Str(logSubComponent, vaultTokenRenewerComponent).Err(err).Msg("Failed to renew token, re-attempting login.")
 				return nil
 			}
@@ -117,8 +357,10 @@ func manageTokenLifecycle(ctx context.Context, client *vault.Client, token *vaul
 
 		// Successfully completed renewal
 		case renewal := <-watcher.RenewCh():
-			metrics.TokenTtl.WithLabelValues(clientName).Set(float64(renewal.Secret.Auth.LeaseDuration))
+			metrics.TokenTtl.WithLabelValues(t.clientName).Set(float64(renewal.Secret.Auth.LeaseDuration))
 			log.Info().Str(logComponent, "vault").Str(logSubComponent, vaultTokenRenewerComponent).Int("token_ttl", renewal.Secret.Auth.LeaseDuration).Msgf("Successfully renewed token")
+			t.recordToken(renewal.Secret.Auth, false)
+			t.writeSinks(ctx)
 		}
 	}
 }
@@ -0,0 +1,187 @@
+package vault_common
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	vault "github.com/hashicorp/vault/api"
+	"github.com/soerenschneider/sc-agent/internal/storage"
+)
+
+type fakeAuth struct {
+	secret *vault.Secret
+	err    error
+	logins int
+}
+
+func (f *fakeAuth) Login(_ context.Context, client *vault.Client) (*vault.Secret, error) {
+	f.logins++
+	if f.secret != nil && f.secret.Auth != nil {
+		client.SetToken(f.secret.Auth.ClientToken)
+	}
+	return f.secret, f.err
+}
+
+func TestTokenRenewer_StartTokenRenewal(t *testing.T) {
+	tests := []struct {
+		name              string
+		auth              *fakeAuth
+		wantReady         bool
+		wantAuthenticated bool
+		wantError         string
+	}{
+		{
+			name: "successful login",
+			auth: &fakeAuth{secret: &vault.Secret{Auth: &vault.SecretAuth{
+				ClientToken:   "hvs.token",
+				LeaseDuration: 3600,
+				Renewable:     true,
+			}}},
+			wantReady:         true,
+			wantAuthenticated: true,
+		},
+		{
+			name:      "unreachable vault does not stop the renewer",
+			auth:      &fakeAuth{err: errors.New("connection refused")},
+			wantError: "connection refused",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := vault.NewClient(vault.DefaultConfig())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			renewer, err := NewTokenRenewer(client, tt.auth, "test")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan struct{})
+			go func() {
+				renewer.StartTokenRenewal(ctx)
+				close(done)
+			}()
+
+			select {
+			case <-renewer.Ready():
+				if !tt.wantReady {
+					t.Error("Ready() closed, want not ready")
+				}
+			case <-time.After(100 * time.Millisecond):
+				if tt.wantReady {
+					t.Error("Ready() not closed, want ready")
+				}
+			}
+
+			status := renewer.Status()
+			if status.IsAuthenticated() != tt.wantAuthenticated {
+				t.Errorf("Status() authenticated = %t, want %t", status.IsAuthenticated(), tt.wantAuthenticated)
+			}
+			if (tt.wantError == "") != (status.LastError == "") || !strings.Contains(status.LastError, tt.wantError) {
+				t.Errorf("Status() last error = %q, want error containing %q", status.LastError, tt.wantError)
+			}
+
+			cancel()
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("StartTokenRenewal() did not return after the context has been canceled")
+			}
+		})
+	}
+}
+
+func TestTokenRenewer_TokenCache(t *testing.T) {
+	tests := []struct {
+		name       string
+		cached     string
+		wantLogins int
+		wantCached string
+	}{
+		{
+			name:       "valid cached token is renewed",
+			cached:     "hvs.cached",
+			wantLogins: 0,
+			wantCached: "hvs.cached",
+		},
+		{
+			name:       "invalid cached token is replaced",
+			cached:     "hvs.revoked",
+			wantLogins: 1,
+			wantCached: "hvs.login",
+		},
+		{
+			name:       "empty cache",
+			wantLogins: 1,
+			wantCached: "hvs.login",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("X-Vault-Token") != "hvs.cached" {
+					w.WriteHeader(http.StatusForbidden)
+					_, _ = w.Write([]byte(`{"errors": ["permission denied"]}`))
+					return
+				}
+
+				switch r.URL.Path {
+				case "/v1/auth/token/lookup-self":
+					_, _ = w.Write([]byte(`{"data": {"renewable": true, "ttl": 600}}`))
+				case "/v1/auth/token/renew-self":
+					_, _ = w.Write([]byte(`{"auth": {"client_token": "hvs.cached", "renewable": true, "lease_duration": 3600}}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			conf := vault.DefaultConfig()
+			conf.Address = server.URL
+			client, err := vault.NewClient(conf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			client.ClearToken()
+
+			auth := &fakeAuth{secret: &vault.Secret{Auth: &vault.SecretAuth{ClientToken: "hvs.login", LeaseDuration: 3600, Renewable: true}}}
+			cache := &storage.InMemory{}
+			if tt.cached != "" {
+				cache.Data = []byte(tt.cached + "\n")
+			}
+
+			renewer, err := NewTokenRenewer(client, auth, "test", WithTokenCache(cache))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go renewer.StartTokenRenewal(ctx)
+
+			select {
+			case <-renewer.Ready():
+			case <-time.After(time.Second):
+				t.Fatal("Ready() not closed")
+			}
+
+			if auth.logins != tt.wantLogins {
+				t.Errorf("StartTokenRenewal() logins = %d, want %d", auth.logins, tt.wantLogins)
+			}
+			if got := strings.TrimSpace(string(cache.Data)); got != tt.wantCached {
+				t.Errorf("StartTokenRenewal() cached = %q, want %q", got, tt.wantCached)
+			}
+			if client.Token() != tt.wantCached {
+				t.Errorf("StartTokenRenewal() client token = %q, want %q", client.Token(), tt.wantCached)
+			}
+		})
+	}
+}
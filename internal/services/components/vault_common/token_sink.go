@@ -0,0 +1,180 @@
+package vault_common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	vault "github.com/hashicorp/vault/api"
+	"github.com/rs/zerolog/log"
+)
+
+const defaultSinkDisplayName = "sc-agent-sink"
+
+type TokenSinkStorage interface {
+	Read() ([]byte, error)
+	Write([]byte) error
+}
+
+// TokenSink writes a token to a destination so local processes can use Vault without authenticating themselves. The
+// written token is either the agent's own token or a child token with restricted policies and TTL. Optionally, the
+// token is response-wrapped, so it can only be used once to retrieve the actual token.
+type TokenSink struct {
+	name     string
+	dest     TokenSinkStorage
+	policies []string
+	ttl      string
+	wrapTtl  string
+
+	// childAccessor is the accessor of the child token that has been written last, it is revoked once the child token
+	// has been replaced.
+	childAccessor string
+}
+
+type TokenSinkOpt func(s *TokenSink) error
+
+func NewTokenSink(name string, dest TokenSinkStorage, opts ...TokenSinkOpt) (*TokenSink, error) {
+	if dest == nil {
+		return nil, errors.New("empty destination passed")
+	}
+
+	ret := &TokenSink{
+		name: name,
+		dest: dest,
+	}
+
+	for _, opt := range opts {
+		if err := opt(ret); err != nil {
+			return nil, err
+		}
+	}
+
+	return ret, nil
+}
+
+// WithChildToken writes a child token with the given policies and TTL instead of the agent's own token. The TTL of the
+// child token is capped by the TTL of the agent's token. If no policies are given, the child token inherits the
+// policies of the agent's token.
+func WithChildToken(policies []string, ttl string) TokenSinkOpt {
+	return func(s *TokenSink) error {
+		s.policies = policies
+		s.ttl = ttl
+		return nil
+	}
+}
+
+// WithWrapTtl response-wraps the written token using the given TTL.
+func WithWrapTtl(ttl string) TokenSinkOpt {
+	return func(s *TokenSink) error {
+		if ttl == "" {
+			return errors.New("empty wrap ttl passed")
+		}
+		s.wrapTtl = ttl
+		return nil
+	}
+}
+
+func (s *TokenSink) Name() string {
+	return s.name
+}
+
+func (s *TokenSink) createsChildToken() bool {
+	return len(s.policies) > 0 || s.ttl != ""
+}
+
+// Write writes a token derived from the client's current token to the destination.
+func (s *TokenSink) Write(ctx context.Context, client *vault.Client) error {
+	token := client.Token()
+	if token == "" {
+		return errors.New("client has no token")
+	}
+
+	// plain tokens do not change on renewal, so there is no need to rewrite them
+	if !s.createsChildToken() && s.wrapTtl == "" {
+		current, err := s.dest.Read()
+		if err == nil && strings.TrimSpace(string(current)) == token {
+			return nil
+		}
+	}
+
+	sinkClient := client
+	if s.wrapTtl != "" {
+		var err error
+		sinkClient, err = client.Clone()
+		if err != nil {
+			return err
+		}
+		sinkClient.SetToken(token)
+		sinkClient.SetWrappingLookupFunc(func(_, _ string) string {
+			return s.wrapTtl
+		})
+	}
+
+	var secret *vault.Secret
+	var err error
+	if s.createsChildToken() {
+		data := map[string]any{
+			"display_name": defaultSinkDisplayName,
+		}
+		if len(s.policies) > 0 {
+			data["policies"] = s.policies
+		}
+		if s.ttl != "" {
+			data["ttl"] = s.ttl
+		}
+		secret, err = sinkClient.Logical().WriteWithContext(ctx, "auth/token/create", data)
+	} else if s.wrapTtl != "" {
+		secret, err = sinkClient.Logical().WriteWithContext(ctx, "sys/wrapping/wrap", map[string]any{"token": token})
+	} else {
+		return s.dest.Write([]byte(token))
+	}
+	if err != nil {
+		return fmt.Errorf("could not create token: %w", err)
+	}
+
+	sinkToken, accessor, err := extractSinkToken(secret)
+	if err != nil {
+		return err
+	}
+
+	if err := s.dest.Write([]byte(sinkToken)); err != nil {
+		return err
+	}
+
+	if s.createsChildToken() {
+		s.revokePreviousChildToken(ctx, client, accessor)
+	}
+	return nil
+}
+
+// revokePreviousChildToken revokes the child token that has been replaced by the child token with the given accessor.
+// Errors are only logged, as the previous child token may already have expired or been revoked along with its parent.
+func (s *TokenSink) revokePreviousChildToken(ctx context.Context, client *vault.Client, accessor string) {
+	previous := s.childAccessor
+	s.childAccessor = accessor
+	if previous == "" || previous == accessor {
+		return
+	}
+
+	if _, err := client.Logical().WriteWithContext(ctx, "auth/token/revoke-accessor", map[string]any{"accessor": previous}); err != nil {
+		log.Warn().Str(logComponent, "vault").Str(logSubComponent, vaultTokenRenewerComponent).Str("sink", s.name).Err(err).Msg("could not revoke previous child token")
+	}
+}
+
+// extractSinkToken returns the (wrapping) token and the accessor of the wrapped or created token.
+func extractSinkToken(secret *vault.Secret) (string, string, error) {
+	if secret == nil {
+		return "", "", errors.New("empty response")
+	}
+
+	if secret.WrapInfo != nil && secret.WrapInfo.Token != "" {
+		return secret.WrapInfo.Token, secret.WrapInfo.WrappedAccessor, nil
+	}
+
+	if secret.Auth != nil && secret.Auth.ClientToken != "" {
+		return secret.Auth.ClientToken, secret.Auth.Accessor, nil
+	}
+
+	return "", "", errors.New("no token in response")
+}
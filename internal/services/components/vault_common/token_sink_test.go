@@ -0,0 +1,181 @@
+package vault_common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	vault "github.com/hashicorp/vault/api"
+	"github.com/soerenschneider/sc-agent/internal/storage"
+)
+
+type sinkRequest struct {
+	path    string
+	token   string
+	wrapTtl string
+	body    map[string]any
+}
+
+func newSinkTestClient(t *testing.T) (*vault.Client, *[]sinkRequest) {
+	t.Helper()
+
+	var requests []sinkRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := sinkRequest{
+			path:    r.URL.Path,
+			token:   r.Header.Get("X-Vault-Token"),
+			wrapTtl: r.Header.Get("X-Vault-Wrap-TTL"),
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req.body)
+		requests = append(requests, req)
+
+		switch {
+		case req.path == "/v1/auth/token/revoke-accessor":
+			w.WriteHeader(http.StatusNoContent)
+		case req.wrapTtl != "":
+			_, _ = fmt.Fprintf(w, `{"wrap_info": {"token": "hvs.wrapping", "wrapped_accessor": "accessor-%d"}}`, len(requests))
+		default:
+			_, _ = fmt.Fprintf(w, `{"auth": {"client_token": "hvs.child", "accessor": "accessor-%d"}}`, len(requests))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	conf := vault.DefaultConfig()
+	conf.Address = server.URL
+	client, err := vault.NewClient(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetToken("hvs.agent")
+	return client, &requests
+}
+
+func TestTokenSink_Write(t *testing.T) {
+	tests := []struct {
+		name         string
+		opts         []TokenSinkOpt
+		want         string
+		wantPath     string
+		wantWrapTtl  string
+		wantPolicies []any
+	}{
+		{
+			name: "agent token",
+			want: "hvs.agent",
+		},
+		{
+			name:        "wrapped agent token",
+			opts:        []TokenSinkOpt{WithWrapTtl("5m")},
+			want:        "hvs.wrapping",
+			wantPath:    "/v1/sys/wrapping/wrap",
+			wantWrapTtl: "5m",
+		},
+		{
+			name:         "child token",
+			opts:         []TokenSinkOpt{WithChildToken([]string{"read-only"}, "1h")},
+			want:         "hvs.child",
+			wantPath:     "/v1/auth/token/create",
+			wantPolicies: []any{"read-only"},
+		},
+		{
+			name:         "wrapped child token",
+			opts:         []TokenSinkOpt{WithChildToken([]string{"read-only"}, "1h"), WithWrapTtl("5m")},
+			want:         "hvs.wrapping",
+			wantPath:     "/v1/auth/token/create",
+			wantWrapTtl:  "5m",
+			wantPolicies: []any{"read-only"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, requests := newSinkTestClient(t)
+			dest := &storage.InMemory{}
+			sink, err := NewTokenSink("test", dest, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := sink.Write(context.Background(), client); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+
+			if got := strings.TrimSpace(string(dest.Data)); got != tt.want {
+				t.Errorf("Write() got = %q, want %q", got, tt.want)
+			}
+
+			if tt.wantPath == "" {
+				if len(*requests) != 0 {
+					t.Errorf("Write() expected no requests, got %v", *requests)
+				}
+				return
+			}
+
+			if len(*requests) != 1 {
+				t.Fatalf("Write() expected one request, got %v", *requests)
+			}
+			req := (*requests)[0]
+			if req.path != tt.wantPath || req.token != "hvs.agent" || req.wrapTtl != tt.wantWrapTtl {
+				t.Errorf("Write() got request %+v", req)
+			}
+			if tt.wantPolicies != nil && !reflect.DeepEqual(req.body["policies"], tt.wantPolicies) {
+				t.Errorf("Write() got policies %v, want %v", req.body["policies"], tt.wantPolicies)
+			}
+		})
+	}
+}
+
+func TestTokenSink_WriteRevokesPreviousChildToken(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        []TokenSinkOpt
+		wantRevoked []any
+	}{
+		{
+			name:        "child token",
+			opts:        []TokenSinkOpt{WithChildToken([]string{"read-only"}, "1h")},
+			wantRevoked: []any{"accessor-1", "accessor-2"},
+		},
+		{
+			name:        "wrapped child token",
+			opts:        []TokenSinkOpt{WithChildToken([]string{"read-only"}, "1h"), WithWrapTtl("5m")},
+			wantRevoked: []any{"accessor-1", "accessor-2"},
+		},
+		{
+			name: "wrapped agent token",
+			opts: []TokenSinkOpt{WithWrapTtl("5m")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, requests := newSinkTestClient(t)
+			sink, err := NewTokenSink("test", &storage.InMemory{}, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i := 0; i < 3; i++ {
+				if err := sink.Write(context.Background(), client); err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+			}
+
+			var revoked []any
+			for _, req := range *requests {
+				if req.path == "/v1/auth/token/revoke-accessor" {
+					if req.token != "hvs.agent" || req.wrapTtl != "" {
+						t.Errorf("Write() revoked child token using request %+v", req)
+					}
+					revoked = append(revoked, req.body["accessor"])
+				}
+			}
+			if !reflect.DeepEqual(revoked, tt.wantRevoked) {
+				t.Errorf("Write() revoked %v, want %v", revoked, tt.wantRevoked)
+			}
+		})
+	}
+}
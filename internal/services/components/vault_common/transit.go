@@ -0,0 +1,79 @@
+package vault_common
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/vault/api"
+)
+
+const defaultTransitMount = "transit"
+
+// Transit encrypts and decrypts data using named keys of Vault's transit secrets engine. The keys never leave Vault,
+// so the data can only be decrypted while the client is able to authenticate to Vault.
+type Transit struct {
+	client    *api.Client
+	mountPath string
+}
+
+func NewTransit(client *api.Client, mountPath string) (*Transit, error) {
+	if client == nil {
+		return nil, errors.New("empty client passed")
+	}
+
+	if mountPath == "" {
+		mountPath = defaultTransitMount
+	}
+
+	return &Transit{
+		client:    client,
+		mountPath: strings.Trim(mountPath, "/"),
+	}, nil
+}
+
+// Encrypt encrypts the plaintext using the given key and returns the ciphertext, e.g. "vault:v1:...".
+func (t *Transit) Encrypt(ctx context.Context, key string, plaintext []byte) (string, error) {
+	path := fmt.Sprintf("%s/encrypt/%s", t.mountPath, key)
+	secret, err := t.client.Logical().WriteWithContext(ctx, path, map[string]any{
+		"plaintext": base64.StdEncoding.EncodeToString(plaintext),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if secret == nil || secret.Data == nil {
+		return "", errors.New("empty response")
+	}
+
+	ciphertext, ok := secret.Data["ciphertext"].(string)
+	if !ok || ciphertext == "" {
+		return "", errors.New("no field 'ciphertext' in response")
+	}
+
+	return ciphertext, nil
+}
+
+// Decrypt decrypts the ciphertext that has been encrypted using the given key.
+func (t *Transit) Decrypt(ctx context.Context, key string, ciphertext string) ([]byte, error) {
+	path := fmt.Sprintf("%s/decrypt/%s", t.mountPath, key)
+	secret, err := t.client.Logical().WriteWithContext(ctx, path, map[string]any{
+		"ciphertext": ciphertext,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if secret == nil || secret.Data == nil {
+		return nil, errors.New("empty response")
+	}
+
+	encoded, ok := secret.Data["plaintext"].(string)
+	if !ok {
+		return nil, errors.New("no field 'plaintext' in response")
+	}
+
+	return base64.StdEncoding.DecodeString(encoded)
+}
@@ -0,0 +1,79 @@
+package vault_common
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	vault "github.com/hashicorp/vault/api"
+)
+
+// newTransitTestClient emulates the transit secrets engine mounted at "transit" with a single key "agent-key". The
+// "ciphertext" is the base64 encoded plaintext prefixed with the key version.
+func newTransitTestClient(t *testing.T) *vault.Client {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		switch r.URL.Path {
+		case "/v1/transit/encrypt/agent-key":
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"ciphertext": "vault:v1:" + body["plaintext"]}})
+		case "/v1/transit/decrypt/agent-key":
+			if !strings.HasPrefix(body["ciphertext"], "vault:v1:") {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"errors": ["invalid ciphertext"]}`))
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"plaintext": strings.TrimPrefix(body["ciphertext"], "vault:v1:")}})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"errors": ["encryption key not found"]}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	conf := vault.DefaultConfig()
+	conf.Address = server.URL
+	client, err := vault.NewClient(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetToken("hvs.agent")
+	return client
+}
+
+func TestTransit(t *testing.T) {
+	transit, err := NewTransit(newTransitTestClient(t), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ciphertext, err := transit.Encrypt(context.Background(), "agent-key", []byte("secret"))
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if want := "vault:v1:" + base64.StdEncoding.EncodeToString([]byte("secret")); ciphertext != want {
+		t.Errorf("Encrypt() got = %q, want %q", ciphertext, want)
+	}
+
+	plaintext, err := transit.Decrypt(context.Background(), "agent-key", ciphertext)
+	if err != nil {
+		t.Fatalf("Decrypt() error = %v", err)
+	}
+	if string(plaintext) != "secret" {
+		t.Errorf("Decrypt() got = %q, want %q", plaintext, "secret")
+	}
+
+	if _, err := transit.Encrypt(context.Background(), "unknown-key", []byte("secret")); err == nil {
+		t.Error("Encrypt() expected error for unknown key")
+	}
+	if _, err := transit.Decrypt(context.Background(), "agent-key", "garbage"); err == nil {
+		t.Error("Decrypt() expected error for invalid ciphertext")
+	}
+}
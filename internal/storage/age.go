@@ -0,0 +1,248 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/agessh"
+	"filippo.io/age/armor"
+)
+
+const (
+	ParamAgeRecipient      = "age_recipient"
+	ParamAgeRecipientsFile = "age_recipients_file"
+	ParamAgeIdentityFile   = "age_identity_file"
+	// ParamAgeKeyFile references a host-local X25519 identity that is used as recipient and identity. The identity is
+	// generated if the file does not exist yet.
+	ParamAgeKeyFile = "age_key_file"
+)
+
+var ErrNoAgeIdentity = errors.New("no age identity configured to decrypt content")
+
+// contentEncryption encrypts content before it is written and decrypts it after it has been read.
+type contentEncryption interface {
+	encrypt(plaintext []byte) ([]byte, error)
+	decrypt(ciphertext []byte) ([]byte, error)
+}
+
+// newEncryptionFromParams builds the encryption configured by the query parameters of a storage URI, content is
+// either encrypted using age or using Vault's transit secrets engine. It returns nil if no encryption is configured.
+func newEncryptionFromParams(params map[string][]string) (contentEncryption, error) {
+	ageEnc, err := newAgeEncryptionFromParams(params)
+	if err != nil {
+		return nil, fmt.Errorf("could not build age encryption: %w", err)
+	}
+
+	transitEnc, err := newTransitEncryptionFromParams(params)
+	if err != nil {
+		return nil, fmt.Errorf("could not build transit encryption: %w", err)
+	}
+
+	switch {
+	case ageEnc != nil && transitEnc != nil:
+		return nil, errors.New("age and transit encryption are mutually exclusive")
+	case ageEnc != nil:
+		return ageEnc, nil
+	case transitEnc != nil:
+		return transitEnc, nil
+	default:
+		return nil, nil
+	}
+}
+
+// ageEncryption encrypts content to a set of age or SSH recipients. Content is written ASCII-armored. If an identity
+// is configured, content can be decrypted again, which is required to detect whether encrypted content has changed.
+type ageEncryption struct {
+	recipients []age.Recipient
+	identities []age.Identity
+}
+
+// newAgeEncryptionFromParams builds the encryption from the query parameters of a storage URI. It returns nil if no
+// recipients are configured.
+func newAgeEncryptionFromParams(params map[string][]string) (*ageEncryption, error) {
+	var recipients []age.Recipient
+	for _, val := range params[ParamAgeRecipient] {
+		recipient, err := parseAgeRecipient(val)
+		if err != nil {
+			return nil, err
+		}
+		recipients = append(recipients, recipient)
+	}
+
+	for _, file := range params[ParamAgeRecipientsFile] {
+		parsed, err := parseAgeRecipientsFile(file)
+		if err != nil {
+			return nil, err
+		}
+		recipients = append(recipients, parsed...)
+	}
+
+	var keyIdentities []age.Identity
+	for _, file := range params[ParamAgeKeyFile] {
+		identity, err := loadOrGenerateAgeKey(file)
+		if err != nil {
+			return nil, err
+		}
+		recipients = append(recipients, identity.Recipient())
+		keyIdentities = append(keyIdentities, identity)
+	}
+
+	identityFiles := params[ParamAgeIdentityFile]
+	if len(recipients) == 0 {
+		if len(identityFiles) > 0 {
+			return nil, errors.New("age identity configured without recipients")
+		}
+		return nil, nil
+	}
+
+	ret := &ageEncryption{recipients: recipients, identities: keyIdentities}
+	for _, file := range identityFiles {
+		identities, err := parseAgeIdentityFile(file)
+		if err != nil {
+			return nil, err
+		}
+		ret.identities = append(ret.identities, identities...)
+	}
+
+	return ret, nil
+}
+
+func (a *ageEncryption) encrypt(plaintext []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	armored := armor.NewWriter(&buf)
+	writer, err := age.Encrypt(armored, a.recipients...)
+	if err != nil {
+		return nil, fmt.Errorf("could not encrypt content: %w", err)
+	}
+
+	if _, err := writer.Write(plaintext); err != nil {
+		return nil, fmt.Errorf("could not encrypt content: %w", err)
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("could not encrypt content: %w", err)
+	}
+
+	if err := armored.Close(); err != nil {
+		return nil, fmt.Errorf("could not encrypt content: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (a *ageEncryption) decrypt(ciphertext []byte) ([]byte, error) {
+	if len(a.identities) == 0 {
+		return nil, ErrNoAgeIdentity
+	}
+
+	reader, err := age.Decrypt(armor.NewReader(bytes.NewReader(ciphertext)), a.identities...)
+	if err != nil {
+		return nil, fmt.Errorf("could not decrypt content: %w", err)
+	}
+
+	return io.ReadAll(reader)
+}
+
+func parseAgeRecipient(val string) (age.Recipient, error) {
+	val = strings.TrimSpace(val)
+	if strings.HasPrefix(val, "ssh-") {
+		return agessh.ParseRecipient(val)
+	}
+	return age.ParseX25519Recipient(val)
+}
+
+func parseAgeRecipientsFile(file string) ([]age.Recipient, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("could not read age recipients file: %w", err)
+	}
+
+	var recipients []age.Recipient
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		recipient, err := parseAgeRecipient(line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recipient in %q: %w", file, err)
+		}
+		recipients = append(recipients, recipient)
+	}
+
+	if len(recipients) == 0 {
+		return nil, fmt.Errorf("no recipients found in %q", file)
+	}
+
+	return recipients, scanner.Err()
+}
+
+// parseAgeIdentityFile parses native age identities or an unencrypted SSH private key.
+func parseAgeIdentityFile(file string) ([]age.Identity, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("could not read age identity file: %w", err)
+	}
+
+	if bytes.Contains(data, []byte("-----BEGIN")) {
+		identity, err := agessh.ParseIdentity(data)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse ssh identity %q: %w", file, err)
+		}
+		return []age.Identity{identity}, nil
+	}
+
+	identities, err := age.ParseIdentities(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("could not parse age identity %q: %w", file, err)
+	}
+	return identities, nil
+}
+
+// loadOrGenerateAgeKey reads the X25519 identity from file. If the file does not exist, a new identity is generated and
+// written to the file, readable only by its owner.
+func loadOrGenerateAgeKey(file string) (*age.X25519Identity, error) {
+	data, err := os.ReadFile(file)
+	if err == nil {
+		identity, err := age.ParseX25519Identity(strings.TrimSpace(string(data)))
+		if err != nil {
+			return nil, fmt.Errorf("could not parse age key %q: %w", file, err)
+		}
+		return identity, nil
+	}
+
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("could not read age key file: %w", err)
+	}
+
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		return nil, fmt.Errorf("could not generate age key: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), defaultDirMode); err != nil {
+		return nil, fmt.Errorf("could not create directory for age key: %w", err)
+	}
+
+	// O_EXCL prevents overwriting a key that has been created concurrently
+	fd, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("could not write age key file: %w", err)
+	}
+	defer fd.Close()
+
+	if _, err := fd.WriteString(identity.String() + "\n"); err != nil {
+		return nil, fmt.Errorf("could not write age key file: %w", err)
+	}
+
+	return identity, nil
+}
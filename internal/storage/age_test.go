@@ -0,0 +1,184 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/spf13/afero"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestFilesystemStorage_AgeEncryption(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	recipientsFile := filepath.Join(dir, "recipients.txt")
+	if err := os.WriteFile(recipientsFile, []byte("# backup host\n"+identity.Recipient().String()+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	identityFile := filepath.Join(dir, "identity.txt")
+	if err := os.WriteFile(identityFile, []byte(identity.String()+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	params := url.Values{}
+	params.Set(ParamAgeRecipientsFile, recipientsFile)
+	params.Set(ParamAgeIdentityFile, identityFile)
+	fss, err := NewFilesystemStorageFromUri("file://" + filepath.Join(dir, "secret.age") + "?" + params.Encode())
+	if err != nil {
+		t.Fatalf("NewFilesystemStorageFromUri() unexpected error: %v", err)
+	}
+	fss.fs = afero.NewMemMapFs()
+
+	if err := fss.Write([]byte("password=secret")); err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+
+	raw, err := afero.ReadFile(fss.fs, fss.FilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(raw, []byte("secret")) || !bytes.HasPrefix(raw, []byte("-----BEGIN AGE ENCRYPTED FILE-----")) {
+		t.Fatalf("expected armored ciphertext, got %q", raw)
+	}
+
+	got, err := fss.Read()
+	if err != nil {
+		t.Fatalf("Read() unexpected error: %v", err)
+	}
+	if string(got) != "password=secret\n" {
+		t.Errorf("Read() got = %q, want %q", got, "password=secret\n")
+	}
+
+	// without identity, only content that has been written by the storage itself is known
+	fss.encryption.(*ageEncryption).identities = nil
+	got, err = fss.Read()
+	if err != nil {
+		t.Fatalf("Read() unexpected error without identity: %v", err)
+	}
+	if string(got) != "password=secret\n" {
+		t.Errorf("Read() without identity got = %q, want %q", got, "password=secret\n")
+	}
+
+	encrypted, err := fss.encryption.encrypt([]byte("password=changed\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := afero.WriteFile(fss.fs, fss.FilePath, encrypted, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fss.Read(); !errors.Is(err, ErrNoAgeIdentity) {
+		t.Errorf("Read() expected ErrNoAgeIdentity for changed content, got %v", err)
+	}
+}
+
+func TestNewAgeEncryptionFromParams(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sshRecipient := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPub)))
+
+	tests := []struct {
+		name    string
+		params  url.Values
+		wantNil bool
+		wantErr bool
+	}{
+		{
+			name:    "no encryption",
+			params:  url.Values{},
+			wantNil: true,
+		},
+		{
+			name:   "age recipient",
+			params: url.Values{ParamAgeRecipient: []string{identity.Recipient().String()}},
+		},
+		{
+			name:   "ssh recipient",
+			params: url.Values{ParamAgeRecipient: []string{sshRecipient}},
+		},
+		{
+			name:    "invalid recipient",
+			params:  url.Values{ParamAgeRecipient: []string{"age1invalid"}},
+			wantErr: true,
+		},
+		{
+			name:    "identity without recipient",
+			params:  url.Values{ParamAgeIdentityFile: []string{"/etc/sc-agent/identity.txt"}},
+			wantErr: true,
+		},
+		{
+			name:    "missing recipients file",
+			params:  url.Values{ParamAgeRecipientsFile: []string{"/does/not/exist"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newAgeEncryptionFromParams(tt.params)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newAgeEncryptionFromParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (got == nil) != tt.wantNil {
+				t.Errorf("newAgeEncryptionFromParams() got = %v, wantNil %v", got, tt.wantNil)
+			}
+		})
+	}
+}
+
+func TestNewAgeEncryptionFromParams_KeyFile(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "keys", "host.key")
+	params := url.Values{ParamAgeKeyFile: []string{keyFile}}
+
+	generated, err := newAgeEncryptionFromParams(params)
+	if err != nil {
+		t.Fatalf("newAgeEncryptionFromParams() unexpected error: %v", err)
+	}
+
+	info, err := os.Stat(keyFile)
+	if err != nil {
+		t.Fatalf("expected key file to be generated: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected key file mode 0600, got %v", info.Mode().Perm())
+	}
+
+	ciphertext, err := generated.encrypt([]byte("hvs.token"))
+	if err != nil {
+		t.Fatalf("encrypt() unexpected error: %v", err)
+	}
+
+	// the existing key is used after a restart
+	loaded, err := newAgeEncryptionFromParams(params)
+	if err != nil {
+		t.Fatalf("newAgeEncryptionFromParams() unexpected error: %v", err)
+	}
+	plaintext, err := loaded.decrypt(ciphertext)
+	if err != nil {
+		t.Fatalf("decrypt() unexpected error: %v", err)
+	}
+	if string(plaintext) != "hvs.token" {
+		t.Errorf("decrypt() got = %q, want %q", plaintext, "hvs.token")
+	}
+}
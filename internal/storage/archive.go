@@ -0,0 +1,584 @@
+package storage
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	ArchiveFormatTarGz = "tar.gz"
+	ArchiveFormatZip   = "zip"
+
+	defaultArchiveMaxBytes = 1 << 30
+	defaultArchiveMaxFiles = 10000
+)
+
+var (
+	ErrArchivePathTraversal = errors.New("archive entry escapes destination")
+	ErrArchiveTooLarge      = errors.New("archive exceeds size limits")
+	ErrArchiveNotReadable   = errors.New("extracted archives can not be read")
+)
+
+// ArchiveStorage extracts tar.gz and zip archives, or writes trees of files, into a directory. Every archive is
+// extracted into its own sibling directory of Dest, named after the hash of the archive, and Dest is atomically swapped
+// to a symlink pointing to it. The directory of the previous archive is retained until the next write to be able to
+// roll back.
+type ArchiveStorage struct {
+	// Dest is the path of the symlink that points to the directory of the extracted archive.
+	Dest            string
+	Format          string
+	Include         []string
+	Exclude         []string
+	StripComponents int
+	MaxBytes        int64
+	MaxFiles        int
+
+	previous    string
+	current     string
+	canRollback bool
+}
+
+type ArchiveStorageOpt func(*ArchiveStorage) error
+
+// WithArchiveInclude only extracts entries whose path, or one of its parent directories, matches one of the globs.
+func WithArchiveInclude(globs []string) ArchiveStorageOpt {
+	return func(a *ArchiveStorage) error {
+		if err := validateGlobs(globs); err != nil {
+			return err
+		}
+		a.Include = globs
+		return nil
+	}
+}
+
+// WithArchiveExclude skips entries whose path, or one of its parent directories, matches one of the globs.
+func WithArchiveExclude(globs []string) ArchiveStorageOpt {
+	return func(a *ArchiveStorage) error {
+		if err := validateGlobs(globs); err != nil {
+			return err
+		}
+		a.Exclude = globs
+		return nil
+	}
+}
+
+// WithArchiveStripComponents strips the given number of leading path elements from the entries' paths.
+func WithArchiveStripComponents(components int) ArchiveStorageOpt {
+	return func(a *ArchiveStorage) error {
+		if components < 0 {
+			return errors.New("strip components must not be negative")
+		}
+		a.StripComponents = components
+		return nil
+	}
+}
+
+// WithArchiveMaxBytes limits the total size of the extracted files.
+func WithArchiveMaxBytes(maxBytes int64) ArchiveStorageOpt {
+	return func(a *ArchiveStorage) error {
+		if maxBytes <= 0 {
+			return errors.New("max bytes must be positive")
+		}
+		a.MaxBytes = maxBytes
+		return nil
+	}
+}
+
+// WithArchiveMaxFiles limits the number of entries of the archive.
+func WithArchiveMaxFiles(maxFiles int) ArchiveStorageOpt {
+	return func(a *ArchiveStorage) error {
+		if maxFiles <= 0 {
+			return errors.New("max files must be positive")
+		}
+		a.MaxFiles = maxFiles
+		return nil
+	}
+}
+
+func NewArchiveStorage(dest string, format string, opts ...ArchiveStorageOpt) (*ArchiveStorage, error) {
+	if dest == "" || !filepath.IsAbs(dest) {
+		return nil, fmt.Errorf("destination %q must be an absolute path", dest)
+	}
+
+	if format != ArchiveFormatTarGz && format != ArchiveFormatZip {
+		return nil, fmt.Errorf("unsupported archive format %q", format)
+	}
+
+	ret := &ArchiveStorage{
+		Dest:     filepath.Clean(dest),
+		Format:   format,
+		MaxBytes: defaultArchiveMaxBytes,
+		MaxFiles: defaultArchiveMaxFiles,
+	}
+
+	var errs error
+	for _, opt := range opts {
+		if err := opt(ret); err != nil {
+			errs = errors.Join(errs, err)
+		}
+	}
+
+	return ret, errs
+}
+
+// ArchiveFormatFromName returns the archive format based on the file extension of the given name, or an empty string
+// if the format can not be detected.
+func ArchiveFormatFromName(name string) string {
+	name = strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		return ArchiveFormatTarGz
+	case strings.HasSuffix(name, ".zip"):
+		return ArchiveFormatZip
+	default:
+		return ""
+	}
+}
+
+func (a *ArchiveStorage) Read() ([]byte, error) {
+	return nil, ErrArchiveNotReadable
+}
+
+func (a *ArchiveStorage) CanRead() error {
+	_, err := a.ContentHash()
+	return err
+}
+
+// ContentHash returns the hash of the archive that is currently extracted, as computed by ContentHash, or the hash of
+// the tree that has been written using WriteTree.
+func (a *ArchiveStorage) ContentHash() (string, error) {
+	target, err := os.Readlink(a.Dest)
+	if err != nil {
+		return "", err
+	}
+
+	hash, found := strings.CutPrefix(filepath.Base(target), a.releasePrefix())
+	if !found || !isHexHash(hash) {
+		return "", fmt.Errorf("%q does not point to an extracted archive", a.Dest)
+	}
+	return hash, nil
+}
+
+func (a *ArchiveStorage) Write(data []byte) error {
+	return a.writeRelease(ContentHash(data), func(dir string) error {
+		return a.extract(data, dir)
+	})
+}
+
+// EntryWriter writes a single entry of a tree. Directories are written using fs.ModeDir, symlinks using
+// fs.ModeSymlink and the link target, regular files using their permissions and content.
+type EntryWriter func(name string, mode fs.FileMode, linkTarget string, content io.Reader) error
+
+// WriteTree writes a tree of files that is not packed as archive, e.g. a directory of a git repository. The walk func
+// is called with a writer for the tree's entries that enforces the same rules and limits as extracting an archive.
+// The hash identifies the content of the tree and has to change whenever the content changes.
+func (a *ArchiveStorage) WriteTree(hash string, walk func(write EntryWriter) error) error {
+	if !isHexHash(hash) {
+		return fmt.Errorf("invalid hash %q", hash)
+	}
+
+	return a.writeRelease(hash, func(dir string) error {
+		return walk(a.newExtractor(dir).writeEntry)
+	})
+}
+
+// writeRelease populates a new directory named after the hash and atomically swaps Dest to point to it.
+func (a *ArchiveStorage) writeRelease(hash string, populate func(dir string) error) error {
+	if info, err := os.Lstat(a.Dest); err == nil && info.Mode()&fs.ModeSymlink == 0 {
+		return fmt.Errorf("destination %q exists and is not a symlink", a.Dest)
+	}
+
+	parent := filepath.Dir(a.Dest)
+	release := a.releasePrefix() + hash
+	releaseDir := filepath.Join(parent, release)
+
+	previous, err := os.Readlink(a.Dest)
+	if err != nil {
+		previous = ""
+	}
+	if previous == release {
+		// the content is already written
+		a.canRollback = false
+		return nil
+	}
+
+	if err := os.MkdirAll(parent, 0755); err != nil {
+		return err
+	}
+
+	tmpDir, err := os.MkdirTemp(parent, "."+filepath.Base(a.Dest)+".tmp-")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.RemoveAll(tmpDir)
+	}()
+
+	// #nosec G302 the extracted files are meant to be read by other users, e.g. a web server
+	if err := os.Chmod(tmpDir, 0755); err != nil {
+		return err
+	}
+
+	if err := populate(tmpDir); err != nil {
+		return err
+	}
+
+	// a directory of identical content may be left over from a previous run
+	if err := os.RemoveAll(releaseDir); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpDir, releaseDir); err != nil {
+		return err
+	}
+
+	if err := swapSymlink(release, a.Dest); err != nil {
+		return err
+	}
+
+	a.previous = previous
+	a.current = release
+	a.canRollback = true
+	a.cleanup()
+	return nil
+}
+
+// Rollback points the symlink to the previously extracted archive and removes the latest one. If no archive has been
+// extracted before, the symlink is removed.
+func (a *ArchiveStorage) Rollback() error {
+	if !a.canRollback {
+		return ErrNothingToRollback
+	}
+	a.canRollback = false
+
+	var err error
+	if a.previous == "" {
+		err = os.Remove(a.Dest)
+	} else {
+		err = swapSymlink(a.previous, a.Dest)
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.RemoveAll(filepath.Join(filepath.Dir(a.Dest), a.current))
+}
+
+func (a *ArchiveStorage) releasePrefix() string {
+	return "." + filepath.Base(a.Dest) + "."
+}
+
+// cleanup removes all extracted archives except the current and the previous one.
+func (a *ArchiveStorage) cleanup() {
+	parent := filepath.Dir(a.Dest)
+	entries, err := os.ReadDir(parent)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		hash, found := strings.CutPrefix(entry.Name(), a.releasePrefix())
+		if !found || !isHexHash(hash) || entry.Name() == a.current || entry.Name() == a.previous {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(parent, entry.Name())); err != nil {
+			log.Warn().Err(err).Str("dir", entry.Name()).Msg("could not remove outdated extracted archive")
+		}
+	}
+}
+
+// swapSymlink atomically replaces link with a symlink pointing to target.
+func swapSymlink(target, link string) error {
+	tmpLink := filepath.Join(filepath.Dir(link), "."+filepath.Base(link)+".link-tmp")
+	_ = os.Remove(tmpLink)
+	if err := os.Symlink(target, tmpLink); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmpLink, link); err != nil {
+		_ = os.Remove(tmpLink)
+		return err
+	}
+
+	dir, err := os.Open(filepath.Dir(link))
+	if err != nil {
+		return nil
+	}
+	defer func() {
+		_ = dir.Close()
+	}()
+	if err := dir.Sync(); err != nil {
+		log.Warn().Err(err).Str("dir", filepath.Dir(link)).Msg("could not sync directory")
+	}
+	return nil
+}
+
+// archiveExtractor writes the entries of an archive to a directory while enforcing the configured limits.
+type archiveExtractor struct {
+	conf     *ArchiveStorage
+	dir      string
+	files    int
+	written  int64
+	symlinks map[string]struct{}
+}
+
+func (a *ArchiveStorage) newExtractor(dir string) *archiveExtractor {
+	return &archiveExtractor{
+		conf:     a,
+		dir:      dir,
+		symlinks: map[string]struct{}{},
+	}
+}
+
+func (a *ArchiveStorage) extract(data []byte, dir string) error {
+	extractor := a.newExtractor(dir)
+	if a.Format == ArchiveFormatZip {
+		return extractor.extractZip(data)
+	}
+	return extractor.extractTarGz(data)
+}
+
+func (e *archiveExtractor) extractTarGz(data []byte) error {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("could not read archive: %w", err)
+	}
+	defer func() {
+		_ = gz.Close()
+	}()
+
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("could not read archive: %w", err)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = e.writeEntry(header.Name, fs.ModeDir, "", nil)
+		case tar.TypeReg:
+			err = e.writeEntry(header.Name, fs.FileMode(header.Mode).Perm(), "", reader) // #nosec G115
+		case tar.TypeSymlink:
+			err = e.writeEntry(header.Name, fs.ModeSymlink, header.Linkname, nil)
+		case tar.TypeXGlobalHeader:
+			continue
+		default:
+			err = fmt.Errorf("unsupported type of archive entry %q", header.Name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (e *archiveExtractor) extractZip(data []byte) error {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("could not read archive: %w", err)
+	}
+
+	for _, file := range reader.File {
+		if err := e.extractZipFile(file); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *archiveExtractor) extractZipFile(file *zip.File) error {
+	mode := file.Mode()
+	if mode.IsDir() {
+		return e.writeEntry(file.Name, fs.ModeDir, "", nil)
+	}
+
+	rc, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("could not read archive entry %q: %w", file.Name, err)
+	}
+	defer func() {
+		_ = rc.Close()
+	}()
+
+	switch {
+	case mode&fs.ModeSymlink != 0:
+		target, err := io.ReadAll(io.LimitReader(rc, 4096))
+		if err != nil {
+			return err
+		}
+		return e.writeEntry(file.Name, fs.ModeSymlink, string(target), nil)
+	case mode.IsRegular():
+		return e.writeEntry(file.Name, mode.Perm(), "", rc)
+	default:
+		return fmt.Errorf("unsupported type of archive entry %q", file.Name)
+	}
+}
+
+func (e *archiveExtractor) writeEntry(name string, mode fs.FileMode, linkTarget string, content io.Reader) error {
+	name, err := e.sanitize(name)
+	if err != nil || name == "" || !e.conf.matches(name) {
+		return err
+	}
+
+	e.files++
+	if e.files > e.conf.MaxFiles {
+		return fmt.Errorf("%w: more than %d entries", ErrArchiveTooLarge, e.conf.MaxFiles)
+	}
+
+	target := filepath.Join(e.dir, filepath.FromSlash(name))
+	if mode.IsDir() {
+		return os.MkdirAll(target, 0755)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	if mode&fs.ModeSymlink != 0 {
+		// targets are not allowed to contain parent directory components at all: they are resolved by the OS after
+		// following previously extracted symlinks, which a lexical check can not reason about.
+		linkTarget = filepath.ToSlash(linkTarget)
+		if path.IsAbs(linkTarget) || slices.Contains(strings.Split(linkTarget, "/"), "..") {
+			return fmt.Errorf("%w: symlink %q points to %q", ErrArchivePathTraversal, name, linkTarget)
+		}
+		e.symlinks[name] = struct{}{}
+		return os.Symlink(linkTarget, target)
+	}
+
+	if mode == 0 {
+		mode = 0644
+	}
+	file, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+	if err != nil {
+		return err
+	}
+
+	remaining := e.conf.MaxBytes - e.written
+	written, err := io.Copy(file, io.LimitReader(content, remaining+1))
+	e.written += written
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if e.written > e.conf.MaxBytes {
+		return fmt.Errorf("%w: more than %d bytes", ErrArchiveTooLarge, e.conf.MaxBytes)
+	}
+
+	return nil
+}
+
+// sanitize returns the cleaned path of the entry after stripping leading components. It returns an error if the entry
+// would be written outside the destination, either directly or by following a symlink that has been extracted before.
+func (e *archiveExtractor) sanitize(name string) (string, error) {
+	name = strings.ReplaceAll(name, "\\", "/")
+	if path.IsAbs(name) {
+		return "", fmt.Errorf("%w: %q", ErrArchivePathTraversal, name)
+	}
+
+	name = path.Clean(name)
+	if escapes(name) {
+		return "", fmt.Errorf("%w: %q", ErrArchivePathTraversal, name)
+	}
+
+	parts := strings.Split(name, "/")
+	if len(parts) <= e.conf.StripComponents {
+		return "", nil
+	}
+	parts = parts[e.conf.StripComponents:]
+	name = strings.Join(parts, "/")
+	if name == "." {
+		return "", nil
+	}
+
+	for i := 1; i <= len(parts); i++ {
+		if _, isSymlink := e.symlinks[strings.Join(parts[:i], "/")]; isSymlink {
+			return "", fmt.Errorf("%w: %q is written through a symlink", ErrArchivePathTraversal, name)
+		}
+	}
+
+	return name, nil
+}
+
+// matches returns true if the entry should be extracted according to the include and exclude globs.
+func (a *ArchiveStorage) matches(name string) bool {
+	if matchesAny(a.Exclude, name) {
+		return false
+	}
+
+	return len(a.Include) == 0 || matchesAny(a.Include, name)
+}
+
+// matchesAny returns true if the name, or one of its parent directories, matches one of the globs. Globs without a
+// slash are matched against the individual path elements, e.g. "*.map" matches "dist/app.js.map".
+func matchesAny(globs []string, name string) bool {
+	for _, glob := range globs {
+		matchElements := !strings.Contains(glob, "/")
+		for candidate := name; candidate != "." && candidate != "/"; candidate = path.Dir(candidate) {
+			subject := candidate
+			if matchElements {
+				subject = path.Base(candidate)
+			}
+			if matched, _ := path.Match(glob, subject); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func validateGlobs(globs []string) error {
+	for _, glob := range globs {
+		if _, err := path.Match(glob, ""); err != nil {
+			return fmt.Errorf("invalid glob %q: %w", glob, err)
+		}
+	}
+	return nil
+}
+
+func escapes(name string) bool {
+	return name == ".." || strings.HasPrefix(name, "../")
+}
+
+var hexHashRegex = regexp.MustCompile("^[0-9a-f]{64}$")
+
+func isHexHash(s string) bool {
+	return hexHashRegex.MatchString(s)
+}
+
+// ContentHash returns the hex encoded sha256 hash of the content.
+func ContentHash(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// ContentMatches returns whether the content read from a storage matches the hash of the content that has been
+// written. File based storages terminate the written content with a newline if it does not end with one already.
+func ContentMatches(stored []byte, hash string) bool {
+	if ContentHash(stored) == hash {
+		return true
+	}
+
+	written, found := bytes.CutSuffix(stored, []byte("\n"))
+	return found && ContentHash(written) == hash
+}
@@ -0,0 +1,322 @@
+package storage
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type archiveEntry struct {
+	name     string
+	content  string
+	linkname string
+	dir      bool
+}
+
+func buildTarGz(t *testing.T, entries []archiveEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, entry := range entries {
+		header := &tar.Header{Name: entry.name, Mode: 0644, Size: int64(len(entry.content)), Typeflag: tar.TypeReg}
+		switch {
+		case entry.dir:
+			header.Typeflag = tar.TypeDir
+			header.Mode = 0755
+		case entry.linkname != "":
+			header.Typeflag = tar.TypeSymlink
+			header.Linkname = entry.linkname
+			header.Size = 0
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if header.Typeflag == tar.TypeReg {
+			if _, err := tw.Write([]byte(entry.content)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	_ = tw.Close()
+	_ = gz.Close()
+	return buf.Bytes()
+}
+
+func buildZip(t *testing.T, entries []archiveEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, entry := range entries {
+		w, err := zw.Create(entry.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(entry.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = zw.Close()
+	return buf.Bytes()
+}
+
+func readExtracted(t *testing.T, dest, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dest, name))
+	if err != nil {
+		t.Fatalf("could not read %s: %v", name, err)
+	}
+	return string(data)
+}
+
+func TestArchiveStorage_Write(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "site")
+	archive, err := NewArchiveStorage(dest, ArchiveFormatTarGz,
+		WithArchiveStripComponents(1),
+		WithArchiveInclude([]string{"dist"}),
+		WithArchiveExclude([]string{"*.map"}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v1 := buildTarGz(t, []archiveEntry{
+		{name: "release-1/", dir: true},
+		{name: "release-1/README.md", content: "readme"},
+		{name: "release-1/dist/index.html", content: "v1"},
+		{name: "release-1/dist/app.js.map", content: "map"},
+		{name: "release-1/dist/latest.html", linkname: "index.html"},
+	})
+	if err := archive.Write(v1); err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+
+	if got := readExtracted(t, dest, "dist/index.html"); got != "v1" {
+		t.Errorf("index.html = %q, want %q", got, "v1")
+	}
+	if got := readExtracted(t, dest, "dist/latest.html"); got != "v1" {
+		t.Errorf("latest.html = %q, want %q", got, "v1")
+	}
+	for _, excluded := range []string{"README.md", "dist/app.js.map"} {
+		if _, err := os.Stat(filepath.Join(dest, excluded)); err == nil {
+			t.Errorf("expected %s not to be extracted", excluded)
+		}
+	}
+
+	hash, err := archive.ContentHash()
+	if err != nil || hash != ContentHash(v1) {
+		t.Errorf("ContentHash() = %q, %v, want %q", hash, err, ContentHash(v1))
+	}
+
+	v2 := buildTarGz(t, []archiveEntry{{name: "release-2/dist/index.html", content: "v2"}})
+	if err := archive.Write(v2); err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+	if got := readExtracted(t, dest, "dist/index.html"); got != "v2" {
+		t.Errorf("index.html = %q, want %q", got, "v2")
+	}
+
+	if err := archive.Rollback(); err != nil {
+		t.Fatalf("Rollback() unexpected error: %v", err)
+	}
+	if got := readExtracted(t, dest, "dist/index.html"); got != "v1" {
+		t.Errorf("index.html after rollback = %q, want %q", got, "v1")
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(dest), ".site."+ContentHash(v2))); !errors.Is(err, os.ErrNotExist) {
+		t.Error("expected directory of rolled back archive to be removed")
+	}
+
+	v3 := buildTarGz(t, []archiveEntry{{name: "release-3/dist/index.html", content: "v3"}})
+	if err := archive.Write(v2); err != nil {
+		t.Fatal(err)
+	}
+	if err := archive.Write(v3); err != nil {
+		t.Fatal(err)
+	}
+
+	// only the current and the previous archive are retained
+	entries, _ := filepath.Glob(filepath.Join(filepath.Dir(dest), ".site.*"))
+	if len(entries) != 2 {
+		t.Errorf("expected 2 extracted archives to be retained, got %v", entries)
+	}
+}
+
+func TestArchiveStorage_WriteZip(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "data")
+	archive, err := NewArchiveStorage(dest, ArchiveFormatZip)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := archive.Write(buildZip(t, []archiveEntry{{name: "a/b.txt", content: "hello"}})); err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+	if got := readExtracted(t, dest, "a/b.txt"); got != "hello" {
+		t.Errorf("b.txt = %q, want %q", got, "hello")
+	}
+
+	// rolling back the initial write removes the symlink
+	if err := archive.Rollback(); err != nil {
+		t.Fatalf("Rollback() unexpected error: %v", err)
+	}
+	if _, err := os.Lstat(dest); !errors.Is(err, os.ErrNotExist) {
+		t.Error("expected symlink to be removed")
+	}
+	if err := archive.Rollback(); !errors.Is(err, ErrNothingToRollback) {
+		t.Errorf("Rollback() expected ErrNothingToRollback, got %v", err)
+	}
+}
+
+func TestArchiveStorage_WriteRejected(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []archiveEntry
+		opts    []ArchiveStorageOpt
+		wantErr error
+	}{
+		{
+			name:    "parent directory",
+			entries: []archiveEntry{{name: "../evil", content: "x"}},
+			wantErr: ErrArchivePathTraversal,
+		},
+		{
+			name:    "nested parent directory",
+			entries: []archiveEntry{{name: "a/../../evil", content: "x"}},
+			wantErr: ErrArchivePathTraversal,
+		},
+		{
+			name:    "absolute path",
+			entries: []archiveEntry{{name: "/etc/evil", content: "x"}},
+			wantErr: ErrArchivePathTraversal,
+		},
+		{
+			name:    "symlink escaping destination",
+			entries: []archiveEntry{{name: "link", linkname: "../../etc"}},
+			wantErr: ErrArchivePathTraversal,
+		},
+		{
+			name: "chained symlinks escaping destination",
+			entries: []archiveEntry{
+				{name: "l", linkname: "."},
+				{name: "e", linkname: "l/l/l/l/l/l/l/l/../../../../../../../.."},
+			},
+			wantErr: ErrArchivePathTraversal,
+		},
+		{
+			name:    "symlink to parent within destination",
+			entries: []archiveEntry{{name: "dir/link", linkname: "../index.html"}},
+			wantErr: ErrArchivePathTraversal,
+		},
+		{
+			name:    "absolute symlink",
+			entries: []archiveEntry{{name: "link", linkname: "/etc"}},
+			wantErr: ErrArchivePathTraversal,
+		},
+		{
+			name: "write through symlink",
+			entries: []archiveEntry{
+				{name: "dir", dir: true},
+				{name: "link", linkname: "dir"},
+				{name: "link/evil", content: "x"},
+			},
+			wantErr: ErrArchivePathTraversal,
+		},
+		{
+			name:    "too large",
+			entries: []archiveEntry{{name: "a", content: "12345"}, {name: "b", content: "67890"}},
+			opts:    []ArchiveStorageOpt{WithArchiveMaxBytes(8)},
+			wantErr: ErrArchiveTooLarge,
+		},
+		{
+			name:    "too many files",
+			entries: []archiveEntry{{name: "a", content: "1"}, {name: "b", content: "2"}},
+			opts:    []ArchiveStorageOpt{WithArchiveMaxFiles(1)},
+			wantErr: ErrArchiveTooLarge,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := t.TempDir()
+			dest := filepath.Join(parent, "current")
+			archive, err := NewArchiveStorage(dest, ArchiveFormatTarGz, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := archive.Write(buildTarGz(t, tt.entries)); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Write() expected %v, got %v", tt.wantErr, err)
+			}
+
+			entries, _ := os.ReadDir(parent)
+			if len(entries) != 0 {
+				t.Errorf("expected no leftovers after failed extraction, got %d entries", len(entries))
+			}
+		})
+	}
+}
+
+func TestArchiveFormatFromName(t *testing.T) {
+	tests := map[string]string{
+		"/releases/site.tar.gz": ArchiveFormatTarGz,
+		"/releases/site.tgz":    ArchiveFormatTarGz,
+		"/releases/site.ZIP":    ArchiveFormatZip,
+		"/releases/site.tar":    "",
+	}
+	for name, want := range tests {
+		if got := ArchiveFormatFromName(name); got != want {
+			t.Errorf("ArchiveFormatFromName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestContentMatches(t *testing.T) {
+	tests := []struct {
+		name    string
+		written string
+		stored  string
+		want    bool
+	}{
+		{
+			name:    "identical",
+			written: "secret\n",
+			stored:  "secret\n",
+			want:    true,
+		},
+		{
+			name:    "newline added by storage",
+			written: "secret",
+			stored:  "secret\n",
+			want:    true,
+		},
+		{
+			name:    "leading whitespace",
+			written: "secret",
+			stored:  " secret",
+			want:    false,
+		},
+		{
+			name:    "additional trailing newlines",
+			written: "secret",
+			stored:  "secret\n\n",
+			want:    false,
+		},
+		{
+			name:    "missing trailing newline",
+			written: "secret\n",
+			stored:  "secret",
+			want:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ContentMatches([]byte(tt.stored), ContentHash([]byte(tt.written))); got != tt.want {
+				t.Errorf("ContentMatches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
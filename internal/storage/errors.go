@@ -2,4 +2,7 @@ package storage
 
 import "errors"
 
-var ErrNoCertFound = errors.New("data not found")
+var (
+	ErrNoCertFound       = errors.New("data not found")
+	ErrNothingToRollback = errors.New("nothing to roll back")
+)
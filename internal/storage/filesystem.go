@@ -1,6 +1,8 @@
 package storage
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"errors"
 	"fmt"
 	"io/fs"
@@ -26,12 +28,28 @@ type FilesystemStorage struct {
 	FileOwner string
 	FileGroup string
 	Mode      os.FileMode
-	fs        afero.Fs
+	// Backups is the number of backups of previous contents that are retained as "<file>.bak.<n>"
+	Backups int
+	fs      afero.Fs
+
+	// encryption encrypts the content before it is written, nil if the content is written in plaintext
+	encryption contentEncryption
+	// written holds the plaintext and the hash of the ciphertext of the latest encrypted write. It allows detecting
+	// changes of content that can not be decrypted, e.g. if only age recipients but no identity are configured.
+	written *encryptedWrite
+
+	// previous holds the content of the file before the latest write to allow a rollback
+	previous        []byte
+	previousExisted bool
+	canRollback     bool
 }
 
 const (
-	FsScheme   = "file"
-	ParamChmod = "chmod"
+	FsScheme     = "file"
+	ParamChmod   = "chmod"
+	ParamBackups = "backups"
+
+	maxBackups = 100
 )
 
 var (
@@ -69,6 +87,7 @@ func NewFilesystemStorageFromUri(uri string) (*FilesystemStorage, error) {
 	}
 
 	mode := defaultFileMode
+	backups := 0
 	params, err := url.ParseQuery(parsed.RawQuery)
 	if err != nil {
 		return nil, fmt.Errorf("could not parse queries")
@@ -85,12 +104,26 @@ func NewFilesystemStorageFromUri(uri string) (*FilesystemStorage, error) {
 				return nil, fmt.Errorf("invalid file mode supplied: %v", val[0])
 			}
 		}
+		if key == ParamBackups {
+			backups, err = strconv.Atoi(val[0])
+			if err != nil || backups < 0 || backups > maxBackups {
+				return nil, fmt.Errorf("invalid value for 'backups' param: %v", val)
+			}
+		}
 	}
 
 	if len(path) == 0 {
 		return nil, errors.New("empty path provided")
 	}
 
+	encryption, err := newEncryptionFromParams(params)
+	if err != nil {
+		return nil, err
+	}
+	if ageEnc, ok := encryption.(*ageEncryption); ok && len(ageEnc.identities) == 0 {
+		log.Warn().Str("component", "cert_storage").Str("file", path).Msg("no age identity configured, the encrypted content is rewritten once after each restart")
+	}
+
 	// usually, uid and gid are resolved dynamically to support users/groups that are added after sc-agent has started
 	// by trying to resolve it now, we make sure to fail fast on systems that we don't support, e.g. Windows
 	_, _, err = resolveUidAndGid(username, group)
@@ -99,20 +132,44 @@ func NewFilesystemStorageFromUri(uri string) (*FilesystemStorage, error) {
 	}
 
 	return &FilesystemStorage{
-		FilePath:  path,
-		FileOwner: username,
-		FileGroup: group,
-		Mode:      mode,
-		fs:        afero.NewOsFs(),
+		FilePath:   path,
+		FileOwner:  username,
+		FileGroup:  group,
+		Mode:       mode,
+		Backups:    backups,
+		fs:         afero.NewOsFs(),
+		encryption: encryption,
 	}, nil
 }
 
+type encryptedWrite struct {
+	plaintext      []byte
+	ciphertextHash [sha256.Size]byte
+}
+
+// IsEncrypted returns whether the content is encrypted before it is written.
+func (fss *FilesystemStorage) IsEncrypted() bool {
+	return fss.encryption != nil
+}
+
 func (fss *FilesystemStorage) Read() ([]byte, error) {
 	data, err := afero.ReadFile(fss.fs, fss.FilePath)
 	if err != nil {
 		return nil, err
 	}
 
+	if fss.encryption != nil {
+		ciphertext := data
+		data, err = fss.encryption.decrypt(ciphertext)
+		if errors.Is(err, ErrNoAgeIdentity) && fss.written != nil && sha256.Sum256(ciphertext) == fss.written.ciphertextHash {
+			// the file has not been changed since it has been written by us
+			data, err = bytes.Clone(fss.written.plaintext), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Check and fix file permissions, ownership, and group
 	if err := fss.checkAndFixFileState(); err != nil {
 		// Log the error but don't fail the read operation
@@ -132,6 +189,16 @@ func (fss *FilesystemStorage) Write(signedData []byte) error {
 		signedData = append(signedData, '\n')
 	}
 
+	var written *encryptedWrite
+	if fss.encryption != nil {
+		encrypted, err := fss.encryption.encrypt(signedData)
+		if err != nil {
+			return err
+		}
+		written = &encryptedWrite{plaintext: bytes.Clone(signedData), ciphertextHash: sha256.Sum256(encrypted)}
+		signedData = encrypted
+	}
+
 	uid, gid, err := resolveUidAndGid(fss.FileOwner, fss.FileGroup)
 	if err != nil {
 		return fmt.Errorf("could not resolve uid and gid for file '%s': %v", fss.FilePath, err)
@@ -157,14 +224,163 @@ func (fss *FilesystemStorage) Write(signedData []byte) error {
 		}
 	}
 
-	if err := afero.WriteFile(fss.fs, fss.FilePath, signedData, fss.Mode); err != nil {
+	previous, err := afero.ReadFile(fss.fs, fss.FilePath)
+	previousExisted := err == nil
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("could not read current content of file '%s': %v", fss.FilePath, err)
+	}
+
+	if previousExisted && fss.Backups > 0 {
+		if err := fss.writeBackup(previous, uid, gid); err != nil {
+			return err
+		}
+	}
+
+	if err := fss.writeAtomic(signedData, uid, gid); err != nil {
+		return err
+	}
+
+	fss.previous = previous
+	fss.previousExisted = previousExisted
+	fss.canRollback = true
+	fss.written = written
+	return nil
+}
+
+// Rollback restores the content of the file before the latest write. If the file did not exist before, it is
+// removed.
+func (fss *FilesystemStorage) Rollback() error {
+	if !fss.canRollback {
+		return ErrNothingToRollback
+	}
+
+	if !fss.previousExisted {
+		if err := fss.Delete(); err != nil {
+			return err
+		}
+	} else {
+		uid, gid, err := resolveUidAndGid(fss.FileOwner, fss.FileGroup)
+		if err != nil {
+			return fmt.Errorf("could not resolve uid and gid for file '%s': %v", fss.FilePath, err)
+		}
+
+		if err := fss.writeAtomic(fss.previous, uid, gid); err != nil {
+			return err
+		}
+	}
+
+	fss.canRollback = false
+	fss.previous = nil
+	fss.written = nil
+	return nil
+}
+
+// writeAtomic writes the data to a temporary file in the same directory which is then renamed, so readers never
+// see partially written files.
+func (fss *FilesystemStorage) writeAtomic(data []byte, uid, gid int) error {
+	dir, base := filepath.Dir(fss.FilePath), filepath.Base(fss.FilePath)
+	tmp, err := afero.TempFile(fss.fs, dir, "."+base+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("could not create temporary file for '%s': %v", fss.FilePath, err)
+	}
+
+	tmpName := tmp.Name()
+	cleanup := func() {
+		_ = tmp.Close()
+		_ = fss.fs.Remove(tmpName)
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		cleanup()
 		return fmt.Errorf("could not write file '%s' to disk: %v", fss.FilePath, err)
 	}
 
-	if err := fss.fs.Chown(fss.FilePath, uid, gid); err != nil {
+	if err := tmp.Sync(); err != nil {
+		cleanup()
+		return fmt.Errorf("could not sync file '%s' to disk: %v", fss.FilePath, err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		_ = fss.fs.Remove(tmpName)
+		return fmt.Errorf("could not close file '%s': %v", fss.FilePath, err)
+	}
+
+	if err := fss.fs.Chown(tmpName, uid, gid); err != nil {
+		_ = fss.fs.Remove(tmpName)
 		return fmt.Errorf("could not chown file '%s': %v", fss.FilePath, err)
 	}
 
+	// the mode of temporary files is always 0600, it is set explicitly before the file becomes visible
+	if err := fss.fs.Chmod(tmpName, fss.Mode); err != nil {
+		_ = fss.fs.Remove(tmpName)
+		return fmt.Errorf("could not chmod file '%s': %v", fss.FilePath, err)
+	}
+
+	if err := fss.fs.Rename(tmpName, fss.FilePath); err != nil {
+		_ = fss.fs.Remove(tmpName)
+		return fmt.Errorf("could not rename temporary file to '%s': %v", fss.FilePath, err)
+	}
+
+	fss.syncDir(dir)
+	return nil
+}
+
+// writeBackup rotates existing backups and writes the given data as most recent backup "<file>.bak.1".
+func (fss *FilesystemStorage) writeBackup(data []byte, uid, gid int) error {
+	for idx := fss.Backups - 1; idx >= 1; idx-- {
+		from := fss.backupPath(idx)
+		if _, err := fss.fs.Stat(from); err != nil {
+			continue
+		}
+		if err := fss.fs.Rename(from, fss.backupPath(idx+1)); err != nil {
+			return fmt.Errorf("could not rotate backup '%s': %v", from, err)
+		}
+	}
+
+	backup := fss.backupPath(1)
+	if err := afero.WriteFile(fss.fs, backup, data, fss.Mode); err != nil {
+		return fmt.Errorf("could not write backup '%s': %v", backup, err)
+	}
+
+	if err := fss.fs.Chown(backup, uid, gid); err != nil {
+		return fmt.Errorf("could not chown backup '%s': %v", backup, err)
+	}
+
+	if err := fss.fs.Chmod(backup, fss.Mode); err != nil {
+		return fmt.Errorf("could not chmod backup '%s': %v", backup, err)
+	}
+
+	return nil
+}
+
+func (fss *FilesystemStorage) backupPath(idx int) string {
+	return fmt.Sprintf("%s.bak.%d", fss.FilePath, idx)
+}
+
+// syncDir persists the rename by syncing the directory, this is only supported for the OS filesystem.
+func (fss *FilesystemStorage) syncDir(dir string) {
+	if _, ok := fss.fs.(*afero.OsFs); !ok {
+		return
+	}
+
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = d.Close()
+	}()
+
+	if err := d.Sync(); err != nil {
+		log.Warn().Err(err).Str("component", "cert_storage").Str("dir", dir).Msg("could not sync directory")
+	}
+}
+
+// Delete removes the file, it does not return an error if the file does not exist.
+func (fss *FilesystemStorage) Delete() error {
+	if err := fss.fs.Remove(fss.FilePath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("could not remove file '%s': %v", fss.FilePath, err)
+	}
 	return nil
 }
 
@@ -1,10 +1,14 @@
 package storage
 
 import (
+	"errors"
 	"os"
 	"os/user"
 	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/spf13/afero"
 )
 
 func getCurrentUsername() string {
@@ -86,3 +90,100 @@ func compareFilesystemStorage(a, b *FilesystemStorage) bool {
 	b.fs = nil
 	return reflect.DeepEqual(a, b)
 }
+
+func newMemFilesystemStorage(backups int) *FilesystemStorage {
+	return &FilesystemStorage{
+		FilePath:  "/etc/app/secret",
+		FileOwner: getCurrentUsername(),
+		FileGroup: getOsDependentGroup(),
+		Mode:      0640,
+		Backups:   backups,
+		fs:        afero.NewMemMapFs(),
+	}
+}
+
+func TestFilesystemStorage_WriteBackups(t *testing.T) {
+	fss := newMemFilesystemStorage(2)
+
+	for _, content := range []string{"one", "two", "three", "four"} {
+		if err := fss.Write([]byte(content)); err != nil {
+			t.Fatalf("Write() unexpected error: %v", err)
+		}
+	}
+
+	want := map[string]string{
+		"/etc/app/secret":       "four\n",
+		"/etc/app/secret.bak.1": "three\n",
+		"/etc/app/secret.bak.2": "two\n",
+	}
+	for file, content := range want {
+		got, err := afero.ReadFile(fss.fs, file)
+		if err != nil {
+			t.Fatalf("could not read %s: %v", file, err)
+		}
+		if string(got) != content {
+			t.Errorf("%s = %q, want %q", file, got, content)
+		}
+	}
+
+	if exists, _ := afero.Exists(fss.fs, "/etc/app/secret.bak.3"); exists {
+		t.Error("expected only two backups to be retained")
+	}
+
+	info, err := fss.fs.Stat("/etc/app/secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0640 {
+		t.Errorf("mode = %o, want %o", info.Mode().Perm(), 0640)
+	}
+
+	entries, _ := afero.ReadDir(fss.fs, "/etc/app")
+	for _, entry := range entries {
+		if strings.Contains(entry.Name(), ".tmp-") {
+			t.Errorf("temporary file %s has not been cleaned up", entry.Name())
+		}
+	}
+}
+
+func TestFilesystemStorage_Rollback(t *testing.T) {
+	fss := newMemFilesystemStorage(0)
+
+	if err := fss.Rollback(); !errors.Is(err, ErrNothingToRollback) {
+		t.Fatalf("Rollback() expected ErrNothingToRollback, got %v", err)
+	}
+
+	// rolling back the initial write removes the file
+	if err := fss.Write([]byte("one")); err != nil {
+		t.Fatal(err)
+	}
+	if err := fss.Rollback(); err != nil {
+		t.Fatalf("Rollback() unexpected error: %v", err)
+	}
+	if exists, _ := afero.Exists(fss.fs, fss.FilePath); exists {
+		t.Error("expected file to be removed")
+	}
+
+	if err := fss.Write([]byte("one")); err != nil {
+		t.Fatal(err)
+	}
+	if err := fss.Write([]byte("two")); err != nil {
+		t.Fatal(err)
+	}
+	if err := fss.Rollback(); err != nil {
+		t.Fatalf("Rollback() unexpected error: %v", err)
+	}
+
+	got, err := afero.ReadFile(fss.fs, fss.FilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "one\n" {
+		t.Errorf("content after rollback = %q, want %q", got, "one\n")
+	}
+
+	// a rollback is only possible once per write
+	if err := fss.Rollback(); !errors.Is(err, ErrNothingToRollback) {
+		t.Errorf("Rollback() expected ErrNothingToRollback, got %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	KeyringScheme = "keyring"
+)
+
+// KeyringStorage stores data as "user" key in the Linux kernel keyring. The URI is of the form
+// "keyring://<keyring>/<description>", where keyring is one of "user", "session", "process" or "thread".
+type KeyringStorage struct {
+	Keyring     string
+	Description string
+	ringId      int
+
+	// encryption encrypts the content before it is written, nil if the content is written in plaintext
+	encryption contentEncryption
+}
+
+func NewKeyringStorageFromUri(uri string) (*KeyringStorage, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	if parsed.Scheme != KeyringScheme {
+		return nil, fmt.Errorf("expected scheme %q, got %q", KeyringScheme, parsed.Scheme)
+	}
+
+	ringId, err := getKeyringId(parsed.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	description := strings.TrimPrefix(parsed.Path, "/")
+	if len(description) == 0 {
+		return nil, fmt.Errorf("empty key description provided")
+	}
+
+	params, err := url.ParseQuery(parsed.RawQuery)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse queries")
+	}
+
+	encryption, err := newEncryptionFromParams(params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KeyringStorage{
+		Keyring:     parsed.Host,
+		Description: description,
+		ringId:      ringId,
+		encryption:  encryption,
+	}, nil
+}
@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+const keyringKeyType = "user"
+
+func getKeyringId(keyring string) (int, error) {
+	switch keyring {
+	case "user":
+		return unix.KEY_SPEC_USER_KEYRING, nil
+	case "session":
+		return unix.KEY_SPEC_SESSION_KEYRING, nil
+	case "process":
+		return unix.KEY_SPEC_PROCESS_KEYRING, nil
+	case "thread":
+		return unix.KEY_SPEC_THREAD_KEYRING, nil
+	default:
+		return 0, fmt.Errorf("unknown keyring %q", keyring)
+	}
+}
+
+func (k *KeyringStorage) Read() ([]byte, error) {
+	id, err := k.search()
+	if err != nil {
+		return nil, err
+	}
+
+	size, err := unix.KeyctlBuffer(unix.KEYCTL_READ, id, nil, 0)
+	if err != nil {
+		return nil, fmt.Errorf("could not read key %q: %w", k.Description, err)
+	}
+
+	// the key may be updated between both calls, the returned size is therefore checked again
+	buf := make([]byte, size)
+	read, err := unix.KeyctlBuffer(unix.KEYCTL_READ, id, buf, 0)
+	if err != nil {
+		return nil, fmt.Errorf("could not read key %q: %w", k.Description, err)
+	}
+	if read > size {
+		return nil, fmt.Errorf("key %q has been modified while reading", k.Description)
+	}
+
+	if k.encryption != nil {
+		return k.encryption.decrypt(buf[:read])
+	}
+
+	return buf[:read], nil
+}
+
+func (k *KeyringStorage) CanRead() error {
+	_, err := k.search()
+	return err
+}
+
+func (k *KeyringStorage) Write(data []byte) error {
+	if k.encryption != nil {
+		encrypted, err := k.encryption.encrypt(data)
+		if err != nil {
+			return err
+		}
+		data = encrypted
+	}
+
+	// adding a key with an existing description to the same keyring updates the key's payload
+	if _, err := unix.AddKey(keyringKeyType, k.Description, data, k.ringId); err != nil {
+		return fmt.Errorf("could not write key %q to %s keyring: %w", k.Description, k.Keyring, err)
+	}
+
+	return nil
+}
+
+// Delete unlinks the key from the keyring, it does not return an error if the key does not exist.
+func (k *KeyringStorage) Delete() error {
+	id, err := k.search()
+	if err != nil {
+		if errors.Is(err, ErrNoCertFound) {
+			return nil
+		}
+		return err
+	}
+
+	if _, err := unix.KeyctlInt(unix.KEYCTL_UNLINK, id, k.ringId, 0, 0); err != nil {
+		return fmt.Errorf("could not unlink key %q: %w", k.Description, err)
+	}
+
+	return nil
+}
+
+func (k *KeyringStorage) search() (int, error) {
+	id, err := unix.KeyctlSearch(k.ringId, keyringKeyType, k.Description, 0)
+	if err != nil {
+		if errors.Is(err, unix.ENOKEY) || errors.Is(err, unix.EKEYEXPIRED) || errors.Is(err, unix.EKEYREVOKED) {
+			return 0, ErrNoCertFound
+		}
+		return 0, fmt.Errorf("could not search key %q: %w", k.Description, err)
+	}
+
+	return id, nil
+}
@@ -0,0 +1,27 @@
+//go:build !linux
+
+package storage
+
+import "errors"
+
+var errKeyringUnsupported = errors.New("the kernel keyring is only supported on linux")
+
+func getKeyringId(_ string) (int, error) {
+	return 0, errKeyringUnsupported
+}
+
+func (k *KeyringStorage) Read() ([]byte, error) {
+	return nil, errKeyringUnsupported
+}
+
+func (k *KeyringStorage) CanRead() error {
+	return errKeyringUnsupported
+}
+
+func (k *KeyringStorage) Write(_ []byte) error {
+	return errKeyringUnsupported
+}
+
+func (k *KeyringStorage) Delete() error {
+	return errKeyringUnsupported
+}
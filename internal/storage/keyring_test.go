@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"testing"
+)
+
+func TestNewKeyringStorageFromUri(t *testing.T) {
+	tests := []struct {
+		name            string
+		uri             string
+		wantKeyring     string
+		wantDescription string
+		wantErr         bool
+	}{
+		{
+			name:            "User keyring",
+			uri:             "keyring://user/sc-agent:db-password",
+			wantKeyring:     "user",
+			wantDescription: "sc-agent:db-password",
+		},
+		{
+			name:            "Session keyring",
+			uri:             "keyring://session/db",
+			wantKeyring:     "session",
+			wantDescription: "db",
+		},
+		{
+			name:    "Unknown keyring",
+			uri:     "keyring://unknown/db",
+			wantErr: true,
+		},
+		{
+			name:    "Missing description",
+			uri:     "keyring://user/",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewKeyringStorageFromUri(tt.uri)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewKeyringStorageFromUri() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Keyring != tt.wantKeyring || got.Description != tt.wantDescription {
+				t.Errorf("NewKeyringStorageFromUri() got = %v/%v, want %v/%v", got.Keyring, got.Description, tt.wantKeyring, tt.wantDescription)
+			}
+		})
+	}
+}
@@ -141,3 +141,12 @@ func (fs *MultiFilesystem) CanWrite() error {
 
 	return errs
 }
+
+// Rollback restores the previous content of all files.
+func (fs *MultiFilesystem) Rollback() error {
+	storages := make([]any, len(fs.storage))
+	for idx := range fs.storage {
+		storages[idx] = fs.storage[idx]
+	}
+	return RollbackAll(storages...)
+}
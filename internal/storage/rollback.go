@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rs/zerolog/log"
+	"github.com/soerenschneider/sc-agent/internal/events"
+	"github.com/soerenschneider/sc-agent/internal/metrics"
+	"go.uber.org/multierr"
+)
+
+const eventTypeRollback = "storage.rollback"
+
+// Restorable is implemented by storage implementations that are able to restore the content they held before their
+// latest write.
+type Restorable interface {
+	Rollback() error
+}
+
+type rollbackEvent struct {
+	Component string `json:"component"`
+	Id        string `json:"id"`
+	Reason    string `json:"reason"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Rollback restores the previous content of all given storage implementations that support it, e.g. after a post
+// hook rejected the newly written content. Implementations that do not support rollbacks are ignored. The rollback is
+// reported via metrics and events.
+func Rollback(ctx context.Context, component, id string, reason error, impls ...any) error {
+	errs := RollbackAll(impls...)
+	if errors.Is(errs, ErrNothingToRollback) {
+		return nil
+	}
+
+	data := rollbackEvent{
+		Component: component,
+		Id:        id,
+		Reason:    reason.Error(),
+	}
+
+	if errs != nil {
+		data.Error = errs.Error()
+		metrics.StorageRollbacks.WithLabelValues(component, id, "failed").Inc()
+		log.Error().Err(errs).Str("component", component).Str("id", id).Msg("could not roll back to previous content")
+	} else {
+		metrics.StorageRollbacks.WithLabelValues(component, id, "success").Inc()
+		log.Warn().Err(reason).Str("component", component).Str("id", id).Msg("rolled back to previous content")
+	}
+
+	if err := events.NewEvent(ctx, id, eventTypeRollback, data); err != nil && !errors.Is(err, events.ErrNoEventSinkConfigured) {
+		log.Warn().Err(err).Str("component", component).Str("id", id).Msg("could not emit event")
+	}
+
+	return errs
+}
+
+// RollbackAll rolls back all given storage implementations that support it. It returns ErrNothingToRollback if none
+// of them had anything to roll back.
+func RollbackAll(impls ...any) error {
+	var errs error
+	rolledBack := false
+	for _, impl := range impls {
+		restorable, ok := impl.(Restorable)
+		if !ok || impl == nil {
+			continue
+		}
+
+		if err := restorable.Rollback(); err != nil {
+			if errors.Is(err, ErrNothingToRollback) {
+				continue
+			}
+			errs = multierr.Append(errs, err)
+		}
+		rolledBack = true
+	}
+
+	if !rolledBack && errs == nil {
+		return ErrNothingToRollback
+	}
+	return errs
+}
@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	SystemdCredsScheme       = "systemd-creds"
+	ParamSystemdCredsDir     = "dir"
+	ParamSystemdCredsWithKey = "with_key"
+
+	defaultSystemdCredsDir  = "/etc/credstore.encrypted"
+	systemdCredsCmdTimeout  = 30 * time.Second
+	systemdCredsDirFileMode = 0700
+)
+
+type commandRunner func(ctx context.Context, stdin []byte, name string, args ...string) ([]byte, error)
+
+// SystemdCredsStorage encrypts data using "systemd-creds encrypt" so it can be consumed by services using
+// LoadCredentialEncrypted=. The URI is of the form "systemd-creds://<name>?dir=/etc/credstore.encrypted&with_key=host".
+type SystemdCredsStorage struct {
+	Name    string
+	Dir     string
+	WithKey string
+	run     commandRunner
+}
+
+func NewSystemdCredsStorageFromUri(uri string) (*SystemdCredsStorage, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	if parsed.Scheme != SystemdCredsScheme {
+		return nil, fmt.Errorf("expected scheme %q, got %q", SystemdCredsScheme, parsed.Scheme)
+	}
+
+	name := parsed.Host
+	if len(name) == 0 || strings.ContainsAny(name, "/\\") || name == "." || name == ".." {
+		return nil, fmt.Errorf("invalid credential name %q", name)
+	}
+
+	params, err := url.ParseQuery(parsed.RawQuery)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse queries")
+	}
+
+	dir := defaultSystemdCredsDir
+	if params.Has(ParamSystemdCredsDir) {
+		dir = params.Get(ParamSystemdCredsDir)
+		if !filepath.IsAbs(dir) {
+			return nil, fmt.Errorf("credential directory %q must be an absolute path", dir)
+		}
+	}
+
+	return &SystemdCredsStorage{
+		Name:    name,
+		Dir:     dir,
+		WithKey: params.Get(ParamSystemdCredsWithKey),
+		run:     runCommand,
+	}, nil
+}
+
+func (s *SystemdCredsStorage) FilePath() string {
+	return filepath.Join(s.Dir, s.Name)
+}
+
+func (s *SystemdCredsStorage) Read() ([]byte, error) {
+	if err := s.CanRead(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), systemdCredsCmdTimeout)
+	defer cancel()
+
+	data, err := s.run(ctx, nil, "systemd-creds", "decrypt", "--name="+s.Name, s.FilePath(), "-")
+	if err != nil {
+		return nil, fmt.Errorf("could not decrypt credential %q: %w", s.Name, err)
+	}
+
+	return data, nil
+}
+
+func (s *SystemdCredsStorage) CanRead() error {
+	_, err := os.Stat(s.FilePath())
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrNoCertFound
+	}
+	return err
+}
+
+func (s *SystemdCredsStorage) Write(data []byte) error {
+	if err := os.MkdirAll(s.Dir, systemdCredsDirFileMode); err != nil {
+		return fmt.Errorf("could not create directory %q: %w", s.Dir, err)
+	}
+
+	args := []string{"encrypt", "--name=" + s.Name}
+	if len(s.WithKey) > 0 {
+		args = append(args, "--with-key="+s.WithKey)
+	}
+	args = append(args, "-", s.FilePath())
+
+	ctx, cancel := context.WithTimeout(context.Background(), systemdCredsCmdTimeout)
+	defer cancel()
+
+	if _, err := s.run(ctx, data, "systemd-creds", args...); err != nil {
+		return fmt.Errorf("could not encrypt credential %q: %w", s.Name, err)
+	}
+
+	return nil
+}
+
+// Delete removes the encrypted credential, it does not return an error if it does not exist.
+func (s *SystemdCredsStorage) Delete() error {
+	if err := os.Remove(s.FilePath()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("could not remove credential %q: %w", s.Name, err)
+	}
+	return nil
+}
+
+func runCommand(ctx context.Context, stdin []byte, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...) // #nosec G204
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return out, nil
+}
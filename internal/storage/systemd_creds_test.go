@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestNewSystemdCredsStorageFromUri(t *testing.T) {
+	tests := []struct {
+		name    string
+		uri     string
+		want    *SystemdCredsStorage
+		wantErr bool
+	}{
+		{
+			name: "Simple",
+			uri:  "systemd-creds://db-password",
+			want: &SystemdCredsStorage{
+				Name: "db-password",
+				Dir:  defaultSystemdCredsDir,
+			},
+		},
+		{
+			name: "Custom dir and key",
+			uri:  "systemd-creds://db-password?dir=/run/credstore.encrypted&with_key=host",
+			want: &SystemdCredsStorage{
+				Name:    "db-password",
+				Dir:     "/run/credstore.encrypted",
+				WithKey: "host",
+			},
+		},
+		{
+			name:    "Relative dir",
+			uri:     "systemd-creds://db-password?dir=credstore",
+			wantErr: true,
+		},
+		{
+			name:    "No name",
+			uri:     "systemd-creds:///db-password",
+			wantErr: true,
+		},
+		{
+			name:    "Wrong scheme",
+			uri:     "file:///db-password",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewSystemdCredsStorageFromUri(tt.uri)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewSystemdCredsStorageFromUri() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != nil {
+				got.run = nil
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewSystemdCredsStorageFromUri() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSystemdCredsStorage_WriteRead(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "credstore.encrypted")
+	var invocations [][]string
+
+	// fake systemd-creds that "encrypts" by writing the plaintext to the output file
+	run := func(_ context.Context, stdin []byte, name string, args ...string) ([]byte, error) {
+		invocations = append(invocations, append([]string{name}, args...))
+		switch args[0] {
+		case "encrypt":
+			return nil, os.WriteFile(args[len(args)-1], stdin, 0600)
+		case "decrypt":
+			return os.ReadFile(args[len(args)-2])
+		}
+		return nil, errors.New("unexpected command")
+	}
+
+	s := &SystemdCredsStorage{Name: "db", Dir: dir, WithKey: "host", run: run}
+	if err := s.CanRead(); !errors.Is(err, ErrNoCertFound) {
+		t.Fatalf("CanRead() expected ErrNoCertFound, got %v", err)
+	}
+
+	if err := s.Write([]byte("secret")); err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+
+	got, err := s.Read()
+	if err != nil {
+		t.Fatalf("Read() unexpected error: %v", err)
+	}
+	if string(got) != "secret" {
+		t.Errorf("Read() got = %q, want %q", got, "secret")
+	}
+
+	wantInvocations := [][]string{
+		{"systemd-creds", "encrypt", "--name=db", "--with-key=host", "-", filepath.Join(dir, "db")},
+		{"systemd-creds", "decrypt", "--name=db", filepath.Join(dir, "db"), "-"},
+	}
+	if !reflect.DeepEqual(invocations, wantInvocations) {
+		t.Errorf("invocations got = %v, want %v", invocations, wantInvocations)
+	}
+}
@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ParamTransitKey   = "transit"
+	ParamTransitMount = "transit_mount"
+	ParamTransitVault = "transit_vault"
+
+	defaultTransitMount = "transit"
+	transitTimeout      = 30 * time.Second
+)
+
+var (
+	ErrNoTransitEngine = errors.New("no transit engine configured")
+
+	transitResolver      TransitEngineResolver
+	transitResolverMutex sync.RWMutex
+)
+
+// TransitEngine encrypts and decrypts content using a named key of Vault's transit secrets engine.
+type TransitEngine interface {
+	Encrypt(ctx context.Context, key string, plaintext []byte) (string, error)
+	Decrypt(ctx context.Context, key string, ciphertext string) ([]byte, error)
+}
+
+// TransitEngineResolver returns the transit engine mounted at mount of the Vault client with the given id.
+type TransitEngineResolver func(vaultId, mount string) (TransitEngine, error)
+
+// SetTransitEngineResolver configures how transit engines referenced by storage URIs are resolved. The resolver is
+// invoked lazily on each read or write, so storage URIs can be parsed before the Vault clients have been built.
+func SetTransitEngineResolver(resolver TransitEngineResolver) {
+	transitResolverMutex.Lock()
+	defer transitResolverMutex.Unlock()
+	transitResolver = resolver
+}
+
+func getTransitEngine(vaultId, mount string) (TransitEngine, error) {
+	transitResolverMutex.RLock()
+	defer transitResolverMutex.RUnlock()
+
+	if transitResolver == nil {
+		return nil, ErrNoTransitEngine
+	}
+	return transitResolver(vaultId, mount)
+}
+
+// transitEncryption encrypts content using Vault's transit secrets engine, so it is only readable while the host can
+// authenticate to Vault.
+type transitEncryption struct {
+	vaultId string
+	mount   string
+	key     string
+}
+
+// newTransitEncryptionFromParams builds the encryption from the query parameters of a storage URI. It returns nil if
+// no transit key is configured.
+func newTransitEncryptionFromParams(params map[string][]string) (*transitEncryption, error) {
+	keys := params[ParamTransitKey]
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	if len(keys) > 1 || strings.TrimSpace(keys[0]) == "" {
+		return nil, errors.New("exactly one transit key must be configured")
+	}
+
+	ret := &transitEncryption{
+		key:   keys[0],
+		mount: defaultTransitMount,
+	}
+
+	if mount := params[ParamTransitMount]; len(mount) > 0 && mount[0] != "" {
+		ret.mount = mount[0]
+	}
+
+	if vaultId := params[ParamTransitVault]; len(vaultId) > 0 {
+		ret.vaultId = vaultId[0]
+	}
+
+	return ret, nil
+}
+
+func (t *transitEncryption) encrypt(plaintext []byte) ([]byte, error) {
+	engine, err := getTransitEngine(t.vaultId, t.mount)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), transitTimeout)
+	defer cancel()
+
+	ciphertext, err := engine.Encrypt(ctx, t.key, plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("could not encrypt content using transit key %q: %w", t.key, err)
+	}
+
+	return []byte(ciphertext + "\n"), nil
+}
+
+func (t *transitEncryption) decrypt(ciphertext []byte) ([]byte, error) {
+	engine, err := getTransitEngine(t.vaultId, t.mount)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), transitTimeout)
+	defer cancel()
+
+	plaintext, err := engine.Decrypt(ctx, t.key, strings.TrimSpace(string(ciphertext)))
+	if err != nil {
+		return nil, fmt.Errorf("could not decrypt content using transit key %q: %w", t.key, err)
+	}
+
+	return plaintext, nil
+}
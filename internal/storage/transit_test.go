@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+type fakeTransitEngine struct {
+	key string
+}
+
+func (f *fakeTransitEngine) Encrypt(_ context.Context, key string, plaintext []byte) (string, error) {
+	if key != f.key {
+		return "", errors.New("encryption key not found")
+	}
+	return "vault:v1:" + base64.StdEncoding.EncodeToString(plaintext), nil
+}
+
+func (f *fakeTransitEngine) Decrypt(_ context.Context, key string, ciphertext string) ([]byte, error) {
+	if key != f.key || !strings.HasPrefix(ciphertext, "vault:v1:") {
+		return nil, errors.New("invalid ciphertext")
+	}
+	return base64.StdEncoding.DecodeString(strings.TrimPrefix(ciphertext, "vault:v1:"))
+}
+
+func TestFilesystemStorage_TransitEncryption(t *testing.T) {
+	t.Cleanup(func() {
+		SetTransitEngineResolver(nil)
+	})
+
+	fss, err := NewFilesystemStorageFromUri("file:///var/lib/sc-agent/journal?transit=agent-key&transit_vault=default")
+	if err != nil {
+		t.Fatalf("NewFilesystemStorageFromUri() unexpected error: %v", err)
+	}
+	fss.fs = afero.NewMemMapFs()
+
+	if err := fss.Write([]byte("secret")); !errors.Is(err, ErrNoTransitEngine) {
+		t.Fatalf("Write() expected ErrNoTransitEngine, got %v", err)
+	}
+
+	var gotVaultId, gotMount string
+	SetTransitEngineResolver(func(vaultId, mount string) (TransitEngine, error) {
+		gotVaultId, gotMount = vaultId, mount
+		return &fakeTransitEngine{key: "agent-key"}, nil
+	})
+
+	if err := fss.Write([]byte("secret")); err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+	if gotVaultId != "default" || gotMount != defaultTransitMount {
+		t.Errorf("resolver called with (%q, %q), want (%q, %q)", gotVaultId, gotMount, "default", defaultTransitMount)
+	}
+
+	raw, err := afero.ReadFile(fss.fs, fss.FilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(raw, []byte("secret")) || !bytes.HasPrefix(raw, []byte("vault:v1:")) {
+		t.Fatalf("expected transit ciphertext, got %q", raw)
+	}
+
+	got, err := fss.Read()
+	if err != nil {
+		t.Fatalf("Read() unexpected error: %v", err)
+	}
+	if string(got) != "secret\n" {
+		t.Errorf("Read() got = %q, want %q", got, "secret\n")
+	}
+}
+
+func TestNewEncryptionFromParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  map[string][]string
+		wantNil bool
+		wantErr bool
+	}{
+		{
+			name:    "no encryption",
+			params:  map[string][]string{},
+			wantNil: true,
+		},
+		{
+			name:   "transit",
+			params: map[string][]string{ParamTransitKey: {"agent-key"}, ParamTransitMount: {"transit-agent"}},
+		},
+		{
+			name:    "empty transit key",
+			params:  map[string][]string{ParamTransitKey: {""}},
+			wantErr: true,
+		},
+		{
+			name:    "age and transit",
+			params:  map[string][]string{ParamTransitKey: {"agent-key"}, ParamAgeRecipient: {"age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newEncryptionFromParams(tt.params)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newEncryptionFromParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (got == nil) != tt.wantNil {
+				t.Errorf("newEncryptionFromParams() got = %v, wantNil %v", got, tt.wantNil)
+			}
+		})
+	}
+}
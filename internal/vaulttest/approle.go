@@ -0,0 +1,252 @@
+package vaulttest
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type approleMount struct {
+	roles map[string]*approleRole
+}
+
+type approleRole struct {
+	name        string
+	roleId      string
+	secretIdTtl time.Duration
+	policies    []string
+	// secretIds holds the secret_ids of the role, keyed by the secret_id
+	secretIds map[string]*secretId
+}
+
+type secretId struct {
+	id         string
+	accessor   string
+	creation   time.Time
+	expiration time.Time
+	metadata   map[string]any
+	cidrList   []string
+	tokenCidrs []string
+}
+
+func (s *secretId) expired() bool {
+	return !s.expiration.IsZero() && time.Now().After(s.expiration)
+}
+
+func (s *secretId) data() map[string]any {
+	ttl := 0
+	if !s.expiration.IsZero() {
+		ttl = int(s.expiration.Sub(s.creation).Seconds())
+	}
+
+	return map[string]any{
+		"secret_id_accessor": s.accessor,
+		"creation_time":      formatTime(s.creation),
+		"expiration_time":    formatTime(s.expiration),
+		"last_updated_time":  formatTime(s.creation),
+		"secret_id_ttl":      ttl,
+		"secret_id_num_uses": 0,
+		"metadata":           s.metadata,
+		"cidr_list":          s.cidrList,
+		"token_bound_cidrs":  s.tokenCidrs,
+	}
+}
+
+// EnableApprole enables the AppRole auth method at the given mount path.
+func (s *Server) EnableApprole(mount string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.approles[mount] = &approleMount{roles: map[string]*approleRole{}}
+}
+
+// AddApproleRole creates a role that issues secret_ids with the given TTL, a zero TTL creates non-expiring secret_ids.
+func (s *Server) AddApproleRole(mount, name, roleId string, secretIdTtl time.Duration, policies ...string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	approle := s.approleMount(mount)
+	approle.roles[name] = &approleRole{
+		name:        name,
+		roleId:      roleId,
+		secretIdTtl: secretIdTtl,
+		policies:    policies,
+		secretIds:   map[string]*secretId{},
+	}
+}
+
+// AddSecretId adds a secret_id to a role as if it has been created at the given time and returns its accessor.
+func (s *Server) AddSecretId(mount, role, id string, created time.Time) string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	r := s.approleRole(mount, role)
+	ret := r.newSecretId(id, created, r.secretIdTtl)
+	return ret.accessor
+}
+
+// SecretIds returns the valid secret_ids of a role.
+func (s *Server) SecretIds(mount, role string) []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var ret []string
+	for id, secretId := range s.approleRole(mount, role).secretIds {
+		if !secretId.expired() {
+			ret = append(ret, id)
+		}
+	}
+	slices.Sort(ret)
+	return ret
+}
+
+func (s *Server) approleMount(mount string) *approleMount {
+	s.t.Helper()
+	approle, found := s.approles[mount]
+	if !found {
+		s.t.Fatalf("approle auth method not enabled at %q", mount)
+	}
+	return approle
+}
+
+func (s *Server) approleRole(mount, role string) *approleRole {
+	s.t.Helper()
+	r, found := s.approleMount(mount).roles[role]
+	if !found {
+		s.t.Fatalf("approle role %q does not exist at %q", role, mount)
+	}
+	return r
+}
+
+func (r *approleRole) newSecretId(id string, created time.Time, ttl time.Duration) *secretId {
+	ret := &secretId{
+		id:       id,
+		accessor: uuid.NewString(),
+		creation: created,
+	}
+	if ttl > 0 {
+		ret.expiration = created.Add(ttl)
+	}
+	r.secretIds[id] = ret
+	return ret
+}
+
+func (r *approleRole) byAccessor(accessor string) *secretId {
+	for _, secretId := range r.secretIds {
+		if secretId.accessor == accessor {
+			return secretId
+		}
+	}
+	return nil
+}
+
+func (s *Server) handleApproleLogin(approle *approleMount, req *request) *response {
+	if !req.isWrite() {
+		return errorResponse(http.StatusMethodNotAllowed, "unsupported operation")
+	}
+
+	for _, role := range approle.roles {
+		if role.roleId != req.str("role_id") {
+			continue
+		}
+
+		secretId, found := role.secretIds[req.str("secret_id")]
+		if !found || secretId.expired() {
+			break
+		}
+
+		t := s.issueToken(role.policies, map[string]string{"role_name": role.name})
+		return authResponse(t)
+	}
+
+	return errorResponse(http.StatusBadRequest, "invalid role or secret ID")
+}
+
+// handleApprole handles the requests for the path "role/<name>/<operation>" of an AppRole mount.
+func (s *Server) handleApprole(approle *approleMount, path string, req *request) *response {
+	parts := strings.SplitN(path, "/", 3)
+	if len(parts) != 3 || parts[0] != "role" {
+		return notFound()
+	}
+
+	role, found := approle.roles[parts[1]]
+	if !found {
+		return errorResponse(http.StatusBadRequest, "role %q does not exist", parts[1])
+	}
+
+	switch operation := parts[2]; {
+	case operation == "role-id" && req.method == http.MethodGet:
+		return dataResponse(map[string]any{"role_id": role.roleId})
+	case operation == "secret-id" && req.method == methodList:
+		return s.handleListSecretIdAccessors(role)
+	case operation == "secret-id" && req.isWrite():
+		return s.handleGenerateSecretId(role, req)
+	case operation == "secret-id/lookup" && req.isWrite():
+		return lookupSecretId(role.secretIds[req.str("secret_id")])
+	case operation == "secret-id-accessor/lookup" && req.isWrite():
+		return lookupSecretId(role.byAccessor(req.str("secret_id_accessor")))
+	case operation == "secret-id/destroy" && req.isWrite():
+		delete(role.secretIds, req.str("secret_id"))
+		return noContent()
+	case operation == "secret-id-accessor/destroy" && req.isWrite():
+		if secretId := role.byAccessor(req.str("secret_id_accessor")); secretId != nil {
+			delete(role.secretIds, secretId.id)
+		}
+		return noContent()
+	default:
+		return notFound()
+	}
+}
+
+func (s *Server) handleListSecretIdAccessors(role *approleRole) *response {
+	var accessors []string
+	for _, secretId := range role.secretIds {
+		accessors = append(accessors, secretId.accessor)
+	}
+
+	if len(accessors) == 0 {
+		return notFound()
+	}
+
+	slices.Sort(accessors)
+	return dataResponse(map[string]any{"keys": accessors})
+}
+
+func (s *Server) handleGenerateSecretId(role *approleRole, req *request) *response {
+	ttl := role.secretIdTtl
+	if req.body["ttl"] != nil {
+		parsed, err := parseTtl(req.body["ttl"])
+		if err != nil {
+			return errorResponse(http.StatusBadRequest, "invalid ttl: %v", err)
+		}
+		ttl = parsed
+	}
+
+	secretId := role.newSecretId(uuid.NewString(), time.Now(), ttl)
+	secretId.cidrList = splitList(req.body["cidr_list"])
+	secretId.tokenCidrs = splitList(req.body["token_bound_cidrs"])
+	if metadata, ok := req.body["metadata"].(string); ok && metadata != "" {
+		if err := json.Unmarshal([]byte(metadata), &secretId.metadata); err != nil {
+			return errorResponse(http.StatusBadRequest, "invalid metadata: %v", err)
+		}
+	}
+
+	return dataResponse(map[string]any{
+		"secret_id":          secretId.id,
+		"secret_id_accessor": secretId.accessor,
+		"secret_id_ttl":      int(ttl.Seconds()),
+		"secret_id_num_uses": 0,
+	})
+}
+
+// lookupSecretId returns the information of a secret_id. Like Vault, no content is returned for unknown or expired
+// secret_ids.
+func lookupSecretId(secretId *secretId) *response {
+	if secretId == nil || secretId.expired() {
+		return noContent()
+	}
+	return dataResponse(secretId.data())
+}
@@ -0,0 +1,181 @@
+package vaulttest
+
+import (
+	"maps"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type kv2Mount struct {
+	secrets map[string]*kv2Secret
+}
+
+type kv2Secret struct {
+	// versions holds all versions of the secret, the first version is stored at index 0
+	versions []*kv2Version
+}
+
+type kv2Version struct {
+	data      map[string]any
+	created   time.Time
+	deleted   time.Time
+	destroyed bool
+}
+
+func (v *kv2Version) metadata(version int) map[string]any {
+	deletionTime := ""
+	if !v.deleted.IsZero() {
+		deletionTime = formatTime(v.deleted)
+	}
+
+	return map[string]any{
+		"version":         version,
+		"created_time":    formatTime(v.created),
+		"deletion_time":   deletionTime,
+		"destroyed":       v.destroyed,
+		"custom_metadata": nil,
+	}
+}
+
+func (v *kv2Version) isDeleted() bool {
+	return v.destroyed || !v.deleted.IsZero()
+}
+
+// EnableKv2 enables a KV v2 secrets engine at the given mount path.
+func (s *Server) EnableKv2(mount string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.kv2[mount] = &kv2Mount{secrets: map[string]*kv2Secret{}}
+}
+
+// PutKv2 writes a new version of a secret and returns the version number.
+func (s *Server) PutKv2(mount, path string, data map[string]any) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.kv2Mount(mount).put(path, data)
+}
+
+// DeleteKv2 soft-deletes the current version of a secret.
+func (s *Server) DeleteKv2(mount, path string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	secret, found := s.kv2Mount(mount).secrets[path]
+	if !found {
+		s.t.Fatalf("kv2 secret %q does not exist at %q", path, mount)
+	}
+	secret.versions[len(secret.versions)-1].deleted = time.Now()
+}
+
+func (s *Server) kv2Mount(mount string) *kv2Mount {
+	s.t.Helper()
+	kv, found := s.kv2[mount]
+	if !found {
+		s.t.Fatalf("kv2 secrets engine not enabled at %q", mount)
+	}
+	return kv
+}
+
+func (m *kv2Mount) put(path string, data map[string]any) int {
+	secret, found := m.secrets[path]
+	if !found {
+		secret = &kv2Secret{}
+		m.secrets[path] = secret
+	}
+
+	secret.versions = append(secret.versions, &kv2Version{data: maps.Clone(data), created: time.Now()})
+	return len(secret.versions)
+}
+
+// handleKv2 handles the requests for the "data" and "metadata" paths of a KV v2 mount.
+func (s *Server) handleKv2(mount *kv2Mount, path string, req *request) *response {
+	if secretPath, found := strings.CutPrefix(path, "data/"); found && secretPath != "" {
+		switch {
+		case req.method == http.MethodGet:
+			return s.handleKv2Read(mount, secretPath, req)
+		case req.isWrite():
+			return s.handleKv2Write(mount, secretPath, req)
+		case req.method == http.MethodDelete:
+			if secret, found := mount.secrets[secretPath]; found {
+				secret.versions[len(secret.versions)-1].deleted = time.Now()
+			}
+			return noContent()
+		}
+	}
+
+	if secretPath, found := strings.CutPrefix(path, "metadata/"); found && secretPath != "" && req.method == http.MethodGet {
+		return s.handleKv2Metadata(mount, secretPath)
+	}
+
+	return notFound()
+}
+
+func (s *Server) handleKv2Read(mount *kv2Mount, path string, req *request) *response {
+	secret, found := mount.secrets[path]
+	if !found {
+		return notFound()
+	}
+
+	version := len(secret.versions)
+	if requested := req.query.Get("version"); requested != "" && requested != "0" {
+		parsed, err := strconv.Atoi(requested)
+		if err != nil || parsed < 1 || parsed > len(secret.versions) {
+			return notFound()
+		}
+		version = parsed
+	}
+
+	v := secret.versions[version-1]
+	if v.isDeleted() {
+		// Vault returns the metadata of deleted versions along with a 404
+		return &response{status: http.StatusNotFound, body: map[string]any{
+			"data": map[string]any{"data": nil, "metadata": v.metadata(version)},
+		}}
+	}
+
+	return dataResponse(map[string]any{
+		"data":     maps.Clone(v.data),
+		"metadata": v.metadata(version),
+	})
+}
+
+func (s *Server) handleKv2Write(mount *kv2Mount, path string, req *request) *response {
+	data, ok := req.body["data"].(map[string]any)
+	if !ok {
+		return errorResponse(http.StatusBadRequest, "no data provided")
+	}
+
+	version := mount.put(path, data)
+	secret := mount.secrets[path]
+	return dataResponse(secret.versions[version-1].metadata(version))
+}
+
+func (s *Server) handleKv2Metadata(mount *kv2Mount, path string) *response {
+	secret, found := mount.secrets[path]
+	if !found {
+		return notFound()
+	}
+
+	versions := map[string]any{}
+	for idx, version := range secret.versions {
+		metadata := version.metadata(idx + 1)
+		delete(metadata, "version")
+		delete(metadata, "custom_metadata")
+		versions[strconv.Itoa(idx+1)] = metadata
+	}
+
+	current := secret.versions[len(secret.versions)-1]
+	return dataResponse(map[string]any{
+		"cas_required":         false,
+		"created_time":         formatTime(secret.versions[0].created),
+		"updated_time":         formatTime(current.created),
+		"current_version":      len(secret.versions),
+		"oldest_version":       1,
+		"max_versions":         0,
+		"delete_version_after": "0s",
+		"custom_metadata":      nil,
+		"versions":             versions,
+	})
+}
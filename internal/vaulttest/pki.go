@@ -0,0 +1,288 @@
+package vaulttest
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	pemTypeCertificate = "CERTIFICATE"
+	pemTypeCrl         = "X509 CRL"
+	defaultPkiTtl      = 72 * time.Hour
+)
+
+type pkiMount struct {
+	ca    *x509.Certificate
+	caPem []byte
+	key   crypto.Signer
+
+	serial int64
+	// issued holds the PEM encoded certificates issued by the mount, keyed by their Vault formatted serial
+	issued  map[string][]byte
+	revoked []x509.RevocationListEntry
+	crl     []byte
+}
+
+// EnablePki enables a PKI secrets engine at the given mount path that is backed by a freshly generated root CA.
+// Certificates can be issued and signed for any role name.
+func (s *Server) EnablePki(mount string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		s.t.Fatalf("could not generate ca key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: fmt.Sprintf("vaulttest %s ca", mount)},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(24 * time.Hour * 365),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		s.t.Fatalf("could not create ca: %v", err)
+	}
+	ca, err := x509.ParseCertificate(der)
+	if err != nil {
+		s.t.Fatalf("could not parse ca: %v", err)
+	}
+
+	m := &pkiMount{
+		ca:     ca,
+		caPem:  pem.EncodeToMemory(&pem.Block{Type: pemTypeCertificate, Bytes: der}),
+		key:    key,
+		serial: 1,
+		issued: map[string][]byte{},
+	}
+	if err := m.updateCrl(); err != nil {
+		s.t.Fatalf("could not create crl: %v", err)
+	}
+	s.pki[mount] = m
+}
+
+// PkiCa returns the CA certificate of the PKI secrets engine at the given mount path.
+func (s *Server) PkiCa(mount string) *x509.Certificate {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.pkiMount(mount).ca
+}
+
+// RevokeCertificate revokes the certificate with the given Vault formatted serial, e.g. "0a:1b", and adds it to the
+// CRL of the mount.
+func (s *Server) RevokeCertificate(mount, serial string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	m := s.pkiMount(mount)
+	certPem, found := m.issued[serial]
+	if !found {
+		s.t.Fatalf("certificate %q not issued by %q", serial, mount)
+	}
+
+	block, _ := pem.Decode(certPem)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		s.t.Fatalf("could not parse certificate: %v", err)
+	}
+
+	m.revoked = append(m.revoked, x509.RevocationListEntry{SerialNumber: cert.SerialNumber, RevocationTime: time.Now()})
+	if err := m.updateCrl(); err != nil {
+		s.t.Fatalf("could not update crl: %v", err)
+	}
+}
+
+func (s *Server) pkiMount(mount string) *pkiMount {
+	s.t.Helper()
+	m, found := s.pki[mount]
+	if !found {
+		s.t.Fatalf("pki secrets engine not enabled at %q", mount)
+	}
+	return m
+}
+
+func (m *pkiMount) updateCrl() error {
+	template := &x509.RevocationList{
+		Number:                    big.NewInt(int64(len(m.revoked) + 1)),
+		ThisUpdate:                time.Now(),
+		NextUpdate:                time.Now().Add(24 * time.Hour),
+		RevokedCertificateEntries: m.revoked,
+	}
+
+	crl, err := x509.CreateRevocationList(rand.Reader, template, m.ca, m.key)
+	if err != nil {
+		return err
+	}
+	m.crl = crl
+	return nil
+}
+
+// formatSerial formats a serial like Vault, as colon separated hex bytes.
+func formatSerial(serial *big.Int) string {
+	encoded := hex.EncodeToString(serial.Bytes())
+	var parts []string
+	for i := 0; i < len(encoded); i += 2 {
+		parts = append(parts, encoded[i:i+2])
+	}
+	return strings.Join(parts, ":")
+}
+
+// handlePki handles the requests of a PKI mount.
+func (s *Server) handlePki(m *pkiMount, path string, req *request) *response {
+	if role, found := strings.CutPrefix(path, "issue/"); found && role != "" && req.isWrite() {
+		return m.handleIssue(req)
+	}
+
+	if role, found := strings.CutPrefix(path, "sign/"); found && role != "" && req.isWrite() {
+		return m.handleSign(req)
+	}
+
+	if req.method != http.MethodGet {
+		return notFound()
+	}
+
+	switch path {
+	case "ca":
+		return rawResponse(m.ca.Raw, "application/pkix-cert")
+	case "ca/pem", "ca_chain":
+		return rawResponse(m.caPem, "application/pem-certificate-chain")
+	case "crl":
+		return rawResponse(m.crl, "application/pkix-crl")
+	case "crl/pem":
+		return rawResponse(m.crlPem(), "application/x-pem-file")
+	case "cert/ca":
+		return dataResponse(map[string]any{"certificate": string(m.caPem)})
+	case "cert/crl":
+		return dataResponse(map[string]any{"certificate": string(m.crlPem())})
+	}
+
+	if serial, found := strings.CutPrefix(path, "cert/"); found {
+		certPem, found := m.issued[strings.ReplaceAll(serial, "-", ":")]
+		if !found {
+			return notFound()
+		}
+		return dataResponse(map[string]any{"certificate": string(certPem)})
+	}
+
+	return notFound()
+}
+
+func (m *pkiMount) crlPem() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: pemTypeCrl, Bytes: m.crl})
+}
+
+func (m *pkiMount) handleIssue(req *request) *response {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return errorResponse(http.StatusInternalServerError, "could not generate key: %v", err)
+	}
+
+	data, err := m.issue(req, req.str("common_name"), key.Public())
+	if err != nil {
+		return errorResponse(http.StatusBadRequest, "%v", err)
+	}
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return errorResponse(http.StatusInternalServerError, "could not marshal key: %v", err)
+	}
+	data["private_key"] = string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}))
+	data["private_key_type"] = "ec"
+
+	return dataResponse(data)
+}
+
+func (m *pkiMount) handleSign(req *request) *response {
+	block, _ := pem.Decode([]byte(req.str("csr")))
+	if block == nil {
+		return errorResponse(http.StatusBadRequest, "could not decode csr")
+	}
+
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return errorResponse(http.StatusBadRequest, "could not parse csr: %v", err)
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return errorResponse(http.StatusBadRequest, "invalid csr signature: %v", err)
+	}
+
+	commonName := req.str("common_name")
+	if commonName == "" {
+		commonName = csr.Subject.CommonName
+	}
+
+	data, err := m.issue(req, commonName, csr.PublicKey)
+	if err != nil {
+		return errorResponse(http.StatusBadRequest, "%v", err)
+	}
+	return dataResponse(data)
+}
+
+func (m *pkiMount) issue(req *request, commonName string, pub crypto.PublicKey) (map[string]any, error) {
+	if commonName == "" {
+		return nil, fmt.Errorf("the common_name field is required")
+	}
+
+	ttl, err := parseTtl(req.body["ttl"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid ttl: %w", err)
+	}
+	if ttl == 0 {
+		ttl = defaultPkiTtl
+	}
+
+	var ips []net.IP
+	for _, ip := range splitList(req.body["ip_sans"]) {
+		parsed := net.ParseIP(ip)
+		if parsed == nil {
+			return nil, fmt.Errorf("invalid ip address %q", ip)
+		}
+		ips = append(ips, parsed)
+	}
+
+	m.serial++
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(m.serial),
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     append([]string{commonName}, splitList(req.body["alt_names"])...),
+		IPAddresses:  ips,
+		NotBefore:    now.Add(-30 * time.Second),
+		NotAfter:     now.Add(ttl),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, m.ca, pub, m.key)
+	if err != nil {
+		return nil, fmt.Errorf("could not create certificate: %w", err)
+	}
+
+	serial := formatSerial(template.SerialNumber)
+	certPem := pem.EncodeToMemory(&pem.Block{Type: pemTypeCertificate, Bytes: der})
+	m.issued[serial] = certPem
+
+	return map[string]any{
+		"certificate":   string(certPem),
+		"issuing_ca":    string(m.caPem),
+		"ca_chain":      []string{string(m.caPem)},
+		"serial_number": serial,
+		"expiration":    template.NotAfter.Unix(),
+	}, nil
+}
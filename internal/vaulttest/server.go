@@ -0,0 +1,391 @@
+// Package vaulttest provides an in-process HTTP server that emulates the parts of the Vault API used by sc-agent, so
+// the lifecycles of Vault-dependent components can be tested without a running Vault instance.
+//
+// The server keeps all state in memory and does not enforce policies: every valid token is allowed to access every
+// endpoint. Secrets engines and auth methods have to be enabled explicitly, requests to paths that are not emulated
+// are answered with 404.
+package vaulttest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/vault/api"
+)
+
+const (
+	// RootToken is a non-expiring token that is valid on every server.
+	RootToken = "hvs.root"
+
+	defaultTokenTtl = time.Hour
+	methodList      = "LIST"
+)
+
+// Request is a request that has been received by the server.
+type Request struct {
+	Method string
+	// Path is the requested path without the "/v1/" prefix
+	Path  string
+	Token string
+}
+
+type Server struct {
+	t        testing.TB
+	server   *httptest.Server
+	tokenTtl time.Duration
+
+	mutex    sync.Mutex
+	tokens   map[string]*token
+	wrapped  map[string]map[string]any
+	approles map[string]*approleMount
+	kv2      map[string]*kv2Mount
+	pki      map[string]*pkiMount
+	ssh      map[string]*sshMount
+	requests []Request
+}
+
+type ServerOpt func(s *Server) error
+
+// WithTokenTtl sets the TTL of tokens issued by logins, defaults to one hour.
+func WithTokenTtl(ttl time.Duration) ServerOpt {
+	return func(s *Server) error {
+		if ttl < time.Second {
+			return errors.New("token ttl must be at least one second")
+		}
+		s.tokenTtl = ttl
+		return nil
+	}
+}
+
+// NewServer starts a new server that is closed when the test finishes.
+func NewServer(t testing.TB, opts ...ServerOpt) *Server {
+	t.Helper()
+
+	s := &Server{
+		t:        t,
+		tokenTtl: defaultTokenTtl,
+		tokens: map[string]*token{
+			RootToken: {id: RootToken, accessor: uuid.NewString(), policies: []string{"root"}},
+		},
+		wrapped:  map[string]map[string]any{},
+		approles: map[string]*approleMount{},
+		kv2:      map[string]*kv2Mount{},
+		pki:      map[string]*pkiMount{},
+		ssh:      map[string]*sshMount{},
+	}
+
+	for _, opt := range opts {
+		if err := opt(s); err != nil {
+			t.Fatalf("could not build vault test server: %v", err)
+		}
+	}
+
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+// URL returns the address of the server.
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// Client returns a new Vault client for the server that is authenticated using the RootToken.
+func (s *Server) Client() *api.Client {
+	s.t.Helper()
+
+	conf := api.DefaultConfig()
+	conf.Address = s.server.URL
+	conf.MaxRetries = 0
+	client, err := api.NewClient(conf)
+	if err != nil {
+		s.t.Fatalf("could not build vault client: %v", err)
+	}
+	client.SetToken(RootToken)
+	return client
+}
+
+// Requests returns all requests that have been received so far.
+func (s *Server) Requests() []Request {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return slices.Clone(s.requests)
+}
+
+// request is a parsed request that is passed to the handlers of the emulated endpoints.
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	body   map[string]any
+	// tokenId is the token passed in the request, token is only set if tokenId refers to a valid token
+	tokenId string
+	token   *token
+}
+
+func (r *request) isWrite() bool {
+	return r.method == http.MethodPut || r.method == http.MethodPost
+}
+
+func (r *request) str(key string) string {
+	val, _ := r.body[key].(string)
+	return val
+}
+
+// response is returned by the handlers, it either contains a JSON body or raw data.
+type response struct {
+	status      int
+	body        map[string]any
+	raw         []byte
+	contentType string
+}
+
+func dataResponse(data map[string]any) *response {
+	return &response{status: http.StatusOK, body: map[string]any{"data": data}}
+}
+
+func rawResponse(data []byte, contentType string) *response {
+	return &response{status: http.StatusOK, raw: data, contentType: contentType}
+}
+
+func noContent() *response {
+	return &response{status: http.StatusNoContent}
+}
+
+func errorResponse(status int, format string, args ...any) *response {
+	return &response{status: status, body: map[string]any{"errors": []string{fmt.Sprintf(format, args...)}}}
+}
+
+func notFound() *response {
+	return &response{status: http.StatusNotFound, body: map[string]any{"errors": []string{}}}
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	req := &request{
+		method: r.Method,
+		path:   strings.Trim(strings.TrimPrefix(r.URL.Path, "/v1/"), "/"),
+		query:  r.URL.Query(),
+	}
+	if req.method == http.MethodGet && req.query.Get("list") == "true" {
+		req.method = methodList
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req.body); err != nil {
+		req.body = map[string]any{}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	req.tokenId = r.Header.Get("X-Vault-Token")
+	s.requests = append(s.requests, Request{Method: req.method, Path: req.path, Token: req.tokenId})
+	req.token = s.lookupToken(req.tokenId)
+
+	resp := s.route(req)
+	if wrapTtl := r.Header.Get("X-Vault-Wrap-TTL"); wrapTtl != "" && resp.status == http.StatusOK && resp.body != nil {
+		resp = s.wrap(resp.body, wrapTtl, req.path)
+	}
+
+	writeResponse(w, resp)
+}
+
+func (s *Server) route(req *request) *response {
+	switch req.path {
+	case "sys/health":
+		return s.handleHealth()
+	case "sys/wrapping/unwrap":
+		return s.handleUnwrap(req)
+	}
+
+	if mount, found := strings.CutPrefix(req.path, "auth/"); found {
+		mount, _, _ = strings.Cut(mount, "/")
+		if approle, found := s.approles[mount]; found && req.path == fmt.Sprintf("auth/%s/login", mount) {
+			return s.handleApproleLogin(approle, req)
+		}
+	}
+
+	if req.token == nil {
+		return errorResponse(http.StatusForbidden, "permission denied")
+	}
+
+	switch req.path {
+	case "auth/token/lookup-self":
+		return s.handleLookupSelf(req)
+	case "auth/token/renew-self":
+		return s.handleRenewSelf(req)
+	}
+
+	if rest, found := strings.CutPrefix(req.path, "auth/"); found {
+		mount, rest, _ := strings.Cut(rest, "/")
+		if approle, found := s.approles[mount]; found {
+			return s.handleApprole(approle, rest, req)
+		}
+		return notFound()
+	}
+
+	kv2, rest := splitMount(req.path, s.kv2)
+	if kv2 != nil {
+		return s.handleKv2(kv2, rest, req)
+	}
+
+	pki, rest := splitMount(req.path, s.pki)
+	if pki != nil {
+		return s.handlePki(pki, rest, req)
+	}
+
+	ssh, rest := splitMount(req.path, s.ssh)
+	if ssh != nil {
+		return s.handleSsh(ssh, rest, req)
+	}
+
+	return notFound()
+}
+
+// splitMount returns the mount the given path belongs to and the remaining path within the mount. The longest
+// matching mount path wins.
+func splitMount[T any](path string, mounts map[string]T) (T, string) {
+	var ret T
+	var longest string
+	for mountPath, mount := range mounts {
+		if strings.HasPrefix(path, mountPath+"/") && len(mountPath) > len(longest) {
+			ret = mount
+			longest = mountPath
+		}
+	}
+
+	if longest == "" {
+		return ret, ""
+	}
+	return ret, strings.TrimPrefix(path, longest+"/")
+}
+
+func writeResponse(w http.ResponseWriter, resp *response) {
+	if resp.raw != nil {
+		w.Header().Set("Content-Type", resp.contentType)
+		w.WriteHeader(resp.status)
+		_, _ = w.Write(resp.raw)
+		return
+	}
+
+	if resp.body == nil {
+		w.WriteHeader(resp.status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(resp.status)
+	_ = json.NewEncoder(w).Encode(resp.body)
+}
+
+func (s *Server) handleHealth() *response {
+	return &response{status: http.StatusOK, body: map[string]any{
+		"initialized":  true,
+		"sealed":       false,
+		"standby":      false,
+		"version":      "vaulttest",
+		"cluster_name": "vaulttest",
+	}}
+}
+
+// wrap response-wraps the given body, the returned wrapping token can be unwrapped once.
+func (s *Server) wrap(body map[string]any, wrapTtl string, path string) *response {
+	ttl, err := parseTtl(wrapTtl)
+	if err != nil {
+		return errorResponse(http.StatusBadRequest, "invalid wrap ttl: %v", err)
+	}
+
+	wrappingToken := newTokenId()
+	s.wrapped[wrappingToken] = body
+	wrapInfo := map[string]any{
+		"token":         wrappingToken,
+		"accessor":      uuid.NewString(),
+		"ttl":           int(ttl.Seconds()),
+		"creation_time": formatTime(time.Now()),
+		"creation_path": path,
+	}
+	// like Vault, the accessor of a wrapped secret_id is returned along with the wrapping token
+	if data, ok := body["data"].(map[string]any); ok && data["secret_id_accessor"] != nil {
+		wrapInfo["wrapped_accessor"] = data["secret_id_accessor"]
+	}
+	return &response{status: http.StatusOK, body: map[string]any{"wrap_info": wrapInfo}}
+}
+
+// handleUnwrap unwraps the wrapping token passed in the body or, if the body is empty, as X-Vault-Token header.
+func (s *Server) handleUnwrap(req *request) *response {
+	if !req.isWrite() {
+		return errorResponse(http.StatusMethodNotAllowed, "unsupported operation")
+	}
+
+	wrappingToken := req.str("token")
+	if wrappingToken == "" {
+		wrappingToken = req.tokenId
+	}
+	body, found := s.wrapped[wrappingToken]
+	if !found {
+		return errorResponse(http.StatusBadRequest, "wrapping token is not valid or does not exist")
+	}
+	delete(s.wrapped, wrappingToken)
+
+	return &response{status: http.StatusOK, body: body}
+}
+
+// parseTtl parses durations as accepted by Vault, e.g. "30m", "30d" or "1800".
+func parseTtl(val any) (time.Duration, error) {
+	switch v := val.(type) {
+	case nil:
+		return 0, nil
+	case float64:
+		return time.Duration(v) * time.Second, nil
+	case string:
+		if v == "" {
+			return 0, nil
+		}
+		if seconds, err := strconv.Atoi(v); err == nil {
+			return time.Duration(seconds) * time.Second, nil
+		}
+		if days, found := strings.CutSuffix(v, "d"); found {
+			count, err := strconv.Atoi(days)
+			if err != nil {
+				return 0, err
+			}
+			return time.Duration(count) * 24 * time.Hour, nil
+		}
+		return time.ParseDuration(v)
+	default:
+		return 0, fmt.Errorf("unsupported ttl %v", val)
+	}
+}
+
+// splitList splits comma-separated lists as accepted by Vault, lists may also be passed as JSON arrays.
+func splitList(val any) []string {
+	var ret []string
+	switch v := val.(type) {
+	case string:
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				ret = append(ret, item)
+			}
+		}
+	case []any:
+		for _, item := range v {
+			if str, ok := item.(string); ok && str != "" {
+				ret = append(ret, str)
+			}
+		}
+	}
+	return ret
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,125 @@
+package vaulttest
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+const defaultSshTtl = time.Hour
+
+type sshMount struct {
+	signer ssh.Signer
+	serial uint64
+}
+
+// EnableSsh enables an SSH secrets engine at the given mount path that signs keys using a freshly generated CA key.
+// Keys can be signed for any role name.
+func (s *Server) EnableSsh(mount string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		s.t.Fatalf("could not generate ssh ca key: %v", err)
+	}
+
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		s.t.Fatalf("could not build ssh signer: %v", err)
+	}
+
+	s.ssh[mount] = &sshMount{signer: signer}
+}
+
+// SshCaPublicKey returns the public key of the CA of the SSH secrets engine at the given mount path.
+func (s *Server) SshCaPublicKey(mount string) ssh.PublicKey {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	m, found := s.ssh[mount]
+	if !found {
+		s.t.Fatalf("ssh secrets engine not enabled at %q", mount)
+	}
+	return m.signer.PublicKey()
+}
+
+// handleSsh handles the requests of an SSH mount.
+func (s *Server) handleSsh(m *sshMount, path string, req *request) *response {
+	if path == "public_key" && req.method == http.MethodGet {
+		return rawResponse(ssh.MarshalAuthorizedKey(m.signer.PublicKey()), "text/plain")
+	}
+
+	if role, found := strings.CutPrefix(path, "sign/"); found && role != "" && req.isWrite() {
+		return m.handleSign(req)
+	}
+
+	return notFound()
+}
+
+func (m *sshMount) handleSign(req *request) *response {
+	pub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(req.str("public_key")))
+	if err != nil {
+		return errorResponse(http.StatusBadRequest, "failed to parse public_key as SSH key: %v", err)
+	}
+
+	certType := uint32(ssh.UserCert)
+	switch req.str("cert_type") {
+	case "", "user":
+	case "host":
+		certType = ssh.HostCert
+	default:
+		return errorResponse(http.StatusBadRequest, "cert_type must be either 'user' or 'host'")
+	}
+
+	ttl, err := parseTtl(req.body["ttl"])
+	if err != nil {
+		return errorResponse(http.StatusBadRequest, "invalid ttl: %v", err)
+	}
+	if ttl == 0 {
+		ttl = defaultSshTtl
+	}
+
+	m.serial++
+	now := time.Now()
+	cert := &ssh.Certificate{
+		Key:             pub,
+		Serial:          m.serial,
+		CertType:        certType,
+		KeyId:           fmt.Sprintf("vaulttest-%d", m.serial),
+		ValidPrincipals: splitList(req.body["valid_principals"]),
+		ValidAfter:      uint64(now.Add(-30 * time.Second).Unix()), //#nosec:G115
+		ValidBefore:     uint64(now.Add(ttl).Unix()),               //#nosec:G115
+		Permissions: ssh.Permissions{
+			CriticalOptions: toStringMap(req.body["critical_options"]),
+			Extensions:      toStringMap(req.body["extensions"]),
+		},
+	}
+
+	if err := cert.SignCert(rand.Reader, m.signer); err != nil {
+		return errorResponse(http.StatusInternalServerError, "could not sign key: %v", err)
+	}
+
+	return dataResponse(map[string]any{
+		"serial_number": fmt.Sprintf("%016x", cert.Serial),
+		"signed_key":    string(ssh.MarshalAuthorizedKey(cert)),
+	})
+}
+
+func toStringMap(val any) map[string]string {
+	raw, ok := val.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	ret := make(map[string]string, len(raw))
+	for key, value := range raw {
+		ret[key] = fmt.Sprint(value)
+	}
+	return ret
+}
@@ -0,0 +1,141 @@
+package vaulttest
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type token struct {
+	id         string
+	accessor   string
+	policies   []string
+	metadata   map[string]string
+	ttl        time.Duration
+	expiration time.Time
+	renewals   int
+}
+
+func (t *token) renewable() bool {
+	return t.ttl > 0
+}
+
+func (t *token) remainingTtl() time.Duration {
+	if t.expiration.IsZero() {
+		return 0
+	}
+	return max(0, time.Until(t.expiration))
+}
+
+func newTokenId() string {
+	return "hvs." + strings.ReplaceAll(uuid.NewString(), "-", "")
+}
+
+// issueToken creates a new renewable token, the caller must hold the mutex.
+func (s *Server) issueToken(policies []string, metadata map[string]string) *token {
+	ret := &token{
+		id:         newTokenId(),
+		accessor:   uuid.NewString(),
+		policies:   policies,
+		metadata:   metadata,
+		ttl:        s.tokenTtl,
+		expiration: time.Now().Add(s.tokenTtl),
+	}
+	s.tokens[ret.id] = ret
+	return ret
+}
+
+// lookupToken returns the token with the given id if it exists and has not expired, the caller must hold the mutex.
+func (s *Server) lookupToken(id string) *token {
+	t, found := s.tokens[id]
+	if !found {
+		return nil
+	}
+
+	if !t.expiration.IsZero() && time.Now().After(t.expiration) {
+		delete(s.tokens, id)
+		return nil
+	}
+
+	return t
+}
+
+// RevokeToken revokes the token with the given id, subsequent requests using the token are denied.
+func (s *Server) RevokeToken(id string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.tokens, id)
+}
+
+// TokenRenewals returns how often the token with the given id has been renewed.
+func (s *Server) TokenRenewals(id string) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if t, found := s.tokens[id]; found {
+		return t.renewals
+	}
+	return 0
+}
+
+func authResponse(t *token) *response {
+	return &response{status: http.StatusOK, body: map[string]any{
+		"auth": map[string]any{
+			"client_token":   t.id,
+			"accessor":       t.accessor,
+			"policies":       t.policies,
+			"token_policies": t.policies,
+			"metadata":       t.metadata,
+			"lease_duration": int(t.remainingTtl().Seconds()),
+			"renewable":      t.renewable(),
+		},
+	}}
+}
+
+func (s *Server) handleLookupSelf(req *request) *response {
+	if req.method != http.MethodGet {
+		return errorResponse(http.StatusMethodNotAllowed, "unsupported operation")
+	}
+
+	var expireTime any
+	if !req.token.expiration.IsZero() {
+		expireTime = formatTime(req.token.expiration)
+	}
+
+	return dataResponse(map[string]any{
+		"id":           req.token.id,
+		"accessor":     req.token.accessor,
+		"policies":     req.token.policies,
+		"meta":         req.token.metadata,
+		"ttl":          int(req.token.remainingTtl().Seconds()),
+		"creation_ttl": int(req.token.ttl.Seconds()),
+		"expire_time":  expireTime,
+		"renewable":    req.token.renewable(),
+		"type":         "service",
+	})
+}
+
+// handleRenewSelf extends the token's lifetime by the requested increment, which is capped by the token's TTL.
+func (s *Server) handleRenewSelf(req *request) *response {
+	if !req.isWrite() {
+		return errorResponse(http.StatusMethodNotAllowed, "unsupported operation")
+	}
+
+	if !req.token.renewable() {
+		return errorResponse(http.StatusBadRequest, "lease is not renewable")
+	}
+
+	increment, err := parseTtl(req.body["increment"])
+	if err != nil {
+		return errorResponse(http.StatusBadRequest, "invalid increment: %v", err)
+	}
+	if increment <= 0 || increment > req.token.ttl {
+		increment = req.token.ttl
+	}
+
+	req.token.expiration = time.Now().Add(increment)
+	req.token.renewals++
+	return authResponse(req.token)
+}
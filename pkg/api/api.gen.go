@@ -23,12 +23,29 @@ import (
 
 // Defines values for FileValidationTest.
 const (
+	Cosign     FileValidationTest = "cosign"
 	EndsWith   FileValidationTest = "ends_with"
+	Gpg        FileValidationTest = "gpg"
+	Minisign   FileValidationTest = "minisign"
 	Regex      FileValidationTest = "regex"
 	Sha256     FileValidationTest = "sha256"
 	StartsWith FileValidationTest = "starts_with"
 )
 
+// Defines values for InfoHealthStatus.
+const (
+	InfoHealthStatusDegraded InfoHealthStatus = "degraded"
+	InfoHealthStatusHealthy  InfoHealthStatus = "healthy"
+)
+
+// Defines values for ReplicationGitItemStatus.
+const (
+	ReplicationGitItemStatusFailed             ReplicationGitItemStatus = "failed"
+	ReplicationGitItemStatusSynced             ReplicationGitItemStatus = "synced"
+	ReplicationGitItemStatusUnknown            ReplicationGitItemStatus = "unknown"
+	ReplicationGitItemStatusVerificationFailed ReplicationGitItemStatus = "verification_failed"
+)
+
 // Defines values for ReplicationHttpItemStatus.
 const (
 	ReplicationHttpItemStatusFailed           ReplicationHttpItemStatus = "failed"
@@ -39,6 +56,7 @@ const (
 
 // Defines values for ReplicationSecretsItemStatus.
 const (
+	ReplicationSecretsItemStatusDeleted ReplicationSecretsItemStatus = "deleted"
 	ReplicationSecretsItemStatusFailed  ReplicationSecretsItemStatus = "failed"
 	ReplicationSecretsItemStatusSynced  ReplicationSecretsItemStatus = "synced"
 	ReplicationSecretsItemStatusUnknown ReplicationSecretsItemStatus = "unknown"
@@ -101,12 +119,15 @@ type AcmeManagedCertificateList struct {
 
 // FileValidation Validates a file's content
 type FileValidation struct {
-	// Arg the value to test against
+	// Arg the value to test against, the public key for signature tests
 	Arg string `json:"arg,omitempty"`
 
 	// InvertResult invert the result of the check
 	InvertResult bool `json:"invert_result,omitempty"`
 
+	// SignatureUrl the URL of the detached signature for signature tests
+	SignatureUrl string `json:"signature_url,omitempty"`
+
 	// Test The type of validation to perform.
 	Test FileValidationTest `json:"test,omitempty"`
 }
@@ -119,6 +140,17 @@ type InfoComponents struct {
 	EnabledComponents []string `json:"enabled_components,omitempty"`
 }
 
+// InfoHealth Describes whether the instance is healthy or degraded
+type InfoHealth struct {
+	Degraded []string `json:"degraded,omitempty"`
+
+	// Status the health of the instance
+	Status InfoHealthStatus `json:"status,omitempty"`
+}
+
+// InfoHealthStatus the health of the instance
+type InfoHealthStatus string
+
 // PackageInfo Info about a single package
 type PackageInfo struct {
 	// Name Name of the package
@@ -182,8 +214,67 @@ type Problem struct {
 	Type *string `json:"type,omitempty"`
 }
 
+// ReplicationGitItem Configuration and status of a single git replication item
+type ReplicationGitItem struct {
+	// Commit hash of the replicated commit
+	Commit string `json:"commit,omitempty"`
+
+	// Id id of the item
+	Id string `json:"id,omitempty"`
+
+	// LastAttempt the point in time of the latest replication attempt
+	LastAttempt *time.Time `json:"last_attempt,omitempty"`
+
+	// LastError the error of the latest replication attempt, empty if it has been successful
+	LastError string `json:"last_error,omitempty"`
+
+	// LastSuccess the point in time of the latest successful replication
+	LastSuccess *time.Time `json:"last_success,omitempty"`
+
+	// NextSync the point in time of the next scheduled replication
+	NextSync  *time.Time           `json:"next_sync,omitempty"`
+	Paths     []ReplicationGitPath `json:"paths,omitempty"`
+	PostHooks []PostHooks          `json:"post_hooks,omitempty"`
+
+	// Ref the branch or tag that is checked out
+	Ref string `json:"ref,omitempty"`
+
+	// Repository URL of the replicated repository
+	Repository string `json:"repository,omitempty"`
+
+	// Status the status of the item
+	Status ReplicationGitItemStatus `json:"status,omitempty"`
+
+	// VerifySignatures whether the checked out commit must be signed by a trusted key
+	VerifySignatures bool `json:"verify_signatures,omitempty"`
+}
+
+// ReplicationGitItemStatus the status of the item
+type ReplicationGitItemStatus string
+
+// ReplicationGitItemsList All managed git replication items
+type ReplicationGitItemsList struct {
+	// Data The replication items
+	Data []ReplicationGitItem `json:"data,omitempty"`
+}
+
+// ReplicationGitPath A file or directory of the repository that is replicated to the local system
+type ReplicationGitPath struct {
+	// Dest the destinations the path is replicated to
+	Dest []string `json:"dest,omitempty"`
+
+	// IsDir whether the path is a directory
+	IsDir bool `json:"is_dir,omitempty"`
+
+	// Src path relative to the root of the repository
+	Src string `json:"src,omitempty"`
+}
+
 // ReplicationHttpItem Configuration and status of a single HTTP replication item
 type ReplicationHttpItem struct {
+	// ContentHash sha256 hash of the replicated content
+	ContentHash string `json:"content_hash,omitempty"`
+
 	// DestUris destination path where the read secret should be writen to
 	DestUris []string `json:"dest_uris,omitempty"`
 
@@ -191,7 +282,19 @@ type ReplicationHttpItem struct {
 	FileValidation *FileValidation `json:"file_validation,omitempty"`
 
 	// Id id of the item
-	Id        string      `json:"id,omitempty"`
+	Id string `json:"id,omitempty"`
+
+	// LastAttempt the point in time of the latest replication attempt
+	LastAttempt *time.Time `json:"last_attempt,omitempty"`
+
+	// LastError the error of the latest replication attempt, empty if it has been successful
+	LastError string `json:"last_error,omitempty"`
+
+	// LastSuccess the point in time of the latest successful replication
+	LastSuccess *time.Time `json:"last_success,omitempty"`
+
+	// NextSync the point in time of the next scheduled replication
+	NextSync  *time.Time  `json:"next_sync,omitempty"`
 	PostHooks []PostHooks `json:"post_hooks,omitempty"`
 
 	// Source path of the secret to read and sync to the local filesystem
@@ -199,6 +302,9 @@ type ReplicationHttpItem struct {
 
 	// Status the status of the synced secret
 	Status ReplicationHttpItemStatus `json:"status,omitempty"`
+
+	// Version version of the replicated content as announced by the server using the ETag or Last-Modified header
+	Version string `json:"version,omitempty"`
 }
 
 // ReplicationHttpItemStatus the status of the synced secret
@@ -212,6 +318,9 @@ type ReplicationHttpItemsList struct {
 
 // ReplicationSecretsItem Configuration and status of a single secret replication item
 type ReplicationSecretsItem struct {
+	// ContentHash sha256 hash of the replicated content
+	ContentHash string `json:"content_hash,omitempty"`
+
 	// DestUri destination path where the read secret should be writen to
 	DestUri string `json:"dest_uri,omitempty"`
 
@@ -221,6 +330,27 @@ type ReplicationSecretsItem struct {
 	// Id id of the item
 	Id string `json:"id,omitempty"`
 
+	// LastAttempt the point in time of the latest replication attempt
+	LastAttempt *time.Time `json:"last_attempt,omitempty"`
+
+	// LastError the error of the latest replication attempt, empty if it has been successful
+	LastError string `json:"last_error,omitempty"`
+
+	// LastSuccess the point in time of the latest successful replication
+	LastSuccess *time.Time `json:"last_success,omitempty"`
+
+	// LeaseExpiry the point in time the lease of a dynamic secret expires
+	LeaseExpiry *time.Time `json:"lease_expiry,omitempty"`
+
+	// PostHooks hooks that are run after the content of the destination has changed
+	PostHooks []PostHooks `json:"post_hooks,omitempty"`
+
+	// ReferencedSecrets the KV v2 versions of all secrets the item has been rendered from, keyed by their path
+	ReferencedSecrets map[string]int `json:"referenced_secrets,omitempty"`
+
+	// ReplicatedVersion the KV v2 version of the secret that has been replicated, 0 if unknown
+	ReplicatedVersion int `json:"replicated_version,omitempty"`
+
 	// SecretPath path of the secret to read and sync to the local filesystem
 	SecretPath string `json:"secret_path,omitempty"`
 
@@ -323,6 +453,84 @@ type SshManagedCertificatesList struct {
 	Data []SshManagedCertificate `json:"data,omitempty"`
 }
 
+// VaultClient Status of a single Vault client
+type VaultClient struct {
+	// Address the address of the Vault endpoint the client currently uses
+	Address string `json:"address,omitempty"`
+
+	// AuthMethod the auth method the client uses to log in
+	AuthMethod string `json:"auth_method,omitempty"`
+
+	// Id id of the client
+	Id string `json:"id,omitempty"`
+
+	// SecretId State of the AppRole secret_id rotation of a Vault client
+	SecretId *VaultSecretId `json:"secret_id,omitempty"`
+
+	// Token Health of the token of a Vault client
+	Token *VaultToken `json:"token,omitempty"`
+}
+
+// VaultClientsList All configured Vault clients
+type VaultClientsList struct {
+	// Data The Vault clients
+	Data []VaultClient `json:"data,omitempty"`
+}
+
+// VaultSecretId State of the AppRole secret_id rotation of a Vault client
+type VaultSecretId struct {
+	// Accessor the accessor of the current secret_id
+	Accessor string `json:"accessor,omitempty"`
+
+	// Expiration the point in time the current secret_id expires, empty if it does not expire
+	Expiration *time.Time `json:"expiration,omitempty"`
+
+	// LastCheck the point in time the secret_id has been checked the last time
+	LastCheck *time.Time `json:"last_check,omitempty"`
+
+	// LastError the latest error while rotating the secret_id
+	LastError string `json:"last_error,omitempty"`
+
+	// LastErrorTime the point in time of the latest error
+	LastErrorTime *time.Time `json:"last_error_time,omitempty"`
+
+	// LastRotation the point in time of the latest rotation
+	LastRotation *time.Time `json:"last_rotation,omitempty"`
+
+	// NextRotation the point in time after which the secret_id is rotated on its next check
+	NextRotation *time.Time `json:"next_rotation,omitempty"`
+}
+
+// VaultToken Health of the token of a Vault client
+type VaultToken struct {
+	// Authenticated whether the client has logged in and its token has not expired
+	Authenticated bool `json:"authenticated,omitempty"`
+
+	// Expiration the point in time the token expires if it is not renewed
+	Expiration *time.Time `json:"expiration,omitempty"`
+
+	// LastError the latest error while logging in or renewing the token
+	LastError string `json:"last_error,omitempty"`
+
+	// LastErrorTime the point in time of the latest error
+	LastErrorTime *time.Time `json:"last_error_time,omitempty"`
+
+	// LastLogin the point in time of the latest successful login
+	LastLogin *time.Time `json:"last_login,omitempty"`
+
+	// LastRenewal the point in time of the latest successful renewal
+	LastRenewal *time.Time `json:"last_renewal,omitempty"`
+
+	// LeaseDuration the lease duration in seconds as returned by the latest login or renewal
+	LeaseDuration int64 `json:"lease_duration,omitempty"`
+
+	// Renewable whether the token is renewable
+	Renewable bool `json:"renewable,omitempty"`
+
+	// Ttl the remaining lifetime of the token in seconds
+	Ttl int64 `json:"ttl,omitempty"`
+}
+
 // X509CertificateConfig Returns the configuration of a managed x509 certificate
 type X509CertificateConfig struct {
 	// AltNames A list of alternative names (SANs) for the certificate
@@ -595,6 +803,9 @@ type ClientInterface interface {
 	// InfoGetComponents request
 	InfoGetComponents(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
 
+	// InfoGetHealth request
+	InfoGetHealth(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// K0sPostAction request
 	K0sPostAction(ctx context.Context, params *K0sPostActionParams, reqEditors ...RequestEditorFn) (*http.Response, error)
 
@@ -619,12 +830,24 @@ type ClientInterface interface {
 	// PowerRebootManagerGetStatus request
 	PowerRebootManagerGetStatus(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
 
+	// ReplicationGetGitItemsList request
+	ReplicationGetGitItemsList(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ReplicationGetGitItem request
+	ReplicationGetGitItem(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ReplicationPostGitItemSync request
+	ReplicationPostGitItemSync(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// ReplicationGetHttpItemsList request
 	ReplicationGetHttpItemsList(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
 
 	// ReplicationGetHttpItem request
 	ReplicationGetHttpItem(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error)
 
+	// ReplicationPostHttpItemSync request
+	ReplicationPostHttpItemSync(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// ReplicationGetSecretsItemsList request
 	ReplicationGetSecretsItemsList(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
 
@@ -640,6 +863,15 @@ type ClientInterface interface {
 	// ServicesUnitStatusPut request
 	ServicesUnitStatusPut(ctx context.Context, unit string, params *ServicesUnitStatusPutParams, reqEditors ...RequestEditorFn) (*http.Response, error)
 
+	// VaultGetClientsList request
+	VaultGetClientsList(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// VaultGetClient request
+	VaultGetClient(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// VaultPostClientSecretIdRotation request
+	VaultPostClientSecretIdRotation(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// WolPostMessage request
 	WolPostMessage(ctx context.Context, alias string, reqEditors ...RequestEditorFn) (*http.Response, error)
 }
@@ -752,6 +984,18 @@ func (c *Client) InfoGetComponents(ctx context.Context, reqEditors ...RequestEdi
 	return c.Client.Do(req)
 }
 
+func (c *Client) InfoGetHealth(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewInfoGetHealthRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
 func (c *Client) K0sPostAction(ctx context.Context, params *K0sPostActionParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
 	req, err := NewK0sPostActionRequest(c.Server, params)
 	if err != nil {
@@ -848,6 +1092,42 @@ func (c *Client) PowerRebootManagerGetStatus(ctx context.Context, reqEditors ...
 	return c.Client.Do(req)
 }
 
+func (c *Client) ReplicationGetGitItemsList(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewReplicationGetGitItemsListRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ReplicationGetGitItem(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewReplicationGetGitItemRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ReplicationPostGitItemSync(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewReplicationPostGitItemSyncRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
 func (c *Client) ReplicationGetHttpItemsList(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
 	req, err := NewReplicationGetHttpItemsListRequest(c.Server)
 	if err != nil {
@@ -872,6 +1152,18 @@ func (c *Client) ReplicationGetHttpItem(ctx context.Context, id string, reqEdito
 	return c.Client.Do(req)
 }
 
+func (c *Client) ReplicationPostHttpItemSync(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewReplicationPostHttpItemSyncRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
 func (c *Client) ReplicationGetSecretsItemsList(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
 	req, err := NewReplicationGetSecretsItemsListRequest(c.Server)
 	if err != nil {
@@ -932,6 +1224,42 @@ func (c *Client) ServicesUnitStatusPut(ctx context.Context, unit string, params
 	return c.Client.Do(req)
 }
 
+func (c *Client) VaultGetClientsList(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewVaultGetClientsListRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) VaultGetClient(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewVaultGetClientRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) VaultPostClientSecretIdRotation(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewVaultPostClientSecretIdRotationRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
 func (c *Client) WolPostMessage(ctx context.Context, alias string, reqEditors ...RequestEditorFn) (*http.Response, error) {
 	req, err := NewWolPostMessageRequest(c.Server, alias)
 	if err != nil {
@@ -1298,6 +1626,33 @@ func NewInfoGetComponentsRequest(server string) (*http.Request, error) {
 	return req, nil
 }
 
+// NewInfoGetHealthRequest generates requests for InfoGetHealth
+func NewInfoGetHealthRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/info/health")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
 // NewK0sPostActionRequest generates requests for K0sPostAction
 func NewK0sPostActionRequest(server string, params *K0sPostActionParams) (*http.Request, error) {
 	var err error
@@ -1593,8 +1948,8 @@ func NewPowerRebootManagerGetStatusRequest(server string) (*http.Request, error)
 	return req, nil
 }
 
-// NewReplicationGetHttpItemsListRequest generates requests for ReplicationGetHttpItemsList
-func NewReplicationGetHttpItemsListRequest(server string) (*http.Request, error) {
+// NewReplicationGetGitItemsListRequest generates requests for ReplicationGetGitItemsList
+func NewReplicationGetGitItemsListRequest(server string) (*http.Request, error) {
 	var err error
 
 	serverURL, err := url.Parse(server)
@@ -1602,7 +1957,7 @@ func NewReplicationGetHttpItemsListRequest(server string) (*http.Request, error)
 		return nil, err
 	}
 
-	operationPath := fmt.Sprintf("/v1/replication/http/items")
+	operationPath := fmt.Sprintf("/v1/replication/git/items")
 	if operationPath[0] == '/' {
 		operationPath = "." + operationPath
 	}
@@ -1620,8 +1975,8 @@ func NewReplicationGetHttpItemsListRequest(server string) (*http.Request, error)
 	return req, nil
 }
 
-// NewReplicationGetHttpItemRequest generates requests for ReplicationGetHttpItem
-func NewReplicationGetHttpItemRequest(server string, id string) (*http.Request, error) {
+// NewReplicationGetGitItemRequest generates requests for ReplicationGetGitItem
+func NewReplicationGetGitItemRequest(server string, id string) (*http.Request, error) {
 	var err error
 
 	var pathParam0 string
@@ -1636,7 +1991,7 @@ func NewReplicationGetHttpItemRequest(server string, id string) (*http.Request,
 		return nil, err
 	}
 
-	operationPath := fmt.Sprintf("/v1/replication/http/items/%s", pathParam0)
+	operationPath := fmt.Sprintf("/v1/replication/git/items/%s", pathParam0)
 	if operationPath[0] == '/' {
 		operationPath = "." + operationPath
 	}
@@ -1654,16 +2009,23 @@ func NewReplicationGetHttpItemRequest(server string, id string) (*http.Request,
 	return req, nil
 }
 
-// NewReplicationGetSecretsItemsListRequest generates requests for ReplicationGetSecretsItemsList
-func NewReplicationGetSecretsItemsListRequest(server string) (*http.Request, error) {
+// NewReplicationPostGitItemSyncRequest generates requests for ReplicationPostGitItemSync
+func NewReplicationPostGitItemSyncRequest(server string, id string) (*http.Request, error) {
 	var err error
 
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
 	serverURL, err := url.Parse(server)
 	if err != nil {
 		return nil, err
 	}
 
-	operationPath := fmt.Sprintf("/v1/replication/secrets/items")
+	operationPath := fmt.Sprintf("/v1/replication/git/items/%s/sync", pathParam0)
 	if operationPath[0] == '/' {
 		operationPath = "." + operationPath
 	}
@@ -1673,7 +2035,7 @@ func NewReplicationGetSecretsItemsListRequest(server string) (*http.Request, err
 		return nil, err
 	}
 
-	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	req, err := http.NewRequest("POST", queryURL.String(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -1681,23 +2043,16 @@ func NewReplicationGetSecretsItemsListRequest(server string) (*http.Request, err
 	return req, nil
 }
 
-// NewReplicationGetSecretsItemRequest generates requests for ReplicationGetSecretsItem
-func NewReplicationGetSecretsItemRequest(server string, id string) (*http.Request, error) {
+// NewReplicationGetHttpItemsListRequest generates requests for ReplicationGetHttpItemsList
+func NewReplicationGetHttpItemsListRequest(server string) (*http.Request, error) {
 	var err error
 
-	var pathParam0 string
-
-	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
-	if err != nil {
-		return nil, err
-	}
-
 	serverURL, err := url.Parse(server)
 	if err != nil {
 		return nil, err
 	}
 
-	operationPath := fmt.Sprintf("/v1/replication/secrets/items/%s", pathParam0)
+	operationPath := fmt.Sprintf("/v1/replication/http/items")
 	if operationPath[0] == '/' {
 		operationPath = "." + operationPath
 	}
@@ -1715,16 +2070,23 @@ func NewReplicationGetSecretsItemRequest(server string, id string) (*http.Reques
 	return req, nil
 }
 
-// NewReplicationPostSecretsRequestsRequest generates requests for ReplicationPostSecretsRequests
-func NewReplicationPostSecretsRequestsRequest(server string, params *ReplicationPostSecretsRequestsParams) (*http.Request, error) {
+// NewReplicationGetHttpItemRequest generates requests for ReplicationGetHttpItem
+func NewReplicationGetHttpItemRequest(server string, id string) (*http.Request, error) {
 	var err error
 
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
 	serverURL, err := url.Parse(server)
 	if err != nil {
 		return nil, err
 	}
 
-	operationPath := fmt.Sprintf("/v1/replication/secrets/sync-requests")
+	operationPath := fmt.Sprintf("/v1/replication/http/items/%s", pathParam0)
 	if operationPath[0] == '/' {
 		operationPath = "." + operationPath
 	}
@@ -1734,25 +2096,7 @@ func NewReplicationPostSecretsRequestsRequest(server string, params *Replication
 		return nil, err
 	}
 
-	if params != nil {
-		queryValues := queryURL.Query()
-
-		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "id", runtime.ParamLocationQuery, params.Id); err != nil {
-			return nil, err
-		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
-			return nil, err
-		} else {
-			for k, v := range parsed {
-				for _, v2 := range v {
-					queryValues.Add(k, v2)
-				}
-			}
-		}
-
-		queryURL.RawQuery = queryValues.Encode()
-	}
-
-	req, err := http.NewRequest("POST", queryURL.String(), nil)
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -1760,13 +2104,13 @@ func NewReplicationPostSecretsRequestsRequest(server string, params *Replication
 	return req, nil
 }
 
-// NewServicesUnitLogsGetRequest generates requests for ServicesUnitLogsGet
-func NewServicesUnitLogsGetRequest(server string, unit string) (*http.Request, error) {
+// NewReplicationPostHttpItemSyncRequest generates requests for ReplicationPostHttpItemSync
+func NewReplicationPostHttpItemSyncRequest(server string, id string) (*http.Request, error) {
 	var err error
 
 	var pathParam0 string
 
-	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "unit", runtime.ParamLocationPath, unit)
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
 	if err != nil {
 		return nil, err
 	}
@@ -1776,7 +2120,147 @@ func NewServicesUnitLogsGetRequest(server string, unit string) (*http.Request, e
 		return nil, err
 	}
 
-	operationPath := fmt.Sprintf("/v1/services/%s/logs", pathParam0)
+	operationPath := fmt.Sprintf("/v1/replication/http/items/%s/sync", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewReplicationGetSecretsItemsListRequest generates requests for ReplicationGetSecretsItemsList
+func NewReplicationGetSecretsItemsListRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/replication/secrets/items")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewReplicationGetSecretsItemRequest generates requests for ReplicationGetSecretsItem
+func NewReplicationGetSecretsItemRequest(server string, id string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/replication/secrets/items/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewReplicationPostSecretsRequestsRequest generates requests for ReplicationPostSecretsRequests
+func NewReplicationPostSecretsRequestsRequest(server string, params *ReplicationPostSecretsRequestsParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/replication/secrets/sync-requests")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "id", runtime.ParamLocationQuery, params.Id); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewServicesUnitLogsGetRequest generates requests for ServicesUnitLogsGet
+func NewServicesUnitLogsGetRequest(server string, unit string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "unit", runtime.ParamLocationPath, unit)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/services/%s/logs", pathParam0)
 	if operationPath[0] == '/' {
 		operationPath = "." + operationPath
 	}
@@ -1846,6 +2330,101 @@ func NewServicesUnitStatusPutRequest(server string, unit string, params *Service
 	return req, nil
 }
 
+// NewVaultGetClientsListRequest generates requests for VaultGetClientsList
+func NewVaultGetClientsListRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/vault/clients")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewVaultGetClientRequest generates requests for VaultGetClient
+func NewVaultGetClientRequest(server string, id string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/vault/clients/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewVaultPostClientSecretIdRotationRequest generates requests for VaultPostClientSecretIdRotation
+func NewVaultPostClientSecretIdRotationRequest(server string, id string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/vault/clients/%s/secret-id/rotate", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
 // NewWolPostMessageRequest generates requests for WolPostMessage
 func NewWolPostMessageRequest(server string, alias string) (*http.Request, error) {
 	var err error
@@ -1950,6 +2529,9 @@ type ClientWithResponsesInterface interface {
 	// InfoGetComponentsWithResponse request
 	InfoGetComponentsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*InfoGetComponentsResponse, error)
 
+	// InfoGetHealthWithResponse request
+	InfoGetHealthWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*InfoGetHealthResponse, error)
+
 	// K0sPostActionWithResponse request
 	K0sPostActionWithResponse(ctx context.Context, params *K0sPostActionParams, reqEditors ...RequestEditorFn) (*K0sPostActionResponse, error)
 
@@ -1974,12 +2556,24 @@ type ClientWithResponsesInterface interface {
 	// PowerRebootManagerGetStatusWithResponse request
 	PowerRebootManagerGetStatusWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*PowerRebootManagerGetStatusResponse, error)
 
+	// ReplicationGetGitItemsListWithResponse request
+	ReplicationGetGitItemsListWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ReplicationGetGitItemsListResponse, error)
+
+	// ReplicationGetGitItemWithResponse request
+	ReplicationGetGitItemWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*ReplicationGetGitItemResponse, error)
+
+	// ReplicationPostGitItemSyncWithResponse request
+	ReplicationPostGitItemSyncWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*ReplicationPostGitItemSyncResponse, error)
+
 	// ReplicationGetHttpItemsListWithResponse request
 	ReplicationGetHttpItemsListWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ReplicationGetHttpItemsListResponse, error)
 
 	// ReplicationGetHttpItemWithResponse request
 	ReplicationGetHttpItemWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*ReplicationGetHttpItemResponse, error)
 
+	// ReplicationPostHttpItemSyncWithResponse request
+	ReplicationPostHttpItemSyncWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*ReplicationPostHttpItemSyncResponse, error)
+
 	// ReplicationGetSecretsItemsListWithResponse request
 	ReplicationGetSecretsItemsListWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ReplicationGetSecretsItemsListResponse, error)
 
@@ -1995,6 +2589,15 @@ type ClientWithResponsesInterface interface {
 	// ServicesUnitStatusPutWithResponse request
 	ServicesUnitStatusPutWithResponse(ctx context.Context, unit string, params *ServicesUnitStatusPutParams, reqEditors ...RequestEditorFn) (*ServicesUnitStatusPutResponse, error)
 
+	// VaultGetClientsListWithResponse request
+	VaultGetClientsListWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*VaultGetClientsListResponse, error)
+
+	// VaultGetClientWithResponse request
+	VaultGetClientWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*VaultGetClientResponse, error)
+
+	// VaultPostClientSecretIdRotationWithResponse request
+	VaultPostClientSecretIdRotationWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*VaultPostClientSecretIdRotationResponse, error)
+
 	// WolPostMessageWithResponse request
 	WolPostMessageWithResponse(ctx context.Context, alias string, reqEditors ...RequestEditorFn) (*WolPostMessageResponse, error)
 }
@@ -2240,6 +2843,31 @@ func (r InfoGetComponentsResponse) StatusCode() int {
 	return 0
 }
 
+type InfoGetHealthResponse struct {
+	Body                      []byte
+	HTTPResponse              *http.Response
+	JSON200                   *InfoHealth
+	ApplicationproblemJSON400 *BadRequest
+	ApplicationproblemJSON403 *Forbidden
+	ApplicationproblemJSON500 *InternalServerError
+}
+
+// Status returns HTTPResponse.Status
+func (r InfoGetHealthResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r InfoGetHealthResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
 type K0sPostActionResponse struct {
 	Body                      []byte
 	HTTPResponse              *http.Response
@@ -2446,7 +3074,87 @@ func (r PowerRebootManagerGetStatusResponse) StatusCode() int {
 	return 0
 }
 
-type ReplicationGetHttpItemsListResponse struct {
+type ReplicationGetGitItemsListResponse struct {
+	Body                      []byte
+	HTTPResponse              *http.Response
+	JSON200                   *ReplicationGitItemsList
+	ApplicationproblemJSON400 *BadRequest
+	ApplicationproblemJSON403 *Forbidden
+	ApplicationproblemJSON500 *InternalServerError
+	ApplicationproblemJSON501 *NotImplemented
+}
+
+// Status returns HTTPResponse.Status
+func (r ReplicationGetGitItemsListResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ReplicationGetGitItemsListResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ReplicationGetGitItemResponse struct {
+	Body                      []byte
+	HTTPResponse              *http.Response
+	JSON200                   *ReplicationGitItem
+	ApplicationproblemJSON400 *BadRequest
+	ApplicationproblemJSON403 *Forbidden
+	ApplicationproblemJSON404 *NotFound
+	ApplicationproblemJSON500 *InternalServerError
+	ApplicationproblemJSON501 *NotImplemented
+}
+
+// Status returns HTTPResponse.Status
+func (r ReplicationGetGitItemResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ReplicationGetGitItemResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ReplicationPostGitItemSyncResponse struct {
+	Body                      []byte
+	HTTPResponse              *http.Response
+	JSON200                   *ReplicationGitItem
+	ApplicationproblemJSON400 *BadRequest
+	ApplicationproblemJSON403 *Forbidden
+	ApplicationproblemJSON404 *NotFound
+	ApplicationproblemJSON500 *InternalServerError
+	ApplicationproblemJSON501 *NotImplemented
+}
+
+// Status returns HTTPResponse.Status
+func (r ReplicationPostGitItemSyncResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ReplicationPostGitItemSyncResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ReplicationGetHttpItemsListResponse struct {
 	Body                      []byte
 	HTTPResponse              *http.Response
 	JSON200                   *ReplicationHttpItemsList
@@ -2499,6 +3207,33 @@ func (r ReplicationGetHttpItemResponse) StatusCode() int {
 	return 0
 }
 
+type ReplicationPostHttpItemSyncResponse struct {
+	Body                      []byte
+	HTTPResponse              *http.Response
+	JSON200                   *ReplicationHttpItem
+	ApplicationproblemJSON400 *BadRequest
+	ApplicationproblemJSON403 *Forbidden
+	ApplicationproblemJSON404 *NotFound
+	ApplicationproblemJSON500 *InternalServerError
+	ApplicationproblemJSON501 *NotImplemented
+}
+
+// Status returns HTTPResponse.Status
+func (r ReplicationPostHttpItemSyncResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ReplicationPostHttpItemSyncResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
 type ReplicationGetSecretsItemsListResponse struct {
 	Body                      []byte
 	HTTPResponse              *http.Response
@@ -2633,6 +3368,86 @@ func (r ServicesUnitStatusPutResponse) StatusCode() int {
 	return 0
 }
 
+type VaultGetClientsListResponse struct {
+	Body                      []byte
+	HTTPResponse              *http.Response
+	JSON200                   *VaultClientsList
+	ApplicationproblemJSON400 *BadRequest
+	ApplicationproblemJSON403 *Forbidden
+	ApplicationproblemJSON500 *InternalServerError
+	ApplicationproblemJSON501 *NotImplemented
+}
+
+// Status returns HTTPResponse.Status
+func (r VaultGetClientsListResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r VaultGetClientsListResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type VaultGetClientResponse struct {
+	Body                      []byte
+	HTTPResponse              *http.Response
+	JSON200                   *VaultClient
+	ApplicationproblemJSON400 *BadRequest
+	ApplicationproblemJSON403 *Forbidden
+	ApplicationproblemJSON404 *NotFound
+	ApplicationproblemJSON500 *InternalServerError
+	ApplicationproblemJSON501 *NotImplemented
+}
+
+// Status returns HTTPResponse.Status
+func (r VaultGetClientResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r VaultGetClientResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type VaultPostClientSecretIdRotationResponse struct {
+	Body                      []byte
+	HTTPResponse              *http.Response
+	JSON200                   *VaultClient
+	ApplicationproblemJSON400 *BadRequest
+	ApplicationproblemJSON403 *Forbidden
+	ApplicationproblemJSON404 *NotFound
+	ApplicationproblemJSON500 *InternalServerError
+	ApplicationproblemJSON501 *NotImplemented
+}
+
+// Status returns HTTPResponse.Status
+func (r VaultPostClientSecretIdRotationResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r VaultPostClientSecretIdRotationResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
 type WolPostMessageResponse struct {
 	Body                      []byte
 	HTTPResponse              *http.Response
@@ -2740,6 +3555,15 @@ func (c *ClientWithResponses) InfoGetComponentsWithResponse(ctx context.Context,
 	return ParseInfoGetComponentsResponse(rsp)
 }
 
+// InfoGetHealthWithResponse request returning *InfoGetHealthResponse
+func (c *ClientWithResponses) InfoGetHealthWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*InfoGetHealthResponse, error) {
+	rsp, err := c.InfoGetHealth(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseInfoGetHealthResponse(rsp)
+}
+
 // K0sPostActionWithResponse request returning *K0sPostActionResponse
 func (c *ClientWithResponses) K0sPostActionWithResponse(ctx context.Context, params *K0sPostActionParams, reqEditors ...RequestEditorFn) (*K0sPostActionResponse, error) {
 	rsp, err := c.K0sPostAction(ctx, params, reqEditors...)
@@ -2812,6 +3636,33 @@ func (c *ClientWithResponses) PowerRebootManagerGetStatusWithResponse(ctx contex
 	return ParsePowerRebootManagerGetStatusResponse(rsp)
 }
 
+// ReplicationGetGitItemsListWithResponse request returning *ReplicationGetGitItemsListResponse
+func (c *ClientWithResponses) ReplicationGetGitItemsListWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ReplicationGetGitItemsListResponse, error) {
+	rsp, err := c.ReplicationGetGitItemsList(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseReplicationGetGitItemsListResponse(rsp)
+}
+
+// ReplicationGetGitItemWithResponse request returning *ReplicationGetGitItemResponse
+func (c *ClientWithResponses) ReplicationGetGitItemWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*ReplicationGetGitItemResponse, error) {
+	rsp, err := c.ReplicationGetGitItem(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseReplicationGetGitItemResponse(rsp)
+}
+
+// ReplicationPostGitItemSyncWithResponse request returning *ReplicationPostGitItemSyncResponse
+func (c *ClientWithResponses) ReplicationPostGitItemSyncWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*ReplicationPostGitItemSyncResponse, error) {
+	rsp, err := c.ReplicationPostGitItemSync(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseReplicationPostGitItemSyncResponse(rsp)
+}
+
 // ReplicationGetHttpItemsListWithResponse request returning *ReplicationGetHttpItemsListResponse
 func (c *ClientWithResponses) ReplicationGetHttpItemsListWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ReplicationGetHttpItemsListResponse, error) {
 	rsp, err := c.ReplicationGetHttpItemsList(ctx, reqEditors...)
@@ -2830,6 +3681,15 @@ func (c *ClientWithResponses) ReplicationGetHttpItemWithResponse(ctx context.Con
 	return ParseReplicationGetHttpItemResponse(rsp)
 }
 
+// ReplicationPostHttpItemSyncWithResponse request returning *ReplicationPostHttpItemSyncResponse
+func (c *ClientWithResponses) ReplicationPostHttpItemSyncWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*ReplicationPostHttpItemSyncResponse, error) {
+	rsp, err := c.ReplicationPostHttpItemSync(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseReplicationPostHttpItemSyncResponse(rsp)
+}
+
 // ReplicationGetSecretsItemsListWithResponse request returning *ReplicationGetSecretsItemsListResponse
 func (c *ClientWithResponses) ReplicationGetSecretsItemsListWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ReplicationGetSecretsItemsListResponse, error) {
 	rsp, err := c.ReplicationGetSecretsItemsList(ctx, reqEditors...)
@@ -2875,6 +3735,33 @@ func (c *ClientWithResponses) ServicesUnitStatusPutWithResponse(ctx context.Cont
 	return ParseServicesUnitStatusPutResponse(rsp)
 }
 
+// VaultGetClientsListWithResponse request returning *VaultGetClientsListResponse
+func (c *ClientWithResponses) VaultGetClientsListWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*VaultGetClientsListResponse, error) {
+	rsp, err := c.VaultGetClientsList(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseVaultGetClientsListResponse(rsp)
+}
+
+// VaultGetClientWithResponse request returning *VaultGetClientResponse
+func (c *ClientWithResponses) VaultGetClientWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*VaultGetClientResponse, error) {
+	rsp, err := c.VaultGetClient(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseVaultGetClientResponse(rsp)
+}
+
+// VaultPostClientSecretIdRotationWithResponse request returning *VaultPostClientSecretIdRotationResponse
+func (c *ClientWithResponses) VaultPostClientSecretIdRotationWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*VaultPostClientSecretIdRotationResponse, error) {
+	rsp, err := c.VaultPostClientSecretIdRotation(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseVaultPostClientSecretIdRotationResponse(rsp)
+}
+
 // WolPostMessageWithResponse request returning *WolPostMessageResponse
 func (c *ClientWithResponses) WolPostMessageWithResponse(ctx context.Context, alias string, reqEditors ...RequestEditorFn) (*WolPostMessageResponse, error) {
 	rsp, err := c.WolPostMessage(ctx, alias, reqEditors...)
@@ -3276,7 +4163,432 @@ func ParseCertsX509PostIssueRequestsResponse(rsp *http.Response) (*CertsX509Post
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
 			return nil, err
 		}
-		response.ApplicationproblemJSON404 = &dest
+		response.ApplicationproblemJSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest InternalServerError
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON500 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 501:
+		var dest NotImplemented
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON501 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseCertsX509GetCertificateResponse parses an HTTP response from a CertsX509GetCertificateWithResponse call
+func ParseCertsX509GetCertificateResponse(rsp *http.Response) (*CertsX509GetCertificateResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &CertsX509GetCertificateResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest X509ManagedCertificate
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest BadRequest
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest NotFound
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest InternalServerError
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON500 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 501:
+		var dest NotImplemented
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON501 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseInfoGetComponentsResponse parses an HTTP response from a InfoGetComponentsWithResponse call
+func ParseInfoGetComponentsResponse(rsp *http.Response) (*InfoGetComponentsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &InfoGetComponentsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest InfoComponents
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest BadRequest
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest NotFound
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest InternalServerError
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON500 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 501:
+		var dest NotImplemented
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON501 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseInfoGetHealthResponse parses an HTTP response from a InfoGetHealthWithResponse call
+func ParseInfoGetHealthResponse(rsp *http.Response) (*InfoGetHealthResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &InfoGetHealthResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest InfoHealth
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest BadRequest
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest InternalServerError
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseK0sPostActionResponse parses an HTTP response from a K0sPostActionWithResponse call
+func ParseK0sPostActionResponse(rsp *http.Response) (*K0sPostActionResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &K0sPostActionResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest BadRequest
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest InternalServerError
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON500 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 501:
+		var dest NotImplemented
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON501 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseLibvirtPostDomainActionResponse parses an HTTP response from a LibvirtPostDomainActionWithResponse call
+func ParseLibvirtPostDomainActionResponse(rsp *http.Response) (*LibvirtPostDomainActionResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &LibvirtPostDomainActionResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest BadRequest
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest NotFound
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest InternalServerError
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON500 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 501:
+		var dest NotImplemented
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON501 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParsePackagesInstalledGetResponse parses an HTTP response from a PackagesInstalledGetWithResponse call
+func ParsePackagesInstalledGetResponse(rsp *http.Response) (*PackagesInstalledGetResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PackagesInstalledGetResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest PackagesInstalled
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest BadRequest
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest InternalServerError
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON500 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 501:
+		var dest NotImplemented
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON501 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParsePackagesUpdatesGetResponse parses an HTTP response from a PackagesUpdatesGetWithResponse call
+func ParsePackagesUpdatesGetResponse(rsp *http.Response) (*PackagesUpdatesGetResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PackagesUpdatesGetResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest PackageUpdates
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest BadRequest
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest InternalServerError
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON500 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 501:
+		var dest NotImplemented
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON501 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParsePackagesUpgradeRequestsPostResponse parses an HTTP response from a PackagesUpgradeRequestsPostWithResponse call
+func ParsePackagesUpgradeRequestsPostResponse(rsp *http.Response) (*PackagesUpgradeRequestsPostResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PackagesUpgradeRequestsPostResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest BadRequest
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Forbidden
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON403 = &dest
 
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
 		var dest InternalServerError
@@ -3297,27 +4609,20 @@ func ParseCertsX509PostIssueRequestsResponse(rsp *http.Response) (*CertsX509Post
 	return response, nil
 }
 
-// ParseCertsX509GetCertificateResponse parses an HTTP response from a CertsX509GetCertificateWithResponse call
-func ParseCertsX509GetCertificateResponse(rsp *http.Response) (*CertsX509GetCertificateResponse, error) {
+// ParsePowerPostActionResponse parses an HTTP response from a PowerPostActionWithResponse call
+func ParsePowerPostActionResponse(rsp *http.Response) (*PowerPostActionResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
 	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
 
-	response := &CertsX509GetCertificateResponse{
+	response := &PowerPostActionResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
 
 	switch {
-	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
-		var dest X509ManagedCertificate
-		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
-			return nil, err
-		}
-		response.JSON200 = &dest
-
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
 		var dest BadRequest
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
@@ -3332,13 +4637,6 @@ func ParseCertsX509GetCertificateResponse(rsp *http.Response) (*CertsX509GetCert
 		}
 		response.ApplicationproblemJSON403 = &dest
 
-	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
-		var dest NotFound
-		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
-			return nil, err
-		}
-		response.ApplicationproblemJSON404 = &dest
-
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
 		var dest InternalServerError
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
@@ -3358,27 +4656,20 @@ func ParseCertsX509GetCertificateResponse(rsp *http.Response) (*CertsX509GetCert
 	return response, nil
 }
 
-// ParseInfoGetComponentsResponse parses an HTTP response from a InfoGetComponentsWithResponse call
-func ParseInfoGetComponentsResponse(rsp *http.Response) (*InfoGetComponentsResponse, error) {
+// ParsePowerRebootManagerPostStatusResponse parses an HTTP response from a PowerRebootManagerPostStatusWithResponse call
+func ParsePowerRebootManagerPostStatusResponse(rsp *http.Response) (*PowerRebootManagerPostStatusResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
 	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
 
-	response := &InfoGetComponentsResponse{
+	response := &PowerRebootManagerPostStatusResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
 
 	switch {
-	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
-		var dest InfoComponents
-		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
-			return nil, err
-		}
-		response.JSON200 = &dest
-
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
 		var dest BadRequest
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
@@ -3393,13 +4684,6 @@ func ParseInfoGetComponentsResponse(rsp *http.Response) (*InfoGetComponentsRespo
 		}
 		response.ApplicationproblemJSON403 = &dest
 
-	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
-		var dest NotFound
-		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
-			return nil, err
-		}
-		response.ApplicationproblemJSON404 = &dest
-
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
 		var dest InternalServerError
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
@@ -3419,20 +4703,29 @@ func ParseInfoGetComponentsResponse(rsp *http.Response) (*InfoGetComponentsRespo
 	return response, nil
 }
 
-// ParseK0sPostActionResponse parses an HTTP response from a K0sPostActionWithResponse call
-func ParseK0sPostActionResponse(rsp *http.Response) (*K0sPostActionResponse, error) {
+// ParsePowerRebootManagerGetStatusResponse parses an HTTP response from a PowerRebootManagerGetStatusWithResponse call
+func ParsePowerRebootManagerGetStatusResponse(rsp *http.Response) (*PowerRebootManagerGetStatusResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
 	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
 
-	response := &K0sPostActionResponse{
+	response := &PowerRebootManagerGetStatusResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
 
 	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest struct {
+			Status *string `json:"status,omitempty"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
 		var dest BadRequest
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
@@ -3466,20 +4759,27 @@ func ParseK0sPostActionResponse(rsp *http.Response) (*K0sPostActionResponse, err
 	return response, nil
 }
 
-// ParseLibvirtPostDomainActionResponse parses an HTTP response from a LibvirtPostDomainActionWithResponse call
-func ParseLibvirtPostDomainActionResponse(rsp *http.Response) (*LibvirtPostDomainActionResponse, error) {
+// ParseReplicationGetGitItemsListResponse parses an HTTP response from a ReplicationGetGitItemsListWithResponse call
+func ParseReplicationGetGitItemsListResponse(rsp *http.Response) (*ReplicationGetGitItemsListResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
 	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
 
-	response := &LibvirtPostDomainActionResponse{
+	response := &ReplicationGetGitItemsListResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
 
 	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ReplicationGitItemsList
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
 		var dest BadRequest
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
@@ -3494,13 +4794,6 @@ func ParseLibvirtPostDomainActionResponse(rsp *http.Response) (*LibvirtPostDomai
 		}
 		response.ApplicationproblemJSON403 = &dest
 
-	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
-		var dest NotFound
-		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
-			return nil, err
-		}
-		response.ApplicationproblemJSON404 = &dest
-
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
 		var dest InternalServerError
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
@@ -3520,22 +4813,22 @@ func ParseLibvirtPostDomainActionResponse(rsp *http.Response) (*LibvirtPostDomai
 	return response, nil
 }
 
-// ParsePackagesInstalledGetResponse parses an HTTP response from a PackagesInstalledGetWithResponse call
-func ParsePackagesInstalledGetResponse(rsp *http.Response) (*PackagesInstalledGetResponse, error) {
+// ParseReplicationGetGitItemResponse parses an HTTP response from a ReplicationGetGitItemWithResponse call
+func ParseReplicationGetGitItemResponse(rsp *http.Response) (*ReplicationGetGitItemResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
 	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
 
-	response := &PackagesInstalledGetResponse{
+	response := &ReplicationGetGitItemResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
 
 	switch {
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
-		var dest PackagesInstalled
+		var dest ReplicationGitItem
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
 			return nil, err
 		}
@@ -3555,6 +4848,13 @@ func ParsePackagesInstalledGetResponse(rsp *http.Response) (*PackagesInstalledGe
 		}
 		response.ApplicationproblemJSON403 = &dest
 
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest NotFound
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON404 = &dest
+
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
 		var dest InternalServerError
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
@@ -3574,22 +4874,22 @@ func ParsePackagesInstalledGetResponse(rsp *http.Response) (*PackagesInstalledGe
 	return response, nil
 }
 
-// ParsePackagesUpdatesGetResponse parses an HTTP response from a PackagesUpdatesGetWithResponse call
-func ParsePackagesUpdatesGetResponse(rsp *http.Response) (*PackagesUpdatesGetResponse, error) {
+// ParseReplicationPostGitItemSyncResponse parses an HTTP response from a ReplicationPostGitItemSyncWithResponse call
+func ParseReplicationPostGitItemSyncResponse(rsp *http.Response) (*ReplicationPostGitItemSyncResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
 	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
 
-	response := &PackagesUpdatesGetResponse{
+	response := &ReplicationPostGitItemSyncResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
 
 	switch {
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
-		var dest PackageUpdates
+		var dest ReplicationGitItem
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
 			return nil, err
 		}
@@ -3609,6 +4909,13 @@ func ParsePackagesUpdatesGetResponse(rsp *http.Response) (*PackagesUpdatesGetRes
 		}
 		response.ApplicationproblemJSON403 = &dest
 
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest NotFound
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON404 = &dest
+
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
 		var dest InternalServerError
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
@@ -3628,20 +4935,27 @@ func ParsePackagesUpdatesGetResponse(rsp *http.Response) (*PackagesUpdatesGetRes
 	return response, nil
 }
 
-// ParsePackagesUpgradeRequestsPostResponse parses an HTTP response from a PackagesUpgradeRequestsPostWithResponse call
-func ParsePackagesUpgradeRequestsPostResponse(rsp *http.Response) (*PackagesUpgradeRequestsPostResponse, error) {
+// ParseReplicationGetHttpItemsListResponse parses an HTTP response from a ReplicationGetHttpItemsListWithResponse call
+func ParseReplicationGetHttpItemsListResponse(rsp *http.Response) (*ReplicationGetHttpItemsListResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
 	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
 
-	response := &PackagesUpgradeRequestsPostResponse{
+	response := &ReplicationGetHttpItemsListResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
 
 	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ReplicationHttpItemsList
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
 		var dest BadRequest
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
@@ -3656,6 +4970,13 @@ func ParsePackagesUpgradeRequestsPostResponse(rsp *http.Response) (*PackagesUpgr
 		}
 		response.ApplicationproblemJSON403 = &dest
 
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest NotFound
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON404 = &dest
+
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
 		var dest InternalServerError
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
@@ -3675,20 +4996,27 @@ func ParsePackagesUpgradeRequestsPostResponse(rsp *http.Response) (*PackagesUpgr
 	return response, nil
 }
 
-// ParsePowerPostActionResponse parses an HTTP response from a PowerPostActionWithResponse call
-func ParsePowerPostActionResponse(rsp *http.Response) (*PowerPostActionResponse, error) {
+// ParseReplicationGetHttpItemResponse parses an HTTP response from a ReplicationGetHttpItemWithResponse call
+func ParseReplicationGetHttpItemResponse(rsp *http.Response) (*ReplicationGetHttpItemResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
 	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
 
-	response := &PowerPostActionResponse{
+	response := &ReplicationGetHttpItemResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
 
 	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ReplicationHttpItem
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
 		var dest BadRequest
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
@@ -3722,20 +5050,27 @@ func ParsePowerPostActionResponse(rsp *http.Response) (*PowerPostActionResponse,
 	return response, nil
 }
 
-// ParsePowerRebootManagerPostStatusResponse parses an HTTP response from a PowerRebootManagerPostStatusWithResponse call
-func ParsePowerRebootManagerPostStatusResponse(rsp *http.Response) (*PowerRebootManagerPostStatusResponse, error) {
+// ParseReplicationPostHttpItemSyncResponse parses an HTTP response from a ReplicationPostHttpItemSyncWithResponse call
+func ParseReplicationPostHttpItemSyncResponse(rsp *http.Response) (*ReplicationPostHttpItemSyncResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
 	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
 
-	response := &PowerRebootManagerPostStatusResponse{
+	response := &ReplicationPostHttpItemSyncResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
 
 	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ReplicationHttpItem
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
 		var dest BadRequest
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
@@ -3750,6 +5085,13 @@ func ParsePowerRebootManagerPostStatusResponse(rsp *http.Response) (*PowerReboot
 		}
 		response.ApplicationproblemJSON403 = &dest
 
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest NotFound
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON404 = &dest
+
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
 		var dest InternalServerError
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
@@ -3769,24 +5111,22 @@ func ParsePowerRebootManagerPostStatusResponse(rsp *http.Response) (*PowerReboot
 	return response, nil
 }
 
-// ParsePowerRebootManagerGetStatusResponse parses an HTTP response from a PowerRebootManagerGetStatusWithResponse call
-func ParsePowerRebootManagerGetStatusResponse(rsp *http.Response) (*PowerRebootManagerGetStatusResponse, error) {
+// ParseReplicationGetSecretsItemsListResponse parses an HTTP response from a ReplicationGetSecretsItemsListWithResponse call
+func ParseReplicationGetSecretsItemsListResponse(rsp *http.Response) (*ReplicationGetSecretsItemsListResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
 	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
 
-	response := &PowerRebootManagerGetStatusResponse{
+	response := &ReplicationGetSecretsItemsListResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
 
 	switch {
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
-		var dest struct {
-			Status *string `json:"status,omitempty"`
-		}
+		var dest ReplicationSecretsItemsList
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
 			return nil, err
 		}
@@ -3806,6 +5146,13 @@ func ParsePowerRebootManagerGetStatusResponse(rsp *http.Response) (*PowerRebootM
 		}
 		response.ApplicationproblemJSON403 = &dest
 
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest NotFound
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON404 = &dest
+
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
 		var dest InternalServerError
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
@@ -3825,22 +5172,22 @@ func ParsePowerRebootManagerGetStatusResponse(rsp *http.Response) (*PowerRebootM
 	return response, nil
 }
 
-// ParseReplicationGetHttpItemsListResponse parses an HTTP response from a ReplicationGetHttpItemsListWithResponse call
-func ParseReplicationGetHttpItemsListResponse(rsp *http.Response) (*ReplicationGetHttpItemsListResponse, error) {
+// ParseReplicationGetSecretsItemResponse parses an HTTP response from a ReplicationGetSecretsItemWithResponse call
+func ParseReplicationGetSecretsItemResponse(rsp *http.Response) (*ReplicationGetSecretsItemResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
 	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
 
-	response := &ReplicationGetHttpItemsListResponse{
+	response := &ReplicationGetSecretsItemResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
 
 	switch {
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
-		var dest ReplicationHttpItemsList
+		var dest ReplicationSecretsItem
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
 			return nil, err
 		}
@@ -3886,27 +5233,20 @@ func ParseReplicationGetHttpItemsListResponse(rsp *http.Response) (*ReplicationG
 	return response, nil
 }
 
-// ParseReplicationGetHttpItemResponse parses an HTTP response from a ReplicationGetHttpItemWithResponse call
-func ParseReplicationGetHttpItemResponse(rsp *http.Response) (*ReplicationGetHttpItemResponse, error) {
+// ParseReplicationPostSecretsRequestsResponse parses an HTTP response from a ReplicationPostSecretsRequestsWithResponse call
+func ParseReplicationPostSecretsRequestsResponse(rsp *http.Response) (*ReplicationPostSecretsRequestsResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
 	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
 
-	response := &ReplicationGetHttpItemResponse{
+	response := &ReplicationPostSecretsRequestsResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
 
 	switch {
-	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
-		var dest ReplicationHttpItem
-		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
-			return nil, err
-		}
-		response.JSON200 = &dest
-
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
 		var dest BadRequest
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
@@ -3921,6 +5261,13 @@ func ParseReplicationGetHttpItemResponse(rsp *http.Response) (*ReplicationGetHtt
 		}
 		response.ApplicationproblemJSON403 = &dest
 
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest NotFound
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON404 = &dest
+
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
 		var dest InternalServerError
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
@@ -3940,22 +5287,22 @@ func ParseReplicationGetHttpItemResponse(rsp *http.Response) (*ReplicationGetHtt
 	return response, nil
 }
 
-// ParseReplicationGetSecretsItemsListResponse parses an HTTP response from a ReplicationGetSecretsItemsListWithResponse call
-func ParseReplicationGetSecretsItemsListResponse(rsp *http.Response) (*ReplicationGetSecretsItemsListResponse, error) {
+// ParseServicesUnitLogsGetResponse parses an HTTP response from a ServicesUnitLogsGetWithResponse call
+func ParseServicesUnitLogsGetResponse(rsp *http.Response) (*ServicesUnitLogsGetResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
 	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
 
-	response := &ReplicationGetSecretsItemsListResponse{
+	response := &ServicesUnitLogsGetResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
 
 	switch {
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
-		var dest ReplicationSecretsItemsList
+		var dest ServiceLogsData
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
 			return nil, err
 		}
@@ -3968,6 +5315,13 @@ func ParseReplicationGetSecretsItemsListResponse(rsp *http.Response) (*Replicati
 		}
 		response.ApplicationproblemJSON400 = &dest
 
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Unauthorized
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON401 = &dest
+
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
 		var dest Forbidden
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
@@ -4001,27 +5355,20 @@ func ParseReplicationGetSecretsItemsListResponse(rsp *http.Response) (*Replicati
 	return response, nil
 }
 
-// ParseReplicationGetSecretsItemResponse parses an HTTP response from a ReplicationGetSecretsItemWithResponse call
-func ParseReplicationGetSecretsItemResponse(rsp *http.Response) (*ReplicationGetSecretsItemResponse, error) {
+// ParseServicesUnitStatusPutResponse parses an HTTP response from a ServicesUnitStatusPutWithResponse call
+func ParseServicesUnitStatusPutResponse(rsp *http.Response) (*ServicesUnitStatusPutResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
 	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
 
-	response := &ReplicationGetSecretsItemResponse{
+	response := &ServicesUnitStatusPutResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
 
 	switch {
-	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
-		var dest ReplicationSecretsItem
-		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
-			return nil, err
-		}
-		response.JSON200 = &dest
-
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
 		var dest BadRequest
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
@@ -4062,20 +5409,27 @@ func ParseReplicationGetSecretsItemResponse(rsp *http.Response) (*ReplicationGet
 	return response, nil
 }
 
-// ParseReplicationPostSecretsRequestsResponse parses an HTTP response from a ReplicationPostSecretsRequestsWithResponse call
-func ParseReplicationPostSecretsRequestsResponse(rsp *http.Response) (*ReplicationPostSecretsRequestsResponse, error) {
+// ParseVaultGetClientsListResponse parses an HTTP response from a VaultGetClientsListWithResponse call
+func ParseVaultGetClientsListResponse(rsp *http.Response) (*VaultGetClientsListResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
 	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
 
-	response := &ReplicationPostSecretsRequestsResponse{
+	response := &VaultGetClientsListResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
 
 	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest VaultClientsList
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
 		var dest BadRequest
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
@@ -4090,13 +5444,6 @@ func ParseReplicationPostSecretsRequestsResponse(rsp *http.Response) (*Replicati
 		}
 		response.ApplicationproblemJSON403 = &dest
 
-	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
-		var dest NotFound
-		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
-			return nil, err
-		}
-		response.ApplicationproblemJSON404 = &dest
-
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
 		var dest InternalServerError
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
@@ -4116,22 +5463,22 @@ func ParseReplicationPostSecretsRequestsResponse(rsp *http.Response) (*Replicati
 	return response, nil
 }
 
-// ParseServicesUnitLogsGetResponse parses an HTTP response from a ServicesUnitLogsGetWithResponse call
-func ParseServicesUnitLogsGetResponse(rsp *http.Response) (*ServicesUnitLogsGetResponse, error) {
+// ParseVaultGetClientResponse parses an HTTP response from a VaultGetClientWithResponse call
+func ParseVaultGetClientResponse(rsp *http.Response) (*VaultGetClientResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
 	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
 
-	response := &ServicesUnitLogsGetResponse{
+	response := &VaultGetClientResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
 
 	switch {
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
-		var dest ServiceLogsData
+		var dest VaultClient
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
 			return nil, err
 		}
@@ -4144,13 +5491,6 @@ func ParseServicesUnitLogsGetResponse(rsp *http.Response) (*ServicesUnitLogsGetR
 		}
 		response.ApplicationproblemJSON400 = &dest
 
-	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
-		var dest Unauthorized
-		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
-			return nil, err
-		}
-		response.ApplicationproblemJSON401 = &dest
-
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
 		var dest Forbidden
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
@@ -4184,20 +5524,27 @@ func ParseServicesUnitLogsGetResponse(rsp *http.Response) (*ServicesUnitLogsGetR
 	return response, nil
 }
 
-// ParseServicesUnitStatusPutResponse parses an HTTP response from a ServicesUnitStatusPutWithResponse call
-func ParseServicesUnitStatusPutResponse(rsp *http.Response) (*ServicesUnitStatusPutResponse, error) {
+// ParseVaultPostClientSecretIdRotationResponse parses an HTTP response from a VaultPostClientSecretIdRotationWithResponse call
+func ParseVaultPostClientSecretIdRotationResponse(rsp *http.Response) (*VaultPostClientSecretIdRotationResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
 	defer func() { _ = rsp.Body.Close() }()
 	if err != nil {
 		return nil, err
 	}
 
-	response := &ServicesUnitStatusPutResponse{
+	response := &VaultPostClientSecretIdRotationResponse{
 		Body:         bodyBytes,
 		HTTPResponse: rsp,
 	}
 
 	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest VaultClient
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
 		var dest BadRequest
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
@@ -4295,107 +5642,142 @@ func ParseWolPostMessageResponse(rsp *http.Response) (*WolPostMessageResponse, e
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+w9a2/cOJJ/hdAdMLPYfvmVif3pss4kY4wnY8TJzhxygcGWqltcq0kNSbXdG/i/H/iQ",
-	"REmUWv2Ik2z8IYDTEslivatYLH0KQrZIGQUqRXD2KeAgUkYF6P/8A0dv4a8MhFT/CxmVQPWfOE0TEmJJ",
-	"GB2nnE0TWPz9X4JR9Qzu8SJNQP0ZgcQkCc6CdzEgbmZCRCBClzghEWIcLYgQhM71U8IhQinmeAESuBgF",
-	"g0BILDMRnB1PJoNAEqnmVWChHK5BIFep+jGWMhVn47FdfhSyxRg4Z1yMpzga2tWDh0EgwhgWWMH33xxm",
-	"wVnwX+MSB2PzVIyvzLaCh4eHQRCBCDlJ1X5r6z8MgleMT0kUAd0RSS/CEIRQ+JnlM6q/kIwBpZwtSQQR",
-	"CjlEQCXBSQ0/RyV+SoD6YKdYbC+4eeXOdkElcIqTa+BL4D+r9XbFEUUZhfsUQgkR0jtALAwzziFy8XHi",
-	"8ksOBjJwIANIH9wQO3Io9Mih/nkvePLD9DAI3jD5imU02p/EQYQ4CJbxENAdFogyiWZqiSoDHZcIe8Mk",
-	"MkD0QRJlcqjn2wtiyrUNMi7UcgugEnZHiRKtjIZqhBIzhQigeJrUeeegigoXhL4IIc6YfaHlojrne4oz",
-	"GTNO/r0zav6XZShiGiExXoJWORSUOsJ8hVLgWk0zWtM5Dp4qwPRBUuYO2AeGKhA8FDNqQ/YiXMBvmOI5",
-	"ROfAJZkp5FgUuHMoqQkZnZF5xjX2EJtpZCzMYPTi/LefUehMMQhSzlL1gzGZYXX6rr38eTI5daB5iSVW",
-	"iHBmuDGwbDjRuRn0MAhSJuRNzNitBo1IWIi1CGZC/qJHPBRUxJzjVTAI7odzNlS/DcUtSYdMYw0nw5Rp",
-	"PRmcSZ6BQrxkHM9d6HstXdvGtZllazgeSub0k/+SuP4Dm/4LQm3PO97uxzA4SYofIWrlHdFgnkixgHcN",
-	"saIhMlgc9MNmC8s/OjZfkQT+qbw9bLZT3519BgJhNCMJ/CBQrsjq6MF83hyvpHOJkwyQZEgqBxPPMaEa",
-	"mELVBYfh7PA4muKT2XSCjyZw+AyeH00PcXgyPYXDUziYHjw7gJPwYIZ/Oj48gZ+OJsdHR88OT4+eT0+f",
-	"Hx6XOxOSEzrfQBoIXQKXNxxElng4yDzWSsa8kqucMIbw1t2Emq8AY8pYAphuAIeENgZWg9Wqy4JMCpcp",
-	"8BnjC6XxgWaL4OxDIGJ8ePLMmAAuxc0dkbF+HBV/c5jDffDRxX0xajsMOpxX4yUPt13QGTuvBDTV7b7U",
-	"/5uCQHcxCWNUCg7CHHJvACkMKKnTvlmDD+1bN9XIqdjwh+AXKdO3UJjgYBBcQ8hBCvfHj44gN2lC8QIK",
-	"TsjXqbB0c5Eqfvcg6dcr4SDTg+4rHN7iOSisN7ehfkV4yjKJMFJBXgIoNQPcjXwK1F6Ds4DOCb3XLJSy",
-	"4CxYYKI2tQQuzHwHo4Pno4m2axVymOH11d84CPSsWqy2rVgbKOurvoWUCSIZX6EZZwvLZQ4M2gNX6gkn",
-	"ifaUSojshrcFqEBUQ8OaB13IsKjdg4i+pDOXKdp55n2qtb4PhTLjVAkoyBg4EishYYGy1FoJDggvMUmU",
-	"DCJMI4tjuy2BQkzRFOz7UZXT9I9q4E3+enD2YWMG/DgILDQ3BSQFGaq86VuwvmFlORVxih3IGMvmNvq5",
-	"bw7ut3fgPLtrCndknBhENFspqnAoqVSM3JcBc3isxkHtPCYuCkFrxbpy1pRYFDJZ0KHKOjsxTIMv1nOD",
-	"F55H44Emth1c+hB+Sy6EyID7zQBfGLfCWANt00rP8QeBiB47akZTbLFg9Mav4Y3vrV6oWMtyroY9FMAJ",
-	"Tm5otpj6IH1nTD7BCTKvrJ1S2wGTuNT+UWX+QQX8j04+ymDKh8Yi+moAp382qkEJGs8owjMJHGGNSxRj",
-	"gaYA1AAbNVG5iNpRqNSo+ke0HzSvrrODTWonnEsxFaHuw/aU2POh1mYOGtC8QHOgwEmIfnn37grZdElV",
-	"+FsTJUBZNo91VpZIxSBabGmo1h7jMGQZleODw6Pjk/FCzMUYT8O2rPbaqTtyKgpoMWaZHLLZ0Ixo+kn5",
-	"JpoIiLMFpkMOONJGFe7TBFMjsSKFUMmpjq5iIvJsKw1L76qJMbUZjsyLEk1xolCCiEBHkwGaahWgTBwT",
-	"UqCTiVdWS0Q24X3/9gJxmIEBQ89FdFZ8RrT5hBLsfuC2k6qpQyztfEytGci8gEIWVYyfobWZTfHvHHS6",
-	"11K/uUcRMy4HddKIbLHAfFXbi47g6vjvxUz1WGEbXLcC0ZdTm2r1YRA4oY2KdC6kT3bPK6kXpcEs9pVh",
-	"z2MOTRVeTqfzKM3cCwh5k3HiIa16RKw8pFjGyjXlYEN2HCGhgztFsSyJlNN2x4kEFUS76PgQjEGGY6Ws",
-	"xTi9JcMQj0Kudq9/j3HK2f3KfeJGiA16LfD9hXl44HDWpp7ejCRws6ykZ7pci1oArsTUY1VIVBhOWRO0",
-	"EA/Nnre1KF8qpamPT5pb1exgN2vZQDLDFZobV9RqTkAJC3GiU1wmoqngJeUsGkfTHRDTppc0ZIVQSJtL",
-	"hJxrnexORm8pu1Pm3rwRDIIZJsbhK1nkxv5WTfHkA3a24bWsxoVBVMOae7SD8GdoXyRJkYH1aoKan29y",
-	"sB9KhaDMg1yk42g60kcog8D4sxr8wP5kkHmj2MGhpsfx32eK16ci95H10XupEUGsoYJNcO2gpq34eBR1",
-	"xRHbmSilqOTs/dBmCz6XKajDvq3MO3uuA+r61+VrLgw7rr2h4tdpECxgFw3nkvP7VMR9lO5aUe4vyWL3",
-	"Qy+zOfGfqXVdpfeoivca+JKEcMnm3gRqykHoM42EzZHa+wj9jMMYAZV8hYg53NBgKrrkLxeaOCFU6w49",
-	"skqnRK/4IXgFUzQ5QocHZ8fPz04OjUzFTMiR/itiC0wougVOITlD1z9fEprdn6HDyfFzhJcST1GMRYxE",
-	"wqQYoIPJ8cHRAeJZArrC4HPO3mSOxGKxp6892Z3Qmm7dZH3p5Vj1RJNFF4ZhfUBFQvDJ0n8GrVoluUtC",
-	"Xel4GASQ153RLHFy9V692aCIiJuVFV0SJ726sVCH19e/1MpHHMKpB+8MBFzgoFIKoiIvpRNjtoBxJoCP",
-	"R0LEYxLdcJHHkcokB+rZUA3UES6hIUlxovkARwudn6ZkEWFlStJsmpDwV1h1T55myhxyZg6pVkP91yCQ",
-	"MgnOguPncdN/Usvf5AmFbe2oWwczI0lLHlE9UYZd+1nGqpI5hcgkRetuVxf29ukIKchyZ4iIgvotlK/Q",
-	"bOvA2KF15TC6QfaWU2cd16NymqL21EFoBep84v0cO+fseHMLqx701p6dgk5JlBmJbsGcuvahuWHqrQ9/",
-	"WbLmyHmJs0QiKyrOGW8pPlsurcXOh5kIhC6gfvfusnAwyZximfEqDEpk9xCpe1XjWhX6stUTw6HMcGKs",
-	"m9aZTV1Z0zKcSBLi5MbAakqEoogYwK8q7zZ4tJ7mXOBUrZrPieycCAvBQoIlROiOyLh+djVq7Lg/JeFe",
-	"AhX7gryc7XPCnAIPgUo897D/VfGsDwTGnVdBeMKwo/iKw7PtFF/L0Xqp1XqA1u4Ibps91OeCGxw41li/",
-	"gi9C5TOnHi0/S9hAg3gz/W4hWDvDbF6VoqzKjT6pbFmTLMCeZJbFMs7iiAhTmFZBQoQlDNXQnUGbwoxx",
-	"6IDNvPC4wLWq2fUKNi+b9TObebiZh9pa4LzWTMe4cdiP7kiS5ImxIjO2tdPT32UwB45UYkLtsVXpNEim",
-	"IDJ+zv5pllPET7o+Zemd8YVJpxq6EqfWYluqro2umvZ8q6p1r//grR7vP0tRM14lhgfHfYnRkgLLi9Qw",
-	"Smp1RJ4c2Drk5zFtr/STfzv7yD61b9+DLP+dg1Y8rQmL708mp50owonUZTTCd0RdkkDfqZJkaepLBPrx",
-	"+sUb8bcikqmusHczv1G10o/nb9oA22ck+p6SvzIoj+y5b80qaXaBIL0RmHYS6eIKKaI8Fk38kZq+HceS",
-	"tY7q54jTlGMwlGyYKDb98d27y7+hKBeKffKDWxh1S3qFar4bSZ0iXZdbHb016+UXmCQ3OIo4CNEtwvpV",
-	"VLz6Rbx1UhQydlYVFBWPD4OAMtnl4wKNFGZ8vvUP1oEkUt+zI6yvK2lX7XJf9S2Nz7FwVxR4SWag/WZ/",
-	"HLG3sG/jmKrOq/4K0cwIg39a87BXhFQvDSV5zWe+QrGDCh1dVnIqRmuC2UN2dw0D1lrkEN+EMSa0dxAw",
-	"y5IEhXioR+03DAhx/1AE73lp4HKjOGi/y/eNfNTqKtLZ4+Kt9qUj3lFMunPAsyGf7u86rTtVn3Oolgu+",
-	"T7dyC65p8kK3c9L3Wu5G4dnVrxf7C89aOPxzocsfnj3o+mlzJ0/nPIxF085VcBacPHv20+Rocvx3wYAD",
-	"FWFMgUTA/ycTwMWIMg5pshrNiYyz6SjUByr2xosZgYohwSDIeOLU+paDxrXZxyIc4jlQ6UliX10gyZDe",
-	"OA6lcfWK1wdBQkKgwr0r+Ob3Nz8Xa1NGwamlDpyR7l2cibk8yFKgOCXBWXA0moyOFLmxjDVhx8sDW5+L",
-	"QxPDzUGuCWptjbu3a4CnpK1gGeUyKC4zhR5RcBYosooX4QJegzyvXhOvdOU5nEw62j3kbR769VPouMvt",
-	"abHwrueGdWWSdoyPDaw+EIo9jZ02Q3rI0fohlTYzx5Pj9SOKvioPg+CkD1S+5jV67EGvxSrNOrRnqSv3",
-	"HfbxYlIJMzZ1G5oHjdURphSiyp/jTyR66MWkLgP2r7wcIfcY29T0ISJQioWACC0JNiWQS8wJniYw+j/a",
-	"l6e10OV9nvK6r+b5uc25c0xFoq8V2go+C7Bb8yfZcApDW1FnIKXonzhL5Kilvo/kZYOldiNR4Hrtpkqk",
-	"FKS6h//x0QXTJ5S/m6DE5rYJnaOy0vBJBLtFsIdw2ErWPkIpRLxWHKseia9gUsSVJiGtUnUt4qah6JSq",
-	"a3MpCsq71bqQQgFmQhMlS6sUkPIDEONIl3GhawAjSShiYabwqSEZNUpYcqH6KwO+KqXK3gsq+b6oczVj",
-	"1Co62N2zeNV8DF3uGLMkUiKiEN+34LMjMb61W9cmxc1jqhZ2MQz4JN5e8X4NsiVudC6D1AXNEXKD3EGg",
-	"BLop42Ody8k7Cprr3MwXhVyTuZb4WomS7lnAicyvKuZFOkoaTQ8cY3lxquIQTrCsHFYqC0zCW4hMhwn/",
-	"gaDHTBeqwcxenZHRZJUXepEZ0uovBytiYHul3StexAkHHK2UfiCzekwlYw5CCVlul7kKOrRVSvLEIBEI",
-	"7kOACKIu5aZiXp1hfZsjup/T4JRQKM1SSU+vWIbuMNV3A9QG67VZbiGeT5Vt6CAMGu1nzJVM3drHSnc1",
-	"W6pRrBWUA7VtaGT8nALBiswzxkNAGFG4cwhWXKsWUtG3ZS967JADhTudhWxsI+/Q0KqJa5d8XIgtj+jt",
-	"iJwjhSRJYrLOA0RZDWrdmlBzhVICh0YJ1ArsKgNCDlhCNEBAtDGbQogzUT3YikhUsu5ArwH3qaKe+c8U",
-	"EnZXpYTDwuWcGlk3Fll6pDA3TBQLjJ70sF8PKwXY0H/99Wzv+KYjP1jX8rZwfk6WQBGJrC7M1YrSH4pR",
-	"M+VYOMqzvxO2uZJq008cJCewrBZ1zBiv6ywl5hWd9dXGNC2lC01n6LyrBWONok/Ct7ETlIc2LRjtJ6D3",
-	"J5PT3aOdevq+Pdz582RyWot3bPJzj0FCXi/qKdi30Pe+JtaRrt5f1HDe5eA2cPskKV3ZgO5ooYnMppRo",
-	"kfCISe+AQbu7Smr+bJ5qtYjEk5f85CU/ecnftPrpkvreKqbTV34RhpDas/RS9nuYY4RFLXmguZRb806k",
-	"sLOM+hrtzdRTK1xeR7mqwD2uslZMX7eP3HZ+7PuCQNFe1YuqJ3Hrzv2rsEpf+VovBmsFUc01rrYD9gti",
-	"4Q9X+vo6/fYqHYiJbRVLQ/C4xYoBlHC5HXo/G1/WGit7+NFtqJnvwkHJEzd2cmML1krG0/UcBcPdTsQY",
-	"h8W1x5b8s67/1KVfE5H3Cmhq6l8nQnmRL0J7/typn81bTn/wombZWaOid4VkaYtzhfMl23Vv0XrczKJL",
-	"Wjc4LKqHS6bVpfJrNW4UzKYh85Ldqkg409/gmWVJsno0jv3CObocEQ6vqf8VrJaQ6ZJwOTadHMT4k/nj",
-	"oSOMqdTxVPveYGTnQ7YzBM+oPh6wLdcXOIwJNTf5X1xdqMcCMTpC/2AyzsfoCxS6Z6nhRqU4laeZeytd",
-	"FQmXZnnF8S/1bP34vt6z1EJSCZXiTEamb0/J/favoXnf734Uz3aImdrlsorvCnAcpozJ3YWzmMdBwW6S",
-	"akhToNQRzCdD0iLHIB0xaxA9F237oBTvvLv0mLi9sr3ey6U5/ks8valLB6Z8tiTYtrkSEhY/iKIFvnGz",
-	"eNMSNVpNvwb5OZ2aZmtrj19T+mzFZqt7fPnm1fjt1W/FRyNMU7Hvw3pc6syFt1d5znHFT02Wy8pvAHgZ",
-	"7jyG8FZ3mMd0VaK/bDSve+JaGrRyk+0T/zi8lDel9zBS/cMG/i8aKLXdzmjfCVtZwitcZGWX/z4MNec4",
-	"6pNqfW/eNI53ge/CYYlgpiue6ioLXcj8y3EcQrZYgHEnMwEmXgMa6aS90gYYcZhnCeZoigURow4O1cDk",
-	"KVzlmwR9rKQdZzJtj28hvyyPeCnYzSbsDvhQGUnoCJpA98LPXctKz0j7KRJt62w6VXJi6lILV4VxZPyh",
-	"mj9LhHZoiXBc3mahDy6+Tqgc2l41tldqW/uI4Cysj+Qibusa/mYx+iV9wy/L+S8Jh1Amq9qFCd3UQvFC",
-	"o82oqNaw6pe8YjE2ZBpadaelJPMKibTfElKKr7qcZX47hZMS8HDtW/2uyXdqFr42Zctbs3AVfm8+QsO8",
-	"O0Pn02TU/LUtOxsU5Di0s0Xfiw432815RuTU35hTx2XX3Y6ChMqNncqaox78+RpK9tzJjaxeLyshr/Fo",
-	"S2vf5lWvLobKD0e+y9zWa5B9Wcu5BTCOpUzHRZHHY14Bc3r/vgZZ7Wz/GWOX1m76T3fAvvAdMNs+ex2f",
-	"fgtXwfy8/XQTbKuvPHyl18C+1VtdnWJmH34NFqHRm/9x+K6x7JNd+JrtQoVhvz3T4H5V4ck6bPk1iqd7",
-	"wt+ARVFs2CNXndNYFwmmHCKYEVp8W8VcAzQXdFu+cGNktPwsjvnoiUD5RNOVKy6FQOmLeimEEiKEBdK5",
-	"EucaYbcg62xO/tXzbUqTc3BjLCvH7JKT+Rx48QWdRqHfPB5Kdgt0P9XJvZI4105sXfusT1uVrjkocl97",
-	"EkCfAFrW1x87qbF7l7jZzJ8Yf8ookQ/j/CMvbVbQVJCqt/JPq9jPjlMijfiov9AiExJN9TcvlyRSJoTa",
-	"RKiMm86b/RCJeE+JvGRzewS5UZ2JBcNuxwCha141DxQAVyuuRByNytSnx4Cpab6ea2G1r974agvVNvec",
-	"yurBmu8pzmTMOPl3npH9Du6MaZYylq3JexWRM8zdLnNlZtN7iJCXidlj1/p6I6TExlfmVdzK7BQ4k6u9",
-	"yvYlclPlAuiqJp2Z/azitkF5l59Ibvkl5ns5v8sn2kslpt1PiDknECGWyYpQGyelubEnK+m/QVI5B6wL",
-	"UqfM3rFkuAAh8BzGn3BCsOgo7DzneCYRRn/8fqlP3HP37A7fgkBZqu9dm9PZTLnF6A98C0NGh5cv3tij",
-	"brVC04pOVy0C/QdLlCP5m4GwjyQ75fxmseIqiT2IZ0joI/7KLqrfiaJMwtR8lN4jzXra/fuQ+bm2QmaW",
-	"mhtciYJVfpmjm2+u/rFGVIft71iiON50DV76eefSVHjo55WuhmfjcfFNu7PT09PTQNHTTt3gQDw3/mO4",
-	"UOYi0S5+XhMkXM27gKCp5vPh+pZJ1/i8/U+DiUDiSsOA8oJQ8UH9MhKiM9YBxO2kE4TbiegYnBegdkyQ",
-	"l6K2T1LUZXXMUpT5tE9z9etF5wy3pGNwJW3ZNU0egnRNZUxZ5yRWQbfPcn39S+cMIu7CaHmC3okSfTja",
-	"Po2j19GPf/x++beuyZT0tU+l71p1jNYXrR4+Pvx/AAAA//97jPfHqZoAAA==",
+	"H4sIAAAAAAAC/+x963LcNtLoq6B4TlWy9c1NN1vSr/XasaOK47gsOdlzsqkpDNkzgxWH4AKgpFmX3v2r",
+	"BkASJEGKc7HsxPqxWXlIAo1Gd6Pv+BSEfJXyBBIlg/NPgQCZ8kSC/sc/aPQB/pOBVPivkCcKEv0nTdOY",
+	"hVQxnoxTwWcxrP7n35In+Azu6CqNAf+MQFEWB+fB1RKIMCMRJglLbmjMIsIFWTEpWbLQT5mAiKRU0BUo",
+	"EHIUDAKpqMpkcH48mQwCxRSOi2CRHK5BoNYp/rhUKpXn47GdfhTy1RiE4EKOZzQa2tmD+0EgwyWsKML3",
+	"fwXMg/Pg/4xLHIzNUzl+b5YV3N/fD4IIZChYiuutzX8/CF5zMWNRBMmOSHoRhiAl4meej4h/EbUEkgp+",
+	"wyKISCgggkQxGtfwc1TipwSoD3aKyfaCm9fuaBeJApHQ+BLEDYgfcL5dcZSQLIG7FEIFEdErIDwMMyEg",
+	"cvFx4tJLDgYxcBADSB/cMPvlUOovh/rnveDJD9P9IHjH1WueJdH+OA4iIkDyTIRAbqkkCVdkjlNUCei4",
+	"RNg7rogBog+SEq6Gery9IKac2yDjAqdbQaJgd5Qga2VJiF8QZhABCZ3Fddo5qKLCBaEvQpjzzb7QclEd",
+	"82NCM7Xkgv13Z9T8P56RiGuELOkNaJGTAIojKtYkBaHFNE9qMsfBUwWYPkjK3A/2gaEKBPfFiPogexGu",
+	"4Gea0AVEL0EoNkfkWBS4YyDXhDyZs0UmNPYIn2tkrMzH5MXLn38goTPEIEgFT/EHc2SG1eG71vLPk8mZ",
+	"A80rqigiwhlhamDZcKCX5qP7QZByqaZLzq81aEzBSj6IYC7Vj/qL+2IXqRB0HQyCu+GCD/G3obxm6ZBr",
+	"rNF4mHItJ4NzJTJAxCsu6MKFvtfUtWVcmlG2huO+JE7/9r9lrv7AZ/+GUJ/nHW/3Ixgax8WPELXSjmwQ",
+	"T4Qk4J1DrpOQGCwO+mGzheQfHZuvWQy/orZHzXLqq7PPQBJK5iyG7yTJBVkdPVQsmt8jd97QOAOiOFEg",
+	"FaELyhKpBkZxymYxC8k1rLUuJdkioSoToF9FZBbiMDgM54fH0YyezGcTejSBw2dwejQ7pOHJ7AwOz+Bg",
+	"dvDsAE7Cgzl9fnx4As+PJsdHR88Oz45OZ2enh8fl6qUSLFlswDEsuQGhpgJkFnuozDzW6zGv5GIpXEJ4",
+	"7S4CxyvAmHEeA0024dwcPdNMxH5cf/zwNp8dj49wCZGD1Ydw7DsQQjoKhRqtWMIkW+yARQVtLIofI9Q3",
+	"BSEitaQg5lys8EyDJFsF578HckkPT56ZQ04oOb1laqkfR8XfAhZwFwwCCy+id5EimCHX//zDXW8x3nZr",
+	"criuxkceTrtI5vxlxZirIuKV/tcMJLldsnBJSqFBqIBcEyKIG5Q4Wi9t8KB9a1q1GosF/x78qFT6AQr1",
+	"IxgElxAKUNL98Q9HiDV3K6ErKCg8n6dCRs1Jqvjdg5S7XEsHmS3o/hForJbdqAa1BGPEoVSiSQioey71",
+	"l2s0gSNYCBpB1MB18aCK4RuaxWocwRz/PxgE76/ZBgjNBy0xizD8imORMGZ1TNcn2w+eSyXSJ2IMbnKA",
+	"c6w5XGqRFwxKFFW4zsFok+9wkOENFYgUiaOVG3mpgfqxGL3+5JUz2zYEZcbyEdN7Gl7TBeCETZzgr4TO",
+	"eKYIJegtiYGk5gN3rz4FuKTgPEgWLLnTkirlwXmwogw55AaENOMdjA5OR5Pgvk5v5vP67O8c4vHMWsy2",
+	"rdQ2UNZn/QApl0xxsSZzwVdWZDkwaFNW00YcQ1SByC54W4AKRDVUFfOgCxkWtXuQ96+SuUsU7TTzMdXq",
+	"kw+FKhNJKYLkWipYkSy16pYAQm8oi1GgE5pEFsd2WZKENCEzsO9HVUrTP+KH0/z14Pz3jQnwj0FgoZkW",
+	"kBTbUKVN34T1BaMKiptTrEAtqWouo58d5OB+eynnWV2TuSNjDRCmyQp3RUC5S8WX+9LyHBqrUVA7jcmL",
+	"gtFasY5WTyGv8dViH6qksxPBNOjiYWrwwvNoNNDEtoNLH8Kv2YWUGQj/MSBWRns1p4FWkEoT7Dt0HuO3",
+	"o6Zbgq9WPJn6JbwxYvGFiqZQjtU49CUIRuNpkq1mIPwDmleIeeXBIfU5YCIAWg2vjD+ogP+H49g1mPKh",
+	"sXBjNIDTPxvRgIwmsoTQuQJBqMYlWVJJZgCJAbaplYWrqB2FKEbxf0wr1YvqPDucSe0b5+4Yunr2cfaU",
+	"2POh1rrgGtC8IAtIQLCQ/Hh19Z5Yv2OV+Vs9jpDwbLHU4Q2mkEAKte88GNMw5FmixgeHR8cn45VcyDGd",
+	"hW3hoQeH7nBOItByzDM15POh+aKpJ+WLaCJgma1oMhRAI32owl0a08RwrEwhRD7Vbgr0RNuwRRIW2+fB",
+	"GC5GEPOiIjMa5/bD0WRAZpmyRxyXSpKTiZdXS0Q24f344YIImIMBQ4/FdHhpzvTxCSXY/cBt36qmDGmx",
+	"AJCoNQGZF0jIo8rhZ/bajIb0uwAdN7G731yjXHKhBvWtkdlqhb7t6lq0o6CO/17EVDeItsF1KxB9KbUp",
+	"Vu8HgWMnv2HqQvlY92XFhYkCzCKfz0uTY8EUEeVg2h3pPWiYxwuzpLKw6PJBjBG6YlWL8+iIPjuO5icn",
+	"B8eHJ/MwPDmB4+j4kB4cnz4/O4nO5ocn89OzaCd3m0eCsygHz67LMSe5Qs+k3GHGmEo1pUrBKlV+o1d/",
+	"QVhCFCsFeky1R9PFej5IxXM5OTweTp4NJwdXB4fnk8n5ZPL/g0Fg1AVcAFUwxHF99KpBgzw22wTMBlkf",
+	"AmhA8L9r1GKZc4jKTEe151lcgTjkWRyZSCSocIm09fcFU8tshsR9bl8c55gfLZg6JxjfQYaxExcKw47b",
+	"YkHcfFvKtbkI2d/OJHCnpuj53wA0/IagAhtlMUQ94TrYDK6UqmX/iFJV/rynaoljrOjdhfn6YDLZUrf+",
+	"YgEuPbxvS2aCJuGScEEUXVgZL42XHiLCM7VPP4UoXCRNYBwvvSNtnS9cOHow3w5gdnn6ylPGFbzWy5cl",
+	"1wm/RQwhD2g+n1Nm7KYbEMboYTyZ2l+rjvf8kx4OwOYJadx9HwsA2t64zGdpe+F1DnDbC786C3ldrGMD",
+	"VxWbr6dFxMWDZtcB7RCiPXnJKpMK3SM4BERktiaUKJHprJFrWAf7cDO8YcpZ/oXZ5YZd0cSQ9MdcX8Rx",
+	"EVP16SSbxFV9324h1fSa9uAiaGJKPowqLVI9Cicyr44vMAGh9qSWEiH3reYiyhES2j4BEvOQxtZn2ESo",
+	"N9JmwgtSMWPyWKWWqmVjBlf8/B6MQYVj7QIy/x1h8LwS0thX1IHJacREN5PkANMSccEuIVXhOb71HAJi",
+	"qtgN5BgXnKvmFjV97S6S9mDsV2lOE1M3yWHwbQczQtt2PewInQMwRbuhOZEJq5JWoyLPH/gqgvvIEtNM",
+	"MI9wdrjF0N2tdgCb9dCISB06RRMW1eUZkFvBFCR+DgpBKDlOr9nQRNSDgfl9SVPB79buk07e2o9qhtJn",
+	"elNJ/OiSp7Xw9uZGWkiHZs1PJtp+TLRbwZOF64QZkCLx9vDujiy4IieToyfr67Gtry+U1KcTiFuOMrt8",
+	"K64UN9JLHwCYuFZRKbRNkasVJU5SwaNxNHsUQ8MYBxbcvhZHIZw2tTd2jDrfVKPOzXOOUElokvBML2q2",
+	"dtJ3SKaLK/CHH67oArXBt1Sq4c88YnMGEVkCjUBUNuJfvT1w/wr2oX/Ucnl6mAe5AtLDPvAqG7WApLEO",
+	"fv9UHNTBeTBWq3QczUY6aTrnTA1+YH8yxDNNtfJdUK8nQrnPpE4PEvaS66TXsqHlYT6SO2iCVlx4dEF3",
+	"d3bflFI05Ox8/w2om59L26xvw7ZwOttXB9SNaZavuTDsOPemAQBMPaESnrTLJ+1yX0gHKmEKdynzuW+b",
+	"0GnQ8BsjQaN1QlcszFlWjwNyf+BVlcxaKK8tgcLk6xqdpMg2LUUOkkS4pMligyys/TjrTcw1mhps6Vlp",
+	"FDHzwfvKQdAMLDd35qdfyc0hsXqZzHOf7OCFBClZQEASgYBIZzIO0K1a6GlMaElcy5DCc4umLDg/GBSH",
+	"2PnRff083ihUYE+raaue2VhZXavHDXfWlI84IBPk+VJ3LlZy1IjTb6DMuyf5t2pzRBCD6mlpPKjP9Vfn",
+	"5O61Tjkz/CVVbwdVj6t9Y60wC+EtX3jTfVMBEmEmMV8QXPuI/EDDJYFEibU+SZZANJhElC8X6njMEn26",
+	"6C+r+xTrGX8PXsOMTI7I4cH58en5yaHhriWXaqT/ijiGNMk1iATic3L5w1uWZHfn5HByfErojaIzo0PL",
+	"mCs5IAeT44OjAyIyDDIGg886epM4YovFno7Qye4brfete1tfeSkWn+ht0fVVVBv3LAQfL/019qqVk7s4",
+	"1OWO+0FQaLRJFjuZ5V652dgRuWwW1HZxnPLKxkIcXl7+WKsadjYOH1wZCISkQaUCGN3iKBOXfAXjTIIY",
+	"j6Rcjlk0FTJ38qMxE+CzIX6oIyksCVlKY00HNFrpNIOErSKKR4kpifwJ1t2DpxkejILrd1brof5rECgV",
+	"B+fB8enSY0SDUNM8/W3bE9Utf56zuCXrFZ/gEa8tVHO+mgB2qAslawZrF/b2aUIiZLkZyWSx+y07X9mz",
+	"bcFw97pSJdbY9pb6MB10IeUwRcsRB6EVqPOB9xWTNeQ4vYZ1j/3WOh5Chxzl1vYKvuqz54aot0W24D74",
+	"3AIpXS5HLKuU0Djss+XUmu18mIlA6r45V1dFxk+RDlKBAVl2D+5ar2h8UIS+atXEaKgyGpvTTcvMpqys",
+	"SRnBFAtpPDWw9rHmShqt50isaIqz5mMSOyahUvKQafcelv3WKy1Gwfa2GNwpSOS+IC9H+5wwpyBCSBRd",
+	"eMj/ffGsDwSF+2Eec+oIvqLUYzvB11IIVrzTB7S9Z5uYKpYNymNqpF/BF0vUM8ctvLlF7c9Ld6vj2wlm",
+	"82gWnipT7RZqmZOtwLqNytJOZ3LCpKnWH/XzWW0K2gzmXEAHbOaFxwWuVcw+LGDzbil+YjMPN9NQW/va",
+	"PHhMo6OojrFbFsd5SKGIKWyt9PRXGUx5TKIoy/PRSqVB8TLzcf97lu+If+v6dCPqtC9MTM3sK3MqA7fd",
+	"1Qetq+Z5vlWzIq/+4G0a1H+UolVQdTM8OO67GS0usLykmpK4VvXq8YE9hPzcpu3lfvIvZx/ep/ble5Cl",
+	"+0S8NG0iGti5bIZ4a30lqgigUSRagzP2YX4umYEgifRCDMr1qHlxXLwmmQR/txmtlY+c6qnz08PJZAcB",
+	"hJUo0xWoJY9aoM/UkpgXXFgRQhQ76KBj1WgSTdMdTYTuoKant0dbV4+NvfQseoh89fYZR+eFDr8rfg1J",
+	"r6+u9JsuybpE2E2jHRkiDsO6RLpJ8nj9u16M7AK/B/Z1F9uKjALxXpYtdL8XafqBF3kZUxYRwZVzqnQz",
+	"s46XtsWQ86cFNdqK1mKqCmGeHp+ePaOTcHhwdPx8SOnZZEiP58+GNKSns+cnJ6fPT3fhXh0tbWlH5o+8",
+	"NsDNI67VoHfEwXaV1E/94djTbUP0ptlXT4hLSIuQXV79YaLcUhE72yOlEdjAun4D1eoYLHkliyrEFZDK",
+	"3pMkgoTtXu2n55/qRWycFAC2cawfYwfnJ2ebYyxnsc2hKb5sAnQyPDzdNuV1E4DqBlxJdUwa+ExDMaak",
+	"yY1tNKwrMXi8McB1MViIuTY5eJUfOrWOEJV+U/pk6iPwympUiLpLSuzpj5wY8wXqhiwp2kSY+ZbUlRzR",
+	"LhUnm4s3A4IVaVaW2f64AhK4hci/ZQdXB0ePICYQZyglWEK4MBDlQkND/m0JjJgvWLJTQpMZYb+CX+8K",
+	"jXdMtDJjdKTKn2yRZBVlXdyg3yH5OwikhJAnEboMidD2XplVbWHWCCxosQrx0bPJxIFvZ7edmcLbOsoV",
+	"MYaFUeoW7+8gQrzRBqXzRDGui9wXszm422kBKNDn4uTw+T5xUhf7V1YGNGS+vy9yq1H/QAz37mRy1mnP",
+	"01hNbU1xsxiz9Bfovu+67k+/TL6/fPFO/q0Iu1Vn2LtPeqNGUN+/fNcG2D6N1I8J+08GZTcU4ZuzujW7",
+	"QJBOJU06N+niPcFNeaw98YcVcTPwyUOhi88RVETGHio+jJFMv7+6evu3UkLukx7cnlPXrFdc0dc1vZOl",
+	"63yrQ40N5kW5Fk+t06mbhfWrpHj1i4SWWNEjrjN9tmgmh8o9V10BGUgixIwvEPSdjXYwpe8CYLxv3MPO",
+	"2hVr0X2WP8fEXSHLt7Wz63PFKDcOANZp1d98LzPM4B/WPOwVzqt33WN5O718hmIFlX10SclpxldjzB68",
+	"u2vM6sETOaTTcElZ0jtiNc/QM0iH+qv9xqxC2huKkO55ahBqo6DdfqfvG6bD2TEst8fJW8+XjuAcEunO",
+	"0bkN6XR/V364Q/VJmmy5hOTp5pCCapq00K2c9L06ZKNY4vufLvYXS2yh8M+FLn8s8V63pjTtznWA3pxo",
+	"WrkKzoOTZ8+eT44mx/8jOQhIZLhMgEUg/p5JEHKUcAFpvB6VbauCvElqYL4gxSfBINC3WRThwPKjcW30",
+	"sQyHdGFDMzX97/0FUZzohdNQGVWveH0QxCyERLpt2N/98u6HYu6EJ+C0qQycL902xxPTl52nkOgCoOBo",
+	"NBlhFVzRc218c2A7fdDQ2HALUA8YtTZ64b3ZyFOEW5AMqgxIZfpnDB8FuK0SL4B5A+pl9Sqbys2BGF5t",
+	"v5Iqv4qq351PHffNeK6Buuq5YF1GoxXj48mkDYRiTWPnKkT9ydHDn1SuwjueHD/8RXH32/0gOOkDle+C",
+	"Pf3tQa/JKheKac1SN0V1yMeLyWAQKGqKDDQNmlNHmrz9Kn2OP7HovheRugTYv1Z8RNyca1t4xyRJqZQQ",
+	"kRtGTaXzDRUMnWKjfyV9aVozXX4XZV6k1Ez2tsEPQROpvYN54ZkF2C1VU3w4g6EtBDOQJibGMGopS2N5",
+	"tVsp3XR8rNTaTUlDyUh1Df+PR2dMH1P+YowSm4hVRPz0Nj+xYDcL9mAOW7DehymlXD7IjlWNxFfdJ5eV",
+	"i8xauepSLpsHRSdXXZp+0+7NOdy64fWUmpfWKRDUAwgXRNcckUvI0zEiHmaITw3JqFFvkTPVfzIQ65Kr",
+	"bMvlku6L8kzzDc6ijd09s1dNx9C1eUseR8gitsHb9ulhJvtla7WujYubOZUt5GII8Im9vez9BlSL3RjH",
+	"rYzmMLlB7iBAhm7y+Fj7cvJbjzX5oEnnSQBiC83xtXoafR2MYApktaIEudHc02dOXp21lgpGVSWzFk9g",
+	"ppNN9OU9/uxVzzFdiAYzenVEnsTrvCqJzQmhDlhO5g3SIo0F0EjfrsXmdZtKLQVIZLKyl0sjqMUkgbsQ",
+	"IIKoS7ihzas9rB9yRPdTGpx8f5QsFff0mmfklia6pN3eL1cpJHKrxnyibEMFYdC4u8x0u9eZGpa7q95S",
+	"jWItoByo7aWL+oUSwbjNcy5CIJQkcOtsWHFjhVS2z6xvLfrbYRllbSwjD222SuJaWyIXYksjejkyp0ip",
+	"0Pukvc4DkvAa1Pr6ZE0VKAQOJwfNKd5VPggFmB4JwPRhNoOQZrIa2IpYVJLuQM9hs0DMP2YQ89vqTjgk",
+	"XI6pkZUH4vWX0jRGQBIYPclhvxxGAdiQf/3lbG/7psM/WJfytsp7wW4gISyysjAXK8RkV8kMFQtHePZX",
+	"wjYXUm3ySYASDG6qFQjzWsrLaj1ENq/IrK/WpmnJs28qQy+7romu7egT822sBOWmTQtG+zEoOt53t3bq",
+	"7vt2cwcdqzV7xzo/92gk5MWNnupyC33vXPQOd/X+rIaXXQpuA7dPnNLlDSDhZshscgm+42OT3gaDVneR",
+	"a/7ZjGq1sMSTlvykJT9pyX9q8dPF9b1FTKeu/CIMIc3b55VVaw8fx5inW3UeaCoV9nhnStpRRn0P7c3E",
+	"UytcXkW5KsA9qjKLci7/anXktvhx8+x3bq72ouqJ3bp9/2hWIZ31YIMHGRHHGlev7fczYqEPV+7fdzp+",
+	"QoKhNF3Yo/sc5TdMetRiJABkrnLaz0iXOJkzk4ce3buK81WEzhdP1NhFjS1YKwkPSaxGcOaq/geNMLeU",
+	"IScnVDTM59qRm1/qb0O+zkv5E8LqRVt5FVOlVoto70qxgFKfuaVMa2zofsFjozJSUbU9aifz4n7/z0ri",
+	"dpaW3IdlpZgtR9Ojkfb2lNoa/GxZURvZXU8w9aBoDdUS9tBpxzrjcCLzfopNBeGniUTj5YUe7iG1wLyF",
+	"hJKCQHdYkSrvzFE57qXiaYtOT/Mp24/8PERpR9GZ1BvEKOtWurm8Gs0pjRuEGbVjltxwDOSUdVrx+k9A",
+	"TXtxDeeIcGgN/1WQWsxmN0yosel2KcefzB/3HdZzJX2s2iWYEjseMcMQkSU6KsUTqz2FS5aYik3MRhNZ",
+	"gkGxEfkHV8v8G123gxtpCEif1xJUoSR3JcK8NdMjxb/So/Wj+/ot5BaSioW+zFRU6w6d/zU07/u13uLZ",
+	"DqZ6O19W8V0BTsCMc7U7cxbjOCjYjVPN1hQodRjzSX9p4WNQDps1Nj1nbfugZO+Uhtd0AXKsz5w4hqhV",
+	"j3lros4xKV4l+del3lw+w5iNaQouFay+k/nLVrsXzZPovR3tIh/jDajPqWg05vPpG6WpUCy2usZX716P",
+	"P7z/OZdgZrnfyOnxVjvMmvTgUFzxU5PksjTSHtw2gnuJjkddq0+TdYl+JDPzqb7l3u5BKzV9NLM8Di3Z",
+	"yXyEVDcD7Or1cugNZbp/sxbb7YT2jZCV3fg5L7DUl6C0kdTDw//RvGnsvQLfhcJiu0Y1RBa5cJpGYBUx",
+	"GHUSna7aTVA070K1hAhYZDEVZEYlk6MOCtXA5JED1E2CPqek/c44eB//hPyyNOLdwW4y4bcghnhIQofR",
+	"hDtKk1y19FwUbM4668VXgpl06EJV4YIYfaimzzKpFVomHZW3mV9mp2XGY98rtfs9LmsfFpyF9ZFUxG1V",
+	"w58tRr+kbvhlKf8VExBiL0DWMLQ0iTcuZZHV1Gn9kpctxmabhlbcaS7JvExiqDvV0abqdJb47RCOS8BD",
+	"tR/0u8bNrknYdFfcnoSr8Hv9ERrm3Qk6HyZLzF/bkrNBQY5DO1r0rchws1wiXCRsQ6nj8o6iDhdspVCs",
+	"MueoB32+gZI8d1Ijq1WNJeQ1Gm25/qhZYdhFUHlM7pv0bb2B2ja3k5ZTfIKlk+MitWjDnMfaLaBoLONI",
+	"uXBcsN5ViM5dSW9AvWGqvLXqM5ox7qzulG1FiH0W/41QmxtBqu9zfgtXTn35JX3d9Ldt4m3LVbQ+oPZR",
+	"W+il1M3yCzQo1b69XOkL7b76RIEmxzwVB37p4sAN2Guc337vNwfz3QXZzUeErVYQMaogXg8ISyJIIYns",
+	"JaFMyeLC/D0zHCrNluwucSEbc51J3LGL/Euz4FXjukq9/vJaV2dPn1iyjSWb7LAJ7y2VSjfRrfbX1KF6",
+	"SFVv138c0qzO+dTV4Qt3dehJp3+G5g5+2n7q7bAFc361uttfUhWrsdkWutiPV1fvvyplLCekvWtj2LHv",
+	"T1Bb2JOnnpSxL6+M2Ydfgz7WuJj9cSi0Me2TVvY1a2UVgv3zKWYOtT3pZttx6ZNr7avW53IGRTLskYRT",
+	"OcBSARHMWYKhKkPeuq2OqU+opGFop5QOahseta8rrmWwKZaYM+dGC/tCwVC68U0KoYIIc4Z1ENhpy/Ow",
+	"kmcpcrtS3xxczCJx84eVYIuF1X70QuqFc4vlML8KZg/Vvr2i05dO0JBY4WLgb616NRlw7mtPDNihuxFq",
+	"MeWQexe72ZQGOf6UJUzdj2O+6NTdTEUmvmU7rtgkJvzasA/+RVaZVGQGJBX8hkXm8iaT4aGWTeXt0gLx",
+	"MWHqLV/Y3MqNEugtGHY5Bght/GgaKACulpLIZTQqczo8BxgO8/W0WTGQIoJMy2lPrR5f7D1G34M0PyZY",
+	"LMYF+y9E3wS7vQFLUuZka9JeheX0zx08V6ZseLOj8voXm09an29EkG189StFl6NOhjNJKO+zfbHcDIgA",
+	"Xa4B0edmtw3qVvyb5ECHEO8jMTEfaC8lZnY9IRWCQUR4pipMbZSU5sKeTkl/R4ZKgmOdkTp5Vl+HPM7v",
+	"re1jJto7Ck0hJk2igSbF/L5YO9KgdgVkcXmszC+ZxfSPyqW5TW7Wj7Fi3Lm/9zOeQ427gh/0xzXW8A26",
+	"ubuwURKeJrMWquvvodgX6fluJK/7Mcqi8U08GVWS3czgqUDTcjn31+2MqNxn3ceZ7S75SbhvxGwtt+r3",
+	"ZjjrhBiyaCx4d63HB/1cttwLzpuNFtywkoAFFVFsr+9nSnpa7I5I2YBvRqPCumEVViz6+may637yPTIy",
+	"+jAMOefXCX8o71l+4uy+nO2Gq3L8PTG7n9k3Y7V2hr/l8XAFUtIFjD/RmFHZ0YngpaBzRSj57Ze3ukQs",
+	"d7vd0muQJEt1f1pTTpSh2CG/0WsY8mT49sU7W5uFMzS9I7N1i6H2G4+RuX42EPbhJqftkZmsaLllAEOL",
+	"QdekVVbBK9yWcAUzzq/97KaH3b9vMC/EQmRmqel0FyOs6svUGvzpCvZrm+rQ/C2PkeLxfT2tj3bempJE",
+	"/bxy+9P5eKz95Esu1fnZ2dlZcP9HMXSDAqlpBUTDFRABsXbd5kWs0rWoVxDcD9o+1924ur7Pr0loEBEo",
+	"WmmsXDZSc5rg2EHwvQ4griedIGBXk/aP844JHQPYVzoGKQqJO0bJ3+kYBi9C6xrhmnV8XAlHdw2Tu5a7",
+	"hjIuis5BrOHdPgq2Nu0aQS67MFqWfHWiBF/rGKZyaneMYw6b9nGc84F8/9svb//WNRhycftQurddx9f4",
+	"PLj/4/5/BwCoc5xwvdwAAA==",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
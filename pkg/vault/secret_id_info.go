@@ -7,6 +7,7 @@ import (
 )
 
 type SecretIdInfo struct {
+	Accessor        string
 	Expiration      time.Time
 	CreationTime    time.Time
 	LastUpdatedTime time.Time
@@ -26,6 +27,11 @@ func (s SecretIdInfo) GetPercentage() float64 {
 func ParseLifetimeIdInfo(data map[string]any) (SecretIdInfo, error) {
 	info := SecretIdInfo{}
 
+	accessor, found := data["secret_id_accessor"]
+	if found {
+		info.Accessor, _ = accessor.(string)
+	}
+
 	// parse expirationTime
 
 	expirationTimeRaw, found := data["expiration_time"]